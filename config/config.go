@@ -1,12 +1,18 @@
 package config
 
 import (
+	"errors"
 	"fmt"
 	"log"
+	"net/mail"
 	"os"
+	"strconv"
+	"strings"
 	"time"
 
+	"github.com/glebarez/sqlite"
 	"github.com/joho/godotenv"
+	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
 )
 
@@ -16,70 +22,418 @@ var (
 )
 
 type Config struct {
-	AppPort          string
-	DBHost           string
-	DBPort           string
-	DBUser           string
-	DBPassword       string
-	DBName           string
+	AppEnv     string
+	AppPort    int
+	CORSOrigin string // dipisah koma, contoh : http://localhost:5173,https://app.example.com
+
+	DBDriver   string
+	DBPath     string // file database sqlite
+	DBHost     string
+	DBPort     int
+	DBUser     string
+	DBPassword string
+	DBName     string
+
 	JWTSecret        string
-	JWTRefreshToken  string
-	JWTExpire        string
+	JWTExpire        time.Duration
+	JWTRefreshExpire time.Duration
+
+	OrderingMode    string
+	StorageDriver   string
+	StorageLocalDir string
+	S3Endpoint      string
+	S3Region        string
+	S3Bucket        string
+	S3AccessKey     string
+	S3SecretKey     string
+	DownloadExpire  time.Duration
+
+	//quota attachment dalam byte, 0 berarti tidak dibatasi
+	//MaxUploadSize selalu ada karena dipakai juga sebagai batas body request
+	MaxUploadSize     int64
+	BoardStorageQuota int64
+	UserStorageQuota  int64
+
+	//jalankan migrasi yang belum tercatat saat server start
+	AutoMigrate bool
+
+	//email verifikasi dikirim lewat MAIL_DRIVER, link nya mengarah ke APP_URL (frontend)
+	AppURL                  string
+	MailDriver              string
+	MailFrom                string
+	MailFileDir             string
+	SMTPHost                string
+	SMTPPort                int
+	SMTPUsername            string
+	SMTPPassword            string
+	EmailVerificationExpire time.Duration
+	PasswordResetExpire     time.Duration
+
+	AdminEmail    string
+	AdminPassword string
+	AdminRole     string
 }
 
+//APP_ENV production mewajibkan secret diisi, development boleh memakai nilai default
+
+const (
+	EnvDevelopment = "development"
+	EnvProduction  = "production"
+)
+
+//postgres untuk production, sqlite (file lokal) untuk development dan test tanpa database server
+
+const (
+	DBDriverPostgres = "postgres"
+	DBDriverSQLite   = "sqlite"
+)
+
+//mode urutan card & list, array memakai kolom uuid[] di card_positions / list_positions
+//rank memakai kolom rank (fractional index) di masing masing card / list
+
+const (
+	OrderingModeArray = "array"
+	OrderingModeRank  = "rank"
+)
+
+//storage file attachment, local menyimpan di folder STORAGE_LOCAL_DIR
+//s3 bisa dipakai untuk AWS S3 maupun storage yang kompatibel (MinIO dsb)
+
+const (
+	StorageDriverLocal = "local"
+	StorageDriverS3    = "s3"
+)
+
+//smtp untuk production, file menyimpan email sebagai .eml di MAIL_FILE_DIR
+//log hanya menulis email ke log, cocok untuk development
+
+const (
+	MailDriverSMTP = "smtp"
+	MailDriverFile = "file"
+	MailDriverLog  = "log"
+)
+
+const defaultJWTSecret = "rahasia"
+
 //function file untuk load file .env
+//setiap variable bisa juga diisi lewat <NAMA>_FILE yang berisi path file (docker / kubernetes secret)
+//error parsing dan validasi dikumpulkan semua supaya bisa diperbaiki sekaligus
 
-func LoadEnv() {
+func LoadEnv() error {
 	err := godotenv.Load()
 	if err != nil {
 		log.Println("No .env file found.")
 	}
-	AppConfig = &Config{
-		AppPort:          getEnv("PORT", "3030"),
-		DBHost:           getEnv("DB_HOST", "localhost"),
-		DBUser:           getEnv("DB_USER", "posgres"),
-		DBPassword:       getEnv("DB_PASSWORD", "password"),
-		JWTSecret:        getEnv("JWT_SECRET", "rahasia"),
-		JWTExpire: getEnv("JWT_EXPIRY", "60"),
-		JWTRefreshToken:  getEnv("REFRESH_TOKEN_EXPIRED", "24h"),
+	if AppConfig == nil {
+		AppConfig = &Config{}
+	}
+
+	l := &loader{}
+	*AppConfig = Config{
+		AppEnv:     l.string("APP_ENV", EnvDevelopment),
+		AppPort:    l.int("PORT", 3030),
+		CORSOrigin: l.string("CORS_ORIGIN", "*"),
+
+		DBDriver:   l.string("DB_DRIVER", DBDriverPostgres),
+		DBPath:     l.string("DB_PATH", "./project_management.db"),
+		DBHost:     l.string("DB_HOST", "localhost"),
+		DBPort:     l.int("DB_PORT", 5432),
+		DBUser:     l.string("DB_USER", "postgres"),
+		DBPassword: l.string("DB_PASSWORD", ""),
+		DBName:     l.string("DB_NAME", "project_management"),
+
+		JWTSecret:        l.string("JWT_SECRET", ""),
+		JWTExpire:        l.duration("JWT_EXPIRED", time.Hour),
+		JWTRefreshExpire: l.duration("REFRESH_TOKEN_EXPIRED", 24*time.Hour),
+
+		OrderingMode:    l.string("ORDERING_MODE", OrderingModeArray),
+		StorageDriver:   l.string("STORAGE_DRIVER", StorageDriverLocal),
+		StorageLocalDir: l.string("STORAGE_LOCAL_DIR", "./uploads"),
+		S3Endpoint:      l.string("S3_ENDPOINT", ""),
+		S3Region:        l.string("S3_REGION", "us-east-1"),
+		S3Bucket:        l.string("S3_BUCKET", ""),
+		S3AccessKey:     l.string("S3_ACCESS_KEY", ""),
+		S3SecretKey:     l.string("S3_SECRET_KEY", ""),
+		DownloadExpire:  l.duration("DOWNLOAD_URL_EXPIRED", 15*time.Minute),
+
+		MaxUploadSize:     l.int64("MAX_UPLOAD_SIZE", 25<<20),
+		BoardStorageQuota: l.int64("BOARD_STORAGE_QUOTA", 1<<30),
+		UserStorageQuota:  l.int64("USER_STORAGE_QUOTA", 5<<30),
+
+		AutoMigrate: l.bool("AUTO_MIGRATE", false),
+
+		AppURL:                  l.string("APP_URL", "http://localhost:5173"),
+		MailDriver:              l.string("MAIL_DRIVER", MailDriverLog),
+		MailFrom:                l.string("MAIL_FROM", "Project Management <no-reply@localhost>"),
+		MailFileDir:             l.string("MAIL_FILE_DIR", "./mails"),
+		SMTPHost:                l.string("SMTP_HOST", ""),
+		SMTPPort:                l.int("SMTP_PORT", 587),
+		SMTPUsername:            l.string("SMTP_USERNAME", ""),
+		SMTPPassword:            l.string("SMTP_PASSWORD", ""),
+		EmailVerificationExpire: l.duration("EMAIL_VERIFICATION_EXPIRED", 24*time.Hour),
+		PasswordResetExpire:     l.duration("PASSWORD_RESET_EXPIRED", time.Hour),
+
+		AdminEmail:    l.string("ADMIN_EMAIL", "admin@example"),
+		AdminPassword: l.string("ADMIN_PASSWORD", ""),
+		AdminRole:     l.string("ADMIN_ROLE", "admin"),
+	}
+
+	if err := errors.Join(append(l.errs, AppConfig.Validate())...); err != nil {
+		return err
+	}
+
+	//di development secret boleh kosong, tapi jangan diam diam
+	if AppConfig.JWTSecret == "" {
+		log.Println("JWT_SECRET is not set, using an insecure default secret")
+		AppConfig.JWTSecret = defaultJWTSecret
+	}
+	if AppConfig.AdminPassword == "" {
+		log.Println("ADMIN_PASSWORD is not set, using an insecure default password")
+		AppConfig.AdminPassword = "admin123"
+	}
+	return nil
+}
+
+func (c *Config) IsProduction() bool {
+	return c.AppEnv == EnvProduction
+}
+
+// Validate mengecek nilai yang tidak masuk akal, di production secret wajib diisi
+func (c *Config) Validate() error {
+	var errs []error
+	invalid := func(format string, args ...interface{}) {
+		errs = append(errs, fmt.Errorf(format, args...))
+	}
+
+	if c.AppEnv != EnvDevelopment && c.AppEnv != EnvProduction {
+		invalid("APP_ENV must be %s or %s", EnvDevelopment, EnvProduction)
+	}
+	if c.AppPort <= 0 || c.AppPort > 65535 {
+		invalid("PORT must be between 1 and 65535")
+	}
+	switch c.DBDriver {
+	case DBDriverPostgres:
+		if c.DBPort <= 0 || c.DBPort > 65535 {
+			invalid("DB_PORT must be between 1 and 65535")
+		}
+	case DBDriverSQLite:
+		if c.DBPath == "" {
+			invalid("DB_PATH is required for the sqlite driver")
+		}
+	default:
+		invalid("DB_DRIVER must be %s or %s", DBDriverPostgres, DBDriverSQLite)
+	}
+	if c.JWTExpire <= 0 {
+		invalid("JWT_EXPIRED must be a positive duration")
+	}
+	if c.JWTRefreshExpire <= 0 {
+		invalid("REFRESH_TOKEN_EXPIRED must be a positive duration")
+	}
+	if c.DownloadExpire <= 0 {
+		invalid("DOWNLOAD_URL_EXPIRED must be a positive duration")
+	}
+	if c.OrderingMode != OrderingModeArray && c.OrderingMode != OrderingModeRank {
+		invalid("ORDERING_MODE must be %s or %s", OrderingModeArray, OrderingModeRank)
+	}
+	if c.MaxUploadSize <= 0 {
+		invalid("MAX_UPLOAD_SIZE must be greater than zero")
+	}
+	if c.AdminRole != "admin" && c.AdminRole != "user" {
+		invalid("ADMIN_ROLE must be admin or user")
+	}
+
+	switch c.StorageDriver {
+	case StorageDriverLocal:
+		if c.StorageLocalDir == "" {
+			invalid("STORAGE_LOCAL_DIR is required for the local storage driver")
+		}
+	case StorageDriverS3:
+		if c.S3Bucket == "" {
+			invalid("S3_BUCKET is required for the s3 storage driver")
+		}
+		if c.S3AccessKey == "" || c.S3SecretKey == "" {
+			invalid("S3_ACCESS_KEY and S3_SECRET_KEY are required for the s3 storage driver")
+		}
+	default:
+		invalid("STORAGE_DRIVER must be %s or %s", StorageDriverLocal, StorageDriverS3)
+	}
+
+	if c.AppURL == "" {
+		invalid("APP_URL is required")
+	}
+	if _, err := mail.ParseAddress(c.MailFrom); err != nil {
+		invalid("MAIL_FROM must be a valid email address")
+	}
+	if c.EmailVerificationExpire <= 0 {
+		invalid("EMAIL_VERIFICATION_EXPIRED must be a positive duration")
+	}
+	if c.PasswordResetExpire <= 0 {
+		invalid("PASSWORD_RESET_EXPIRED must be a positive duration")
+	}
+	switch c.MailDriver {
+	case MailDriverSMTP:
+		if c.SMTPHost == "" {
+			invalid("SMTP_HOST is required for the smtp mail driver")
+		}
+		if c.SMTPPort <= 0 || c.SMTPPort > 65535 {
+			invalid("SMTP_PORT must be between 1 and 65535")
+		}
+	case MailDriverFile:
+		if c.MailFileDir == "" {
+			invalid("MAIL_FILE_DIR is required for the file mail driver")
+		}
+	case MailDriverLog:
+	default:
+		invalid("MAIL_DRIVER must be %s, %s or %s", MailDriverSMTP, MailDriverFile, MailDriverLog)
+	}
+
+	if c.IsProduction() {
+		if c.JWTSecret == "" || c.JWTSecret == defaultJWTSecret {
+			invalid("JWT_SECRET is required in production")
+		} else if len(c.JWTSecret) < 32 {
+			invalid("JWT_SECRET must be at least 32 characters in production")
+		}
+		if c.DBDriver == DBDriverPostgres && c.DBPassword == "" {
+			invalid("DB_PASSWORD is required in production")
+		}
+		if c.AdminPassword == "" {
+			invalid("ADMIN_PASSWORD is required in production")
+		}
+		if c.CORSOrigin == "*" {
+			invalid("CORS_ORIGIN must list the allowed origins in production")
+		}
 	}
+	return errors.Join(errs...)
 }
 
-func getEnv(key string, fallback string) string {
+// loader membaca env sambil mengumpulkan error, nilai yang gagal di parse diganti fallback
+type loader struct {
+	errs []error
+}
+
+// <KEY>_FILE dibaca dari file, tidak boleh diisi bersamaan dengan <KEY>
+func (l *loader) lookup(key string) (string, bool) {
 	value, exist := os.LookupEnv(key)
+	path, fromFile := os.LookupEnv(key + "_FILE")
+	if !fromFile {
+		return value, exist
+	}
 	if exist {
+		l.errs = append(l.errs, fmt.Errorf("%s and %s_FILE cannot both be set", key, key))
+		return value, exist
+	}
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		l.errs = append(l.errs, fmt.Errorf("%s_FILE: %w", key, err))
+		return "", false
+	}
+	return strings.TrimRight(string(content), "\r\n"), true
+}
+
+func (l *loader) string(key string, fallback string) string {
+	if value, ok := l.lookup(key); ok {
 		return value
-	} else {
+	}
+	return fallback
+}
+
+func (l *loader) int(key string, fallback int) int {
+	value, ok := l.lookup(key)
+	if !ok || value == "" {
+		return fallback
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil {
+		l.errs = append(l.errs, fmt.Errorf("%s must be an integer, got %q", key, value))
+		return fallback
+	}
+	return n
+}
+
+func (l *loader) int64(key string, fallback int64) int64 {
+	value, ok := l.lookup(key)
+	if !ok || value == "" {
+		return fallback
+	}
+	n, err := strconv.ParseInt(value, 10, 64)
+	if err != nil || n < 0 {
+		l.errs = append(l.errs, fmt.Errorf("%s must be a non negative integer, got %q", key, value))
 		return fallback
 	}
+	return n
+}
+
+// durasi memakai format time.ParseDuration, contoh : 15m, 2h
+func (l *loader) duration(key string, fallback time.Duration) time.Duration {
+	value, ok := l.lookup(key)
+	if !ok || value == "" {
+		return fallback
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil {
+		l.errs = append(l.errs, fmt.Errorf("%s must be a duration like 15m or 2h, got %q", key, value))
+		return fallback
+	}
+	return d
+}
+
+func (l *loader) bool(key string, fallback bool) bool {
+	value, ok := l.lookup(key)
+	if !ok || value == "" {
+		return fallback
+	}
+	b, err := strconv.ParseBool(value)
+	if err != nil {
+		l.errs = append(l.errs, fmt.Errorf("%s must be true or false, got %q", key, value))
+		return fallback
+	}
+	return b
 }
 
 func ConnectDB() {
-	cfg := AppConfig
+	db, err := OpenDB(AppConfig)
+	if err != nil {
+		log.Fatal("Failed to Connect to database", err)
+	}
+
+	DB = db
 
-	dsn := fmt.Sprintf("host=%s port=%s password=%s dbname=%s sslmode=disable",cfg.DBHost
-	cfg.DBPort,cfg.DBUser,cfg.DBPassword,cfg.DBName)
+}
+
+// OpenDB membuka koneksi sesuai DB_DRIVER, dipakai juga oleh test dengan sqlite ":memory:"
+func OpenDB(cfg *Config) (*gorm.DB, error) {
+	var dialector gorm.Dialector
+	switch cfg.DBDriver {
+	case DBDriverSQLite:
+		//driver sqlite murni Go (tanpa cgo), foreign key dan busy timeout harus dinyalakan per koneksi
+		dialector = sqlite.Open(cfg.DBPath + "?_pragma=foreign_keys(1)&_pragma=busy_timeout(5000)")
+	default:
+		dsn := fmt.Sprintf("host=%s port=%d user=%s password=%s dbname=%s sslmode=disable", cfg.DBHost,
+			cfg.DBPort, cfg.DBUser, cfg.DBPassword, cfg.DBName)
+		dialector = postgres.Open(dsn)
+	}
 
 	//open conecction ke db
 
-	db,err := gorm.Open(postgres.Open(dsn),&gorm.Config{})
+	db, err := gorm.Open(dialector, &gorm.Config{})
 	if err != nil {
-		log.Fatal("Failed to Connect to database",err)
+		return nil, err
 	}
 
-	sqlDB,err := db.DB()
+	sqlDB, err := db.DB()
 	if err != nil {
-		log.Fatal("Failed to get database instance",err)
+		return nil, err
+	}
 
+	//sqlite hanya bisa satu penulis, satu koneksi juga membuat ":memory:" tetap memakai database yang sama
+	if cfg.DBDriver == DBDriverSQLite {
+		sqlDB.SetMaxOpenConns(1)
+		return db, nil
 	}
 
 	sqlDB.SetMaxIdleConns(10)
 	sqlDB.SetMaxOpenConns(100)
 	sqlDB.SetConnMaxLifetime(time.Hour)
-
-
-	DB = db
-	
-
-
+	return db, nil
 }
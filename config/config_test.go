@@ -3,10 +3,13 @@ package config
 
 import (
 	"os"
+	"path/filepath"
+	"strings"
 	"testing"
+	"time"
 )
 
-func TestGetEnv(t *testing.T) {
+func TestLoaderString(t *testing.T) {
 	tests := []struct {
 		name     string
 		key      string
@@ -48,9 +51,10 @@ func TestGetEnv(t *testing.T) {
 				os.Unsetenv(tt.key)
 			}
 
-			got := getEnv(tt.key, tt.fallback)
+			l := &loader{}
+			got := l.string(tt.key, tt.fallback)
 			if got != tt.want {
-				t.Errorf("getEnv() = %v, want %v", got, tt.want)
+				t.Errorf("string() = %v, want %v", got, tt.want)
 			}
 		})
 	}
@@ -60,7 +64,7 @@ func TestLoadEnv(t *testing.T) {
 	tests := []struct {
 		name       string
 		envVars    map[string]string
-		wantPort   string
+		wantPort   int
 		wantDBHost string
 		shouldLoad bool
 	}{
@@ -72,17 +76,17 @@ func TestLoadEnv(t *testing.T) {
 				"DB_USER":               "test-user",
 				"DB_PASSWORD":           "test-pass",
 				"JWT_SECRET":            "test-secret",
-				"JWT_EXPIRY":            "120",
+				"JWT_EXPIRED":           "2h",
 				"REFRESH_TOKEN_EXPIRED": "48h",
 			},
-			wantPort:   "8080",
+			wantPort:   8080,
 			wantDBHost: "test-host",
 			shouldLoad: true,
 		},
 		{
 			name:       "load with default values",
 			envVars:    map[string]string{},
-			wantPort:   "3030",      // default value
+			wantPort:   3030,        // default value
 			wantDBHost: "localhost", // default value
 			shouldLoad: true,
 		},
@@ -100,7 +104,9 @@ func TestLoadEnv(t *testing.T) {
 			AppConfig = nil
 
 			// Load environment
-			LoadEnv()
+			if err := LoadEnv(); err != nil {
+				t.Fatalf("LoadEnv() error = %v", err)
+			}
 
 			// Verify config is not nil
 			if AppConfig == nil {
@@ -121,20 +127,16 @@ func TestLoadEnv(t *testing.T) {
 				t.Error("DBUser should not be empty")
 			}
 
-			if AppConfig.DBPassword == "" {
-				t.Error("DBPassword should not be empty")
-			}
-
 			if AppConfig.JWTSecret == "" {
 				t.Error("JWTSecret should not be empty")
 			}
 
-			if AppConfig.JWTExpire == "" {
-				t.Error("JWTExpire should not be empty")
+			if AppConfig.JWTExpire <= 0 {
+				t.Error("JWTExpire should be positive")
 			}
 
-			if AppConfig.JWTRefreshToken == "" {
-				t.Error("JWTRefreshToken should not be empty")
+			if AppConfig.JWTRefreshExpire <= 0 {
+				t.Error("JWTRefreshExpire should be positive")
 			}
 		})
 	}
@@ -147,19 +149,16 @@ DB_HOST=test-db-host
 DB_USER=test-db-user
 DB_PASSWORD=test-db-pass
 JWT_SECRET=test-jwt-secret
-JWT_EXPIRY=3600
+JWT_EXPIRED=1h
 REFRESH_TOKEN_EXPIRED=72h`
 
-	tmpFile, err := os.CreateTemp("", "test*.env")
-	if err != nil {
-		t.Fatalf("Failed to create temp file: %v", err)
-	}
-	defer os.Remove(tmpFile.Name())
-
-	if _, err := tmpFile.WriteString(envContent); err != nil {
+	tmpDir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(tmpDir, ".env"), []byte(envContent), 0o600); err != nil {
 		t.Fatalf("Failed to write to temp file: %v", err)
 	}
-	tmpFile.Close()
+	for _, key := range []string{"PORT", "DB_HOST", "DB_USER", "DB_PASSWORD", "JWT_SECRET", "JWT_EXPIRED", "REFRESH_TOKEN_EXPIRED"} {
+		defer os.Unsetenv(key)
+	}
 
 	// Change to temp directory and back
 	oldWd, err := os.Getwd()
@@ -168,17 +167,20 @@ REFRESH_TOKEN_EXPIRED=72h`
 	}
 	defer os.Chdir(oldWd)
 
-	tmpDir := tmpFile.Name()
-	os.Chdir(tmpDir)
+	if err := os.Chdir(tmpDir); err != nil {
+		t.Fatalf("Failed to change directory: %v", err)
+	}
 
 	// Clear previous config
 	AppConfig = nil
 
 	// Load environment
-	LoadEnv()
+	if err := LoadEnv(); err != nil {
+		t.Fatalf("LoadEnv() error = %v", err)
+	}
 
 	// Verify values from .env file
-	if AppConfig.AppPort != "9999" {
+	if AppConfig.AppPort != 9999 {
 		t.Errorf("AppPort = %v, want 9999", AppConfig.AppPort)
 	}
 
@@ -198,12 +200,12 @@ REFRESH_TOKEN_EXPIRED=72h`
 		t.Errorf("JWTSecret = %v, want test-jwt-secret", AppConfig.JWTSecret)
 	}
 
-	if AppConfig.JWTExpire != "3600" {
-		t.Errorf("JWTExpire = %v, want 3600", AppConfig.JWTExpire)
+	if AppConfig.JWTExpire != time.Hour {
+		t.Errorf("JWTExpire = %v, want 1h", AppConfig.JWTExpire)
 	}
 
-	if AppConfig.JWTRefreshToken != "72h" {
-		t.Errorf("JWTRefreshToken = %v, want 72h", AppConfig.JWTRefreshToken)
+	if AppConfig.JWTRefreshExpire != 72*time.Hour {
+		t.Errorf("JWTRefreshExpire = %v, want 72h", AppConfig.JWTRefreshExpire)
 	}
 }
 
@@ -223,3 +225,104 @@ func TestConfigSingleton(t *testing.T) {
 		t.Error("LoadEnv() should maintain singleton instance")
 	}
 }
+
+func TestLoaderFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "secret")
+	if err := os.WriteFile(path, []byte("from-file\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("TEST_SECRET_FILE", path)
+
+	l := &loader{}
+	if got := l.string("TEST_SECRET", "fallback"); got != "from-file" {
+		t.Errorf("string() = %q, want from-file", got)
+	}
+	if len(l.errs) != 0 {
+		t.Errorf("unexpected errors: %v", l.errs)
+	}
+
+	t.Setenv("TEST_SECRET", "direct")
+	l = &loader{}
+	l.string("TEST_SECRET", "fallback")
+	if len(l.errs) != 1 {
+		t.Errorf("expected error when both TEST_SECRET and TEST_SECRET_FILE are set, got %v", l.errs)
+	}
+
+	t.Setenv("TEST_MISSING_FILE", filepath.Join(t.TempDir(), "missing"))
+	l = &loader{}
+	if got := l.string("TEST_MISSING", "fallback"); got != "fallback" || len(l.errs) != 1 {
+		t.Errorf("string() = %q, errors = %v", got, l.errs)
+	}
+}
+
+func TestLoadEnv_InvalidValues(t *testing.T) {
+	t.Setenv("PORT", "abc")
+	t.Setenv("JWT_EXPIRED", "120")
+	t.Setenv("ORDERING_MODE", "random")
+
+	AppConfig = nil
+	err := LoadEnv()
+	if err == nil {
+		t.Fatal("expected error")
+	}
+	for _, key := range []string{"PORT", "JWT_EXPIRED", "ORDERING_MODE"} {
+		if !strings.Contains(err.Error(), key) {
+			t.Errorf("error should mention %s: %v", key, err)
+		}
+	}
+}
+
+func TestLoadEnv_Production(t *testing.T) {
+	t.Setenv("APP_ENV", "production")
+	t.Setenv("JWT_SECRET", "")
+	t.Setenv("DB_PASSWORD", "")
+	t.Setenv("ADMIN_PASSWORD", "")
+	t.Setenv("CORS_ORIGIN", "https://app.example.com")
+
+	AppConfig = nil
+	err := LoadEnv()
+	if err == nil {
+		t.Fatal("expected error for missing secrets in production")
+	}
+	for _, key := range []string{"JWT_SECRET", "DB_PASSWORD", "ADMIN_PASSWORD"} {
+		if !strings.Contains(err.Error(), key) {
+			t.Errorf("error should mention %s: %v", key, err)
+		}
+	}
+
+	t.Setenv("JWT_SECRET", strings.Repeat("s", 32))
+	t.Setenv("DB_PASSWORD", "db-secret")
+	t.Setenv("ADMIN_PASSWORD", "admin-secret")
+	if err := LoadEnv(); err != nil {
+		t.Errorf("LoadEnv() error = %v", err)
+	}
+}
+
+func TestLoadEnv_DevelopmentDefaultSecret(t *testing.T) {
+	t.Setenv("JWT_SECRET", "")
+
+	AppConfig = nil
+	if err := LoadEnv(); err != nil {
+		t.Fatalf("LoadEnv() error = %v", err)
+	}
+	if AppConfig.JWTSecret == "" {
+		t.Error("JWTSecret should fall back to the development default")
+	}
+}
+
+// driver sqlite murni Go, test ini juga harus jalan dengan CGO_ENABLED=0
+func TestOpenDB_SQLitePragmas(t *testing.T) {
+	db, err := OpenDB(&Config{DBDriver: DBDriverSQLite, DBPath: filepath.Join(t.TempDir(), "test.db")})
+	if err != nil {
+		t.Fatalf("OpenDB() error = %v", err)
+	}
+	sqlDB, _ := db.DB()
+	defer sqlDB.Close()
+
+	var foreignKeys, busyTimeout int
+	db.Raw("PRAGMA foreign_keys").Scan(&foreignKeys)
+	db.Raw("PRAGMA busy_timeout").Scan(&busyTimeout)
+	if foreignKeys != 1 || busyTimeout != 5000 {
+		t.Errorf("foreign_keys = %d, busy_timeout = %d", foreignKeys, busyTimeout)
+	}
+}
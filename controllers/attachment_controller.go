@@ -0,0 +1,196 @@
+package controllers
+
+import (
+	"fmt"
+	"mime"
+	"net/url"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/odink789/project-management/dto"
+	"github.com/odink789/project-management/models"
+	"github.com/odink789/project-management/services"
+	"github.com/odink789/project-management/utils"
+)
+
+type AttachmentController struct {
+	service services.AttachmentService
+}
+
+func NewAttachmentController(s services.AttachmentService) *AttachmentController {
+	return &AttachmentController{service: s}
+}
+
+func (c *AttachmentController) GetAttachments(ctx *fiber.Ctx) error {
+	principal, _ := utils.GetPrincipal(ctx)
+
+	attachments, err := c.service.GetAttachments(ctx.UserContext(), principal, ctx.Params("id"))
+	if err != nil {
+		return serviceError("Gagal Mengambil Attachment", err)
+	}
+
+	return utils.Success(ctx, "Get Attachments Success", dto.NewAttachmentResponses(attachments))
+}
+
+// multipart/form-data dengan field "file"
+func (c *AttachmentController) Upload(ctx *fiber.Ctx) error {
+	principal, _ := utils.GetPrincipal(ctx)
+
+	header, err := ctx.FormFile("file")
+	if err != nil {
+		return serviceError("Gagal Parsing Data", services.ValidationError(services.FieldError{Field: "file", Message: "file is required"}))
+	}
+
+	file, err := header.Open()
+	if err != nil {
+		return serviceError("Gagal Membaca File", services.ValidationError(services.FieldError{Field: "file", Message: "file could not be read"}))
+	}
+	defer file.Close()
+
+	attachment, err := c.service.Upload(ctx.UserContext(), principal, ctx.Params("id"), services.AttachmentUpload{
+		FileName:    header.Filename,
+		Size:        header.Size,
+		ContentType: header.Header.Get(fiber.HeaderContentType),
+		Reader:      file,
+	})
+	if err != nil {
+		return serviceError("Gagal Upload Attachment", err)
+	}
+
+	return utils.Created(ctx, "Upload Attachment Success", dto.NewAttachmentResponse(attachment))
+}
+
+func (c *AttachmentController) Download(ctx *fiber.Ctx) error {
+	principal, _ := utils.GetPrincipal(ctx)
+
+	attachment, err := c.service.GetAttachment(ctx.UserContext(), principal, ctx.Params("id"))
+	if err != nil {
+		return serviceError("Gagal Download Attachment", err)
+	}
+
+	return c.sendFile(ctx, attachment)
+}
+
+// link download yang di sign, bisa dibuka tanpa token (tag <img>, <video>, dsb) sampai expired
+func (c *AttachmentController) CreateLink(ctx *fiber.Ctx) error {
+	principal, _ := utils.GetPrincipal(ctx)
+
+	link, err := c.service.CreateDownloadLink(ctx.UserContext(), principal, ctx.Params("id"))
+	if err != nil {
+		return serviceError("Gagal Membuat Link Download", err)
+	}
+
+	query := url.Values{}
+	query.Set("expires", strconv.FormatInt(link.ExpiresAt.Unix(), 10))
+	query.Set("signature", link.Signature)
+
+	return utils.Created(ctx, "Create Download Link Success", fiber.Map{
+		"url":        ctx.BaseURL() + "/v1/files/" + link.AttachmentID + "?" + query.Encode(),
+		"expires_at": link.ExpiresAt,
+	})
+}
+
+func (c *AttachmentController) SignedDownload(ctx *fiber.Ctx) error {
+	attachment, err := c.service.ResolveDownloadLink(ctx.UserContext(), ctx.Params("id"), ctx.Query("expires"), ctx.Query("signature"))
+	if err != nil {
+		return serviceError("Gagal Download Attachment", err)
+	}
+
+	return c.sendFile(ctx, attachment)
+}
+
+// thumbnail tidak berubah setelah dibuat, jadi boleh di cache browser selama link nya berlaku
+func (c *AttachmentController) Thumbnail(ctx *fiber.Ctx) error {
+	thumbnail, file, err := c.service.OpenThumbnail(ctx.UserContext(), ctx.Params("id"), ctx.Params("size"), ctx.Query("expires"), ctx.Query("signature"))
+	if err != nil {
+		return serviceError("Gagal Mengambil Thumbnail", err)
+	}
+
+	ctx.Set(fiber.HeaderContentType, "image/jpeg")
+	ctx.Set(fiber.HeaderCacheControl, "private, max-age=600")
+	ctx.Set(fiber.HeaderXContentTypeOptions, "nosniff")
+	return ctx.SendStream(file, int(thumbnail.ByteSize))
+}
+
+func (c *AttachmentController) SetCover(ctx *fiber.Ctx) error {
+	principal, _ := utils.GetPrincipal(ctx)
+
+	var body dto.SetCoverRequest
+
+	if err := parseBody(ctx, &body); err != nil {
+		return err
+	}
+
+	card, err := c.service.SetCover(ctx.UserContext(), principal, ctx.Params("id"), body.AttachmentID)
+	if err != nil {
+		return serviceError("Gagal Mengatur Cover", err)
+	}
+
+	return utils.Success(ctx, "Set Cover Success", dto.NewCardResponse(card))
+}
+
+func (c *AttachmentController) RemoveCover(ctx *fiber.Ctx) error {
+	principal, _ := utils.GetPrincipal(ctx)
+
+	card, err := c.service.RemoveCover(ctx.UserContext(), principal, ctx.Params("id"))
+	if err != nil {
+		return serviceError("Gagal Menghapus Cover", err)
+	}
+
+	return utils.Success(ctx, "Remove Cover Success", dto.NewCardResponse(card))
+}
+
+// kirim file dengan dukungan header Range supaya video / pdf besar bisa di stream
+func (c *AttachmentController) sendFile(ctx *fiber.Ctx, attachment *models.CardAttachment) error {
+	etag := `"` + attachment.Checksum + `"`
+	ctx.Set(fiber.HeaderAcceptRanges, "bytes")
+	ctx.Set(fiber.HeaderETag, etag)
+
+	rangeHeader := ctx.Get(fiber.HeaderRange)
+	//If-Range yang tidak cocok berarti file sudah berubah, kirim file utuh
+	if ifRange := ctx.Get(fiber.HeaderIfRange); ifRange != "" && ifRange != etag {
+		rangeHeader = ""
+	}
+
+	start, length, partial, err := utils.ParseRange(rangeHeader, attachment.Size)
+	if err != nil {
+		ctx.Set(fiber.HeaderContentRange, fmt.Sprintf("bytes */%d", attachment.Size))
+		return utils.RangeNotSatisfiable(ctx, "Gagal Download Attachment", err.Error())
+	}
+
+	file, err := c.service.OpenFile(ctx.UserContext(), attachment, start, length)
+	if err != nil {
+		return serviceError("Gagal Download Attachment", err)
+	}
+
+	ctx.Set(fiber.HeaderContentType, attachment.MimeType)
+	ctx.Set(fiber.HeaderContentDisposition, mime.FormatMediaType("attachment", map[string]string{"filename": attachment.FileName}))
+	ctx.Set(fiber.HeaderXContentTypeOptions, "nosniff")
+	if partial {
+		ctx.Status(fiber.StatusPartialContent)
+		ctx.Set(fiber.HeaderContentRange, fmt.Sprintf("bytes %d-%d/%d", start, start+length-1, attachment.Size))
+	}
+
+	//stream ditutup oleh fasthttp setelah selesai dikirim
+	return ctx.SendStream(file, int(length))
+}
+
+// laporan pemakaian storage per board dan per user, ?limit untuk jumlah baris tiap daftar
+func (c *AttachmentController) StorageUsage(ctx *fiber.Ctx) error {
+	usage, err := c.service.StorageUsage(ctx.UserContext(), ctx.QueryInt("limit", 0))
+	if err != nil {
+		return serviceError("Gagal Mengambil Pemakaian Storage", err)
+	}
+
+	return utils.Success(ctx, "Get Storage Usage Success", usage)
+}
+
+func (c *AttachmentController) Delete(ctx *fiber.Ctx) error {
+	principal, _ := utils.GetPrincipal(ctx)
+
+	if err := c.service.Delete(ctx.UserContext(), principal, ctx.Params("id")); err != nil {
+		return serviceError("Gagal Menghapus Attachment", err)
+	}
+
+	return utils.Success(ctx, "Delete Attachment Success", nil)
+}
@@ -0,0 +1,136 @@
+package controllers
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/odink789/project-management/dto"
+	"github.com/odink789/project-management/models"
+	"github.com/odink789/project-management/services"
+	"github.com/odink789/project-management/utils"
+)
+
+type BoardController struct {
+	service services.BoardService
+}
+
+func NewBoardController(s services.BoardService) *BoardController {
+	return &BoardController{service: s}
+}
+
+func (c *BoardController) Create(ctx *fiber.Ctx) error {
+	principal, _ := utils.GetPrincipal(ctx)
+	var body dto.BoardRequest
+
+	if err := parseBody(ctx, &body); err != nil {
+		return err
+	}
+
+	board := body.ToModel()
+	if err := c.service.Create(ctx.UserContext(), principal, board); err != nil {
+		return serviceError("Gagal Membuat Board", err)
+	}
+
+	return utils.Created(ctx, "Create Board Success", dto.NewBoardResponse(board))
+}
+
+func (c *BoardController) GetMyBoards(ctx *fiber.Ctx) error {
+	principal, _ := utils.GetPrincipal(ctx)
+
+	boards, err := c.service.ListMine(ctx.UserContext(), principal)
+	if err != nil {
+		return serviceError("Gagal Mengambil Board", err)
+	}
+
+	return utils.Success(ctx, "Get Boards Success", dto.NewBoardResponses(boards))
+}
+
+func (c *BoardController) GetBoard(ctx *fiber.Ctx) error {
+	principal, _ := utils.GetPrincipal(ctx)
+
+	board, err := c.service.GetByPublicID(ctx.UserContext(), principal, ctx.Params("id"))
+	if err != nil {
+		return serviceError("Gagal Mengambil Board", err)
+	}
+
+	return utils.Success(ctx, "Get Board Success", dto.NewBoardResponse(board))
+}
+
+func (c *BoardController) Update(ctx *fiber.Ctx) error {
+	principal, _ := utils.GetPrincipal(ctx)
+	var body dto.BoardRequest
+
+	if err := parseBody(ctx, &body); err != nil {
+		return err
+	}
+
+	board, err := c.service.Update(ctx.UserContext(), principal, ctx.Params("id"), body.ToModel())
+	if err != nil {
+		return serviceError("Gagal Update Board", err)
+	}
+
+	return utils.Success(ctx, "Update Board Success", dto.NewBoardResponse(board))
+}
+
+func (c *BoardController) Delete(ctx *fiber.Ctx) error {
+	principal, _ := utils.GetPrincipal(ctx)
+
+	if err := c.service.Delete(ctx.UserContext(), principal, ctx.Params("id")); err != nil {
+		return serviceError("Gagal Menghapus Board", err)
+	}
+
+	return utils.Success(ctx, "Delete Board Success", nil)
+}
+
+func (c *BoardController) GetMembers(ctx *fiber.Ctx) error {
+	principal, _ := utils.GetPrincipal(ctx)
+
+	members, err := c.service.ListMembers(ctx.UserContext(), principal, ctx.Params("id"))
+	if err != nil {
+		return serviceError("Gagal Mengambil Member", err)
+	}
+
+	return utils.Success(ctx, "Get Members Success", dto.NewBoardMemberResponses(members))
+}
+
+func (c *BoardController) AddMember(ctx *fiber.Ctx) error {
+	principal, _ := utils.GetPrincipal(ctx)
+
+	var body dto.AddMemberRequest
+
+	if err := parseBody(ctx, &body); err != nil {
+		return err
+	}
+
+	member, err := c.service.AddMember(ctx.UserContext(), principal, ctx.Params("id"), body.Email, models.BoardRole(body.Role))
+	if err != nil {
+		return serviceError("Gagal Menambah Member", err)
+	}
+
+	return utils.Created(ctx, "Add Member Success", dto.NewBoardMemberResponse(member))
+}
+
+func (c *BoardController) UpdateMemberRole(ctx *fiber.Ctx) error {
+	principal, _ := utils.GetPrincipal(ctx)
+
+	var body dto.UpdateMemberRoleRequest
+
+	if err := parseBody(ctx, &body); err != nil {
+		return err
+	}
+
+	member, err := c.service.UpdateMemberRole(ctx.UserContext(), principal, ctx.Params("id"), ctx.Params("userId"), models.BoardRole(body.Role))
+	if err != nil {
+		return serviceError("Gagal Update Role Member", err)
+	}
+
+	return utils.Success(ctx, "Update Member Role Success", dto.NewBoardMemberResponse(member))
+}
+
+func (c *BoardController) RemoveMember(ctx *fiber.Ctx) error {
+	principal, _ := utils.GetPrincipal(ctx)
+
+	if err := c.service.RemoveMember(ctx.UserContext(), principal, ctx.Params("id"), ctx.Params("userId")); err != nil {
+		return serviceError("Gagal Menghapus Member", err)
+	}
+
+	return utils.Success(ctx, "Remove Member Success", nil)
+}
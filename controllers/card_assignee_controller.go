@@ -0,0 +1,66 @@
+package controllers
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/odink789/project-management/dto"
+	"github.com/odink789/project-management/services"
+	"github.com/odink789/project-management/utils"
+)
+
+type CardAssigneeController struct {
+	service services.CardAssigneeService
+}
+
+func NewCardAssigneeController(s services.CardAssigneeService) *CardAssigneeController {
+	return &CardAssigneeController{service: s}
+}
+
+func (c *CardAssigneeController) GetAssignees(ctx *fiber.Ctx) error {
+	principal, _ := utils.GetPrincipal(ctx)
+
+	users, err := c.service.GetAssignees(ctx.UserContext(), principal, ctx.Params("id"))
+	if err != nil {
+		return serviceError("Gagal Mengambil Assignee", err)
+	}
+
+	return utils.Success(ctx, "Get Assignees Success", dto.NewUserResponses(users))
+}
+
+func (c *CardAssigneeController) Assign(ctx *fiber.Ctx) error {
+	principal, _ := utils.GetPrincipal(ctx)
+
+	var body dto.AssignRequest
+
+	if err := parseBody(ctx, &body); err != nil {
+		return err
+	}
+
+	user, err := c.service.Assign(ctx.UserContext(), principal, ctx.Params("id"), body.UserID)
+	if err != nil {
+		return serviceError("Gagal Menambahkan Assignee", err)
+	}
+
+	return utils.Created(ctx, "Assign Card Success", dto.NewUserResponse(user))
+}
+
+func (c *CardAssigneeController) Unassign(ctx *fiber.Ctx) error {
+	principal, _ := utils.GetPrincipal(ctx)
+
+	if err := c.service.Unassign(ctx.UserContext(), principal, ctx.Params("id"), ctx.Params("userId")); err != nil {
+		return serviceError("Gagal Menghapus Assignee", err)
+	}
+
+	return utils.Success(ctx, "Unassign Card Success", nil)
+}
+
+// card yang di assign ke user yang sedang login dari semua board
+func (c *CardAssigneeController) AssignedToMe(ctx *fiber.Ctx) error {
+	principal, _ := utils.GetPrincipal(ctx)
+
+	cards, err := c.service.AssignedToMe(ctx.UserContext(), principal)
+	if err != nil {
+		return serviceError("Gagal Mengambil Card", err)
+	}
+
+	return utils.Success(ctx, "Get Assigned Cards Success", dto.NewAssignedCardResponses(cards))
+}
@@ -0,0 +1,97 @@
+package controllers
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/odink789/project-management/dto"
+	"github.com/odink789/project-management/services"
+	"github.com/odink789/project-management/utils"
+)
+
+type CardController struct {
+	service services.CardService
+}
+
+func NewCardController(s services.CardService) *CardController {
+	return &CardController{service: s}
+}
+
+func (c *CardController) GetCards(ctx *fiber.Ctx) error {
+	principal, _ := utils.GetPrincipal(ctx)
+
+	cards, err := c.service.GetCards(ctx.UserContext(), principal, ctx.Params("listId"))
+	if err != nil {
+		return serviceError("Gagal Mengambil Card", err)
+	}
+
+	return utils.Success(ctx, "Get Cards Success", dto.NewOrderedCardsResponse(cards))
+}
+
+func (c *CardController) Create(ctx *fiber.Ctx) error {
+	principal, _ := utils.GetPrincipal(ctx)
+
+	var body dto.CreateCardRequest
+
+	if err := parseBody(ctx, &body); err != nil {
+		return err
+	}
+
+	card := body.ToModel()
+	if err := c.service.Create(ctx.UserContext(), principal, ctx.Params("listId"), card, body.Index()); err != nil {
+		return serviceError("Gagal Membuat Card", err)
+	}
+
+	return utils.Created(ctx, "Create Card Success", dto.NewCardResponse(card))
+}
+
+func (c *CardController) GetCard(ctx *fiber.Ctx) error {
+	principal, _ := utils.GetPrincipal(ctx)
+
+	card, err := c.service.GetByPublicID(ctx.UserContext(), principal, ctx.Params("id"))
+	if err != nil {
+		return serviceError("Gagal Mengambil Card", err)
+	}
+
+	return utils.Success(ctx, "Get Card Success", dto.NewCardResponse(card))
+}
+
+func (c *CardController) Update(ctx *fiber.Ctx) error {
+	principal, _ := utils.GetPrincipal(ctx)
+	var body dto.UpdateCardRequest
+
+	if err := parseBody(ctx, &body); err != nil {
+		return err
+	}
+
+	card, err := c.service.Update(ctx.UserContext(), principal, ctx.Params("id"), body.ToModel())
+	if err != nil {
+		return serviceError("Gagal Update Card", err)
+	}
+
+	return utils.Success(ctx, "Update Card Success", dto.NewCardResponse(card))
+}
+
+func (c *CardController) Delete(ctx *fiber.Ctx) error {
+	principal, _ := utils.GetPrincipal(ctx)
+
+	if err := c.service.Delete(ctx.UserContext(), principal, ctx.Params("id")); err != nil {
+		return serviceError("Gagal Menghapus Card", err)
+	}
+
+	return utils.Success(ctx, "Delete Card Success", nil)
+}
+
+func (c *CardController) Move(ctx *fiber.Ctx) error {
+	principal, _ := utils.GetPrincipal(ctx)
+	var body dto.MoveCardRequest
+
+	if err := parseBody(ctx, &body); err != nil {
+		return err
+	}
+
+	result, err := c.service.Move(ctx.UserContext(), principal, ctx.Params("id"), body.ToInput())
+	if err != nil {
+		return serviceError("Gagal Memindahkan Card", err)
+	}
+
+	return utils.Success(ctx, "Move Card Success", dto.NewCardMoveResponse(result))
+}
@@ -0,0 +1,81 @@
+package controllers
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/odink789/project-management/dto"
+	"github.com/odink789/project-management/services"
+	"github.com/odink789/project-management/utils"
+)
+
+type CommentController struct {
+	service services.CommentService
+}
+
+func NewCommentController(s services.CommentService) *CommentController {
+	return &CommentController{service: s}
+}
+
+// ?page=1&limit=20
+func (c *CommentController) GetComments(ctx *fiber.Ctx) error {
+	principal, _ := utils.GetPrincipal(ctx)
+
+	page, err := c.service.GetComments(ctx.UserContext(), principal, ctx.Params("id"), ctx.QueryInt("page", 1), ctx.QueryInt("limit", 0))
+	if err != nil {
+		return serviceError("Gagal Mengambil Comment", err)
+	}
+
+	return utils.Success(ctx, "Get Comments Success", dto.NewCommentPageResponse(page))
+}
+
+func (c *CommentController) Create(ctx *fiber.Ctx) error {
+	principal, _ := utils.GetPrincipal(ctx)
+	var body dto.CommentRequest
+
+	if err := parseBody(ctx, &body); err != nil {
+		return err
+	}
+
+	comment, err := c.service.Create(ctx.UserContext(), principal, ctx.Params("id"), body.Message)
+	if err != nil {
+		return serviceError("Gagal Membuat Comment", err)
+	}
+
+	return utils.Created(ctx, "Create Comment Success", dto.NewCommentResponse(comment))
+}
+
+func (c *CommentController) Update(ctx *fiber.Ctx) error {
+	principal, _ := utils.GetPrincipal(ctx)
+	var body dto.CommentRequest
+
+	if err := parseBody(ctx, &body); err != nil {
+		return err
+	}
+
+	comment, err := c.service.Update(ctx.UserContext(), principal, ctx.Params("id"), body.Message)
+	if err != nil {
+		return serviceError("Gagal Update Comment", err)
+	}
+
+	return utils.Success(ctx, "Update Comment Success", dto.NewCommentResponse(comment))
+}
+
+func (c *CommentController) Delete(ctx *fiber.Ctx) error {
+	principal, _ := utils.GetPrincipal(ctx)
+
+	if err := c.service.Delete(ctx.UserContext(), principal, ctx.Params("id")); err != nil {
+		return serviceError("Gagal Menghapus Comment", err)
+	}
+
+	return utils.Success(ctx, "Delete Comment Success", nil)
+}
+
+func (c *CommentController) History(ctx *fiber.Ctx) error {
+	principal, _ := utils.GetPrincipal(ctx)
+
+	edits, err := c.service.History(ctx.UserContext(), principal, ctx.Params("id"))
+	if err != nil {
+		return serviceError("Gagal Mengambil Riwayat Comment", err)
+	}
+
+	return utils.Success(ctx, "Get Comment History Success", dto.NewCommentEditResponses(edits))
+}
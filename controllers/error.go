@@ -0,0 +1,91 @@
+package controllers
+
+import (
+	"errors"
+	"log"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/odink789/project-management/services"
+	"github.com/odink789/project-management/utils"
+)
+
+//mapping error dari service ke response http dilakukan di satu tempat yaitu ErrorHandler
+//controller cukup return serviceError("Gagal Xxx", err)
+
+// requestError membawa pesan dari controller bersama error asli nya ke ErrorHandler
+type requestError struct {
+	message string
+	err     error
+}
+
+func (e *requestError) Error() string {
+	return e.message + ": " + e.err.Error()
+}
+
+func (e *requestError) Unwrap() error {
+	return e.err
+}
+
+func serviceError(message string, err error) error {
+	return &requestError{message: message, err: err}
+}
+
+// ErrorHandler dipasang di fiber.Config, error yang tidak dikenali dianggap internal
+// dan pesan asli nya hanya di log supaya error database tidak bocor ke client
+func ErrorHandler(ctx *fiber.Ctx, err error) error {
+	message := "Request Gagal"
+	var reqErr *requestError
+	if errors.As(err, &reqErr) {
+		message = reqErr.message
+		err = reqErr.err
+	}
+
+	var fiberErr *fiber.Error
+	var stale *services.StaleOrderError
+	var quota *services.QuotaExceededError
+	switch {
+	case errors.As(err, &fiberErr):
+		return utils.Fail(ctx, fiberErr.Code, "", message, fiberErr.Message, nil, nil)
+	case errors.As(err, &stale):
+		return utils.Fail(ctx, fiber.StatusConflict, "stale_order", message, err.Error(), stale.Current, nil)
+	case errors.As(err, &quota):
+		return utils.Fail(ctx, fiber.StatusRequestEntityTooLarge, "quota_exceeded", message, err.Error(), quota, nil)
+	case errors.Is(err, utils.ErrInvalidSignature):
+		return utils.Fail(ctx, fiber.StatusForbidden, "invalid_signature", message, err.Error(), nil, nil)
+	case errors.Is(err, utils.ErrSignatureExpired):
+		return utils.Fail(ctx, fiber.StatusForbidden, "signature_expired", message, err.Error(), nil, nil)
+	}
+
+	status := errorStatus(err)
+	if status == fiber.StatusInternalServerError {
+		log.Printf("%s %s: %v", ctx.Method(), ctx.Path(), err)
+		return utils.Fail(ctx, status, utils.CodeInternal, message, "internal server error", nil, nil)
+	}
+
+	var domainErr *services.Error
+	if errors.As(err, &domainErr) {
+		var details interface{}
+		if len(domainErr.Fields) > 0 {
+			details = domainErr.Fields
+		}
+		return utils.Fail(ctx, status, domainErr.Code, message, err.Error(), nil, details)
+	}
+	return utils.Fail(ctx, status, "", message, err.Error(), nil, nil)
+}
+
+func errorStatus(err error) int {
+	switch {
+	case errors.Is(err, services.ErrValidation):
+		return fiber.StatusBadRequest
+	case errors.Is(err, services.ErrUnauthorized):
+		return fiber.StatusUnauthorized
+	case errors.Is(err, services.ErrForbidden):
+		return fiber.StatusForbidden
+	case errors.Is(err, services.ErrNotFound):
+		return fiber.StatusNotFound
+	case errors.Is(err, services.ErrConflict):
+		return fiber.StatusConflict
+	default:
+		return fiber.StatusInternalServerError
+	}
+}
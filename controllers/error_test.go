@@ -0,0 +1,91 @@
+package controllers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/odink789/project-management/policies"
+	"github.com/odink789/project-management/services"
+)
+
+func doErrorRequest(t *testing.T, err error) (int, map[string]interface{}) {
+	t.Helper()
+	app := fiber.New(fiber.Config{ErrorHandler: ErrorHandler})
+	app.Get("/", func(c *fiber.Ctx) error {
+		return serviceError("Gagal Test", err)
+	})
+
+	resp, testErr := app.Test(httptest.NewRequest(fiber.MethodGet, "/", nil))
+	if testErr != nil {
+		t.Fatalf("app.Test() error = %v", testErr)
+	}
+	defer resp.Body.Close()
+
+	body := map[string]interface{}{}
+	json.NewDecoder(resp.Body).Decode(&body)
+	return resp.StatusCode, body
+}
+
+func TestErrorHandler(t *testing.T) {
+	tests := []struct {
+		name   string
+		err    error
+		status int
+		code   string
+	}{
+		{"not found", services.ErrCardNotFound, fiber.StatusNotFound, "card_not_found"},
+		{"conflict", services.ErrEmailTaken, fiber.StatusConflict, "email_taken"},
+		{"unauthorized", services.ErrInvalidCredentials, fiber.StatusUnauthorized, "invalid_credentials"},
+		{"forbidden", policies.ErrForbidden, fiber.StatusForbidden, "forbidden"},
+		{"stale order", &services.StaleOrderError{}, fiber.StatusConflict, "stale_order"},
+		{"quota", &services.QuotaExceededError{Scope: services.QuotaScopeFile, Limit: 10}, fiber.StatusRequestEntityTooLarge, "quota_exceeded"},
+		{"fiber error", fiber.ErrMethodNotAllowed, fiber.StatusMethodNotAllowed, "method_not_allowed"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status, body := doErrorRequest(t, tt.err)
+			if status != tt.status {
+				t.Errorf("status = %d, want %d", status, tt.status)
+			}
+			if body["error_code"] != tt.code {
+				t.Errorf("error_code = %v, want %s", body["error_code"], tt.code)
+			}
+			if body["message"] != "Gagal Test" {
+				t.Errorf("message = %v", body["message"])
+			}
+		})
+	}
+}
+
+func TestErrorHandler_ValidationDetails(t *testing.T) {
+	err := &services.Error{
+		Kind:    services.ErrValidation,
+		Code:    "validation_failed",
+		Message: "title is required",
+		Fields:  []services.FieldError{{Field: "title", Message: "title is required"}},
+	}
+
+	status, body := doErrorRequest(t, err)
+	if status != fiber.StatusBadRequest || body["error_code"] != "validation_failed" {
+		t.Fatalf("got %d %v", status, body)
+	}
+	details, ok := body["details"].([]interface{})
+	if !ok || len(details) != 1 || details[0].(map[string]interface{})["field"] != "title" {
+		t.Errorf("details = %v", body["details"])
+	}
+}
+
+func TestErrorHandler_InternalErrorIsHidden(t *testing.T) {
+	status, body := doErrorRequest(t, errors.New(`pq: relation "users" does not exist`))
+	if status != fiber.StatusInternalServerError || body["error_code"] != "internal_error" {
+		t.Fatalf("got %d %v", status, body)
+	}
+	if strings.Contains(body["error"].(string), "relation") {
+		t.Errorf("internal error leaked: %v", body["error"])
+	}
+}
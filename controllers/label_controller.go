@@ -0,0 +1,107 @@
+package controllers
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/odink789/project-management/dto"
+	"github.com/odink789/project-management/services"
+	"github.com/odink789/project-management/utils"
+)
+
+type LabelController struct {
+	service services.LabelService
+}
+
+func NewLabelController(s services.LabelService) *LabelController {
+	return &LabelController{service: s}
+}
+
+func (c *LabelController) GetLabels(ctx *fiber.Ctx) error {
+	principal, _ := utils.GetPrincipal(ctx)
+
+	labels, err := c.service.GetLabels(ctx.UserContext(), principal, ctx.Params("id"))
+	if err != nil {
+		return serviceError("Gagal Mengambil Label", err)
+	}
+
+	return utils.Success(ctx, "Get Labels Success", dto.NewLabelResponses(labels))
+}
+
+func (c *LabelController) Create(ctx *fiber.Ctx) error {
+	principal, _ := utils.GetPrincipal(ctx)
+	var body dto.LabelRequest
+
+	if err := parseBody(ctx, &body); err != nil {
+		return err
+	}
+
+	label := body.ToModel()
+	if err := c.service.Create(ctx.UserContext(), principal, ctx.Params("id"), label); err != nil {
+		return serviceError("Gagal Membuat Label", err)
+	}
+
+	return utils.Created(ctx, "Create Label Success", dto.NewLabelResponse(label))
+}
+
+func (c *LabelController) Update(ctx *fiber.Ctx) error {
+	principal, _ := utils.GetPrincipal(ctx)
+	var body dto.LabelRequest
+
+	if err := parseBody(ctx, &body); err != nil {
+		return err
+	}
+
+	label, err := c.service.Update(ctx.UserContext(), principal, ctx.Params("id"), ctx.Params("labelId"), body.ToModel())
+	if err != nil {
+		return serviceError("Gagal Update Label", err)
+	}
+
+	return utils.Success(ctx, "Update Label Success", dto.NewLabelResponse(label))
+}
+
+func (c *LabelController) Delete(ctx *fiber.Ctx) error {
+	principal, _ := utils.GetPrincipal(ctx)
+
+	if err := c.service.Delete(ctx.UserContext(), principal, ctx.Params("id"), ctx.Params("labelId")); err != nil {
+		return serviceError("Gagal Menghapus Label", err)
+	}
+
+	return utils.Success(ctx, "Delete Label Success", nil)
+}
+
+func (c *LabelController) GetCardLabels(ctx *fiber.Ctx) error {
+	principal, _ := utils.GetPrincipal(ctx)
+
+	labels, err := c.service.GetCardLabels(ctx.UserContext(), principal, ctx.Params("id"))
+	if err != nil {
+		return serviceError("Gagal Mengambil Label", err)
+	}
+
+	return utils.Success(ctx, "Get Card Labels Success", dto.NewLabelResponses(labels))
+}
+
+func (c *LabelController) Attach(ctx *fiber.Ctx) error {
+	principal, _ := utils.GetPrincipal(ctx)
+
+	var body dto.AttachLabelRequest
+
+	if err := parseBody(ctx, &body); err != nil {
+		return err
+	}
+
+	label, err := c.service.Attach(ctx.UserContext(), principal, ctx.Params("id"), body.LabelID)
+	if err != nil {
+		return serviceError("Gagal Memasang Label", err)
+	}
+
+	return utils.Success(ctx, "Attach Label Success", dto.NewLabelResponse(label))
+}
+
+func (c *LabelController) Detach(ctx *fiber.Ctx) error {
+	principal, _ := utils.GetPrincipal(ctx)
+
+	if err := c.service.Detach(ctx.UserContext(), principal, ctx.Params("id"), ctx.Params("labelId")); err != nil {
+		return serviceError("Gagal Melepas Label", err)
+	}
+
+	return utils.Success(ctx, "Detach Label Success", nil)
+}
@@ -0,0 +1,87 @@
+package controllers
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/odink789/project-management/dto"
+	"github.com/odink789/project-management/services"
+	"github.com/odink789/project-management/utils"
+)
+
+type ListController struct {
+	service services.ListService
+}
+
+func NewListController(s services.ListService) *ListController {
+	return &ListController{service: s}
+}
+
+func (c *ListController) GetLists(ctx *fiber.Ctx) error {
+	principal, _ := utils.GetPrincipal(ctx)
+
+	lists, err := c.service.GetLists(ctx.UserContext(), principal, ctx.Params("boardId"))
+	if err != nil {
+		return serviceError("Gagal Mengambil List", err)
+	}
+
+	return utils.Success(ctx, "Get Lists Success", dto.NewOrderedListsResponse(lists))
+}
+
+func (c *ListController) Create(ctx *fiber.Ctx) error {
+	principal, _ := utils.GetPrincipal(ctx)
+
+	var body dto.CreateListRequest
+
+	if err := parseBody(ctx, &body); err != nil {
+		return err
+	}
+
+	list := body.ToModel()
+	if err := c.service.Create(ctx.UserContext(), principal, ctx.Params("boardId"), list, body.Index()); err != nil {
+		return serviceError("Gagal Membuat List", err)
+	}
+
+	return utils.Created(ctx, "Create List Success", dto.NewListResponse(list))
+}
+
+func (c *ListController) Rename(ctx *fiber.Ctx) error {
+	principal, _ := utils.GetPrincipal(ctx)
+	var body dto.RenameListRequest
+
+	if err := parseBody(ctx, &body); err != nil {
+		return err
+	}
+
+	list, err := c.service.Rename(ctx.UserContext(), principal, ctx.Params("boardId"), ctx.Params("listId"), body.Title)
+	if err != nil {
+		return serviceError("Gagal Update List", err)
+	}
+
+	return utils.Success(ctx, "Update List Success", dto.NewListResponse(list))
+}
+
+func (c *ListController) Delete(ctx *fiber.Ctx) error {
+	principal, _ := utils.GetPrincipal(ctx)
+
+	if err := c.service.Delete(ctx.UserContext(), principal, ctx.Params("boardId"), ctx.Params("listId")); err != nil {
+		return serviceError("Gagal Menghapus List", err)
+	}
+
+	return utils.Success(ctx, "Delete List Success", nil)
+}
+
+func (c *ListController) Reorder(ctx *fiber.Ctx) error {
+	principal, _ := utils.GetPrincipal(ctx)
+
+	var body dto.ReorderListsRequest
+
+	if err := parseBody(ctx, &body); err != nil {
+		return err
+	}
+
+	lists, err := c.service.Reorder(ctx.UserContext(), principal, ctx.Params("boardId"), body.ListOrder, body.Version)
+	if err != nil {
+		return serviceError("Gagal Mengurutkan List", err)
+	}
+
+	return utils.Success(ctx, "Reorder Lists Success", dto.NewOrderedListsResponse(lists))
+}
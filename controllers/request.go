@@ -0,0 +1,17 @@
+package controllers
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/odink789/project-management/dto"
+)
+
+// parseBody membaca body ke dto lalu menjalankan validasi nya, error nya diteruskan ke ErrorHandler
+func parseBody(ctx *fiber.Ctx, req interface{}) error {
+	if err := ctx.BodyParser(req); err != nil {
+		return serviceError("Gagal Parsing Data", fiber.NewError(fiber.StatusBadRequest, err.Error()))
+	}
+	if err := dto.Validate(req); err != nil {
+		return serviceError("Data Tidak Valid", err)
+	}
+	return nil
+}
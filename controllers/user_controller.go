@@ -2,7 +2,7 @@ package controllers
 
 import (
 	"github.com/gofiber/fiber/v2"
-	"github.com/odink789/project-management/models"
+	"github.com/odink789/project-management/dto"
 	"github.com/odink789/project-management/services"
 	"github.com/odink789/project-management/utils"
 )
@@ -16,16 +16,158 @@ func NewUserController(s services.UserService) *UserController {
 }
 
 func (c *UserController) Register(ctx *fiber.Ctx) error {
-	user := new(models.User)
+	var body dto.RegisterRequest
 
-	if err := ctx.BodyParser(user); err != nil {
-		return utils.BadRequest(ctx, "Gagal Parsing Data", err.Error())
+	if err := parseBody(ctx, &body); err != nil {
+		return err
 	}
 
-	if err := c.service.Register(user); err != nil {
-		return utils.BadRequest(ctx, "Registrasi Gagal", err.Error())
+	user := body.ToModel()
+	if err := c.service.Register(ctx.UserContext(), user); err != nil {
+		return serviceError("Registrasi Gagal", err)
 	}
 
-	return utils.Success(ctx, "Register Success", user)
+	return utils.Success(ctx, "Register Success", dto.NewUserResponse(user))
 
 }
+
+func (c *UserController) Login(ctx *fiber.Ctx) error {
+	var body dto.LoginRequest
+
+	if err := parseBody(ctx, &body); err != nil {
+		return err
+	}
+
+	//kalau client tidak kirim nama device, pakai user agent sebagai label sesi
+	if body.DeviceLabel == "" {
+		body.DeviceLabel = ctx.Get(fiber.HeaderUserAgent)
+	}
+
+	user, token, err := c.service.Login(ctx.UserContext(), body.Email, body.Password, body.DeviceLabel)
+	if err != nil {
+		return serviceError("Login Gagal", err)
+	}
+
+	return utils.Success(ctx, "Login Success", dto.NewLoginResponse(user, token))
+}
+
+func (c *UserController) Refresh(ctx *fiber.Ctx) error {
+	var body dto.RefreshTokenRequest
+
+	if err := parseBody(ctx, &body); err != nil {
+		return err
+	}
+
+	token, err := c.service.RefreshToken(ctx.UserContext(), body.RefreshToken)
+	if err != nil {
+		return serviceError("Refresh Token Gagal", err)
+	}
+
+	return utils.Success(ctx, "Refresh Token Success", token)
+}
+
+func (c *UserController) Logout(ctx *fiber.Ctx) error {
+	principal, _ := utils.GetPrincipal(ctx)
+
+	var body dto.RefreshTokenRequest
+
+	if err := parseBody(ctx, &body); err != nil {
+		return err
+	}
+
+	if err := c.service.Logout(ctx.UserContext(), principal.UserID, body.RefreshToken); err != nil {
+		return serviceError("Logout Gagal", err)
+	}
+
+	return utils.Success(ctx, "Logout Success", nil)
+}
+
+func (c *UserController) LogoutAll(ctx *fiber.Ctx) error {
+	principal, _ := utils.GetPrincipal(ctx)
+
+	if err := c.service.LogoutAll(ctx.UserContext(), principal.UserID); err != nil {
+		return serviceError("Logout Gagal", err)
+	}
+
+	return utils.Success(ctx, "Logout All Sessions Success", nil)
+}
+
+func (c *UserController) Sessions(ctx *fiber.Ctx) error {
+	principal, _ := utils.GetPrincipal(ctx)
+
+	sessions, err := c.service.Sessions(ctx.UserContext(), principal.UserID)
+	if err != nil {
+		return serviceError("Gagal Mengambil Sesi", err)
+	}
+
+	return utils.Success(ctx, "Get Sessions Success", dto.NewSessionResponses(sessions))
+}
+
+func (c *UserController) VerifyEmail(ctx *fiber.Ctx) error {
+	var body dto.VerifyEmailRequest
+
+	if err := parseBody(ctx, &body); err != nil {
+		return err
+	}
+
+	if err := c.service.VerifyEmail(ctx.UserContext(), body.Token); err != nil {
+		return serviceError("Verifikasi Email Gagal", err)
+	}
+
+	return utils.Success(ctx, "Verify Email Success", nil)
+}
+
+func (c *UserController) ResendVerification(ctx *fiber.Ctx) error {
+	principal, _ := utils.GetPrincipal(ctx)
+
+	if err := c.service.ResendVerification(ctx.UserContext(), principal.UserID); err != nil {
+		return serviceError("Gagal Mengirim Email Verifikasi", err)
+	}
+
+	return utils.Success(ctx, "Verification Email Sent", nil)
+}
+
+func (c *UserController) ForgotPassword(ctx *fiber.Ctx) error {
+	var body dto.ForgotPasswordRequest
+
+	if err := parseBody(ctx, &body); err != nil {
+		return err
+	}
+
+	if err := c.service.ForgotPassword(ctx.UserContext(), body.Email); err != nil {
+		return serviceError("Gagal Memproses Lupa Password", err)
+	}
+
+	//pesan sama untuk email yang terdaftar maupun tidak
+	return utils.Success(ctx, "If the email is registered, a password reset link has been sent", nil)
+}
+
+func (c *UserController) ResetPassword(ctx *fiber.Ctx) error {
+	var body dto.ResetPasswordRequest
+
+	if err := parseBody(ctx, &body); err != nil {
+		return err
+	}
+
+	if err := c.service.ResetPassword(ctx.UserContext(), body.Token, body.Password); err != nil {
+		return serviceError("Reset Password Gagal", err)
+	}
+
+	return utils.Success(ctx, "Reset Password Success", nil)
+}
+
+func (c *UserController) ChangePassword(ctx *fiber.Ctx) error {
+	principal, _ := utils.GetPrincipal(ctx)
+
+	var body dto.ChangePasswordRequest
+
+	if err := parseBody(ctx, &body); err != nil {
+		return err
+	}
+
+	if err := c.service.ChangePassword(ctx.UserContext(), principal.UserID, body.CurrentPassword, body.NewPassword); err != nil {
+		return serviceError("Ganti Password Gagal", err)
+	}
+
+	return utils.Success(ctx, "Change Password Success", nil)
+}
@@ -0,0 +1,187 @@
+package backfill
+
+import (
+	"context"
+	"log"
+	"slices"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/odink789/project-management/models"
+	"github.com/odink789/project-management/utils"
+	"gorm.io/gorm"
+)
+
+//migrasi data urutan lama (list_order / card_order) ke kolom rank
+//hanya board / list yang masih punya row dengan rank kosong yang diproses, jadi aman dijalankan berulang kali
+//rank yang sudah ada tidak diubah karena card_order tidak lagi ditulis setelah ORDERING_MODE=rank
+
+type rankItem struct {
+	InternalID int64
+	PublicID   uuid.UUID
+	Rank       string
+}
+
+func BackfillRanks(ctx context.Context, db *gorm.DB) error {
+	db = db.WithContext(ctx)
+
+	var listPositions []models.ListPosition
+	if err := db.Find(&listPositions).Error; err != nil {
+		return err
+	}
+
+	for _, position := range listPositions {
+		var lists []models.List
+		if err := db.Where("board_internal_id = ?", position.BoardID).Order("created_at ASC").Find(&lists).Error; err != nil {
+			return err
+		}
+
+		items := make([]rankItem, len(lists))
+		for i, l := range lists {
+			items[i] = rankItem{l.InternalID, l.PublicID, l.Rank}
+		}
+		if err := writeRanks(db, &models.List{}, fillMissingRanks(items, position.ListOrder)); err != nil {
+			return err
+		}
+	}
+
+	var cardPositions []models.CardPosition
+	if err := db.Find(&cardPositions).Error; err != nil {
+		return err
+	}
+
+	for _, position := range cardPositions {
+		var cards []models.Card
+		if err := db.Where("list_internal_id = ?", position.ListID).Order("created_at ASC").Find(&cards).Error; err != nil {
+			return err
+		}
+
+		items := make([]rankItem, len(cards))
+		for i, c := range cards {
+			items[i] = rankItem{c.InternalID, c.PublicID, c.Rank}
+		}
+		if err := writeRanks(db, &models.Card{}, fillMissingRanks(items, position.CardOrder)); err != nil {
+			return err
+		}
+	}
+
+	log.Println("Ranks backfilled successfully")
+	return nil
+}
+
+// fillMissingRanks mengembalikan rank untuk item yang rank nya masih kosong (key nya internal id)
+// item tanpa rank ditaruh tepat setelah item ber rank yang ada sebelum nya di urutan lama,
+// item ber rank tetap di urutan rank nya. kalau rank tidak muat disisipkan, semua item di rebalance
+func fillMissingRanks(items []rankItem, legacyOrder []uuid.UUID) map[int64]string {
+	if !slices.ContainsFunc(items, func(item rankItem) bool { return item.Rank == "" }) {
+		return nil
+	}
+
+	byID := make(map[int64]rankItem, len(items))
+	var ranked []rankItem
+	for _, item := range items {
+		byID[item.InternalID] = item
+		if item.Rank != "" {
+			ranked = append(ranked, item)
+		}
+	}
+	slices.SortStableFunc(ranked, func(a, b rankItem) int {
+		if c := strings.Compare(a.Rank, b.Rank); c != 0 {
+			return c
+		}
+		return strings.Compare(a.PublicID.String(), b.PublicID.String())
+	})
+
+	//item tanpa rank dikelompokkan berdasarkan item ber rank sebelum nya, 0 berarti paling awal
+	following := make(map[int64][]int64)
+	var anchor int64
+	for _, id := range orderIDs(items, legacyOrder) {
+		if byID[id].Rank != "" {
+			anchor = id
+			continue
+		}
+		following[anchor] = append(following[anchor], id)
+	}
+
+	order := following[0]
+	for _, item := range ranked {
+		order = append(order, item.InternalID)
+		order = append(order, following[item.InternalID]...)
+	}
+
+	if ranks, ok := rankBetweenNeighbours(order, byID); ok {
+		return ranks
+	}
+
+	sequence := utils.RankSequence(len(order))
+	ranks := make(map[int64]string, len(order))
+	for i, id := range order {
+		ranks[id] = sequence[i]
+	}
+	return ranks
+}
+
+// rank item kosong dibuat di antara rank tetangga nya, false kalau ada yang tidak muat
+func rankBetweenNeighbours(order []int64, byID map[int64]rankItem) (map[int64]string, bool) {
+	ranks := make(map[int64]string)
+	before := ""
+	for i, id := range order {
+		if rank := byID[id].Rank; rank != "" {
+			before = rank
+			continue
+		}
+
+		after := ""
+		for _, nextID := range order[i+1:] {
+			if rank := byID[nextID].Rank; rank != "" {
+				after = rank
+				break
+			}
+		}
+
+		rank, err := utils.RankBetween(before, after)
+		if err != nil || len(rank) > utils.MaxRankLength {
+			return nil, false
+		}
+		ranks[id] = rank
+		before = rank
+	}
+	return ranks, true
+}
+
+// orderIDs mengurutkan internal id sesuai order, item yang tidak ada di order ditaruh paling akhir
+func orderIDs(items []rankItem, order []uuid.UUID) []int64 {
+	byPublicID := make(map[uuid.UUID]int64, len(items))
+	for _, item := range items {
+		byPublicID[item.PublicID] = item.InternalID
+	}
+
+	ids := make([]int64, 0, len(items))
+	seen := make(map[int64]bool, len(items))
+	for _, publicID := range order {
+		if internalID, ok := byPublicID[publicID]; ok && !seen[internalID] {
+			ids = append(ids, internalID)
+			seen[internalID] = true
+		}
+	}
+	for _, item := range items {
+		if !seen[item.InternalID] {
+			ids = append(ids, item.InternalID)
+		}
+	}
+	return ids
+}
+
+func writeRanks(db *gorm.DB, model interface{}, ranks map[int64]string) error {
+	if len(ranks) == 0 {
+		return nil
+	}
+	return db.Transaction(func(tx *gorm.DB) error {
+		for id, rank := range ranks {
+			if err := tx.Model(model).Where("internal_id = ?", id).Update("rank", rank).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
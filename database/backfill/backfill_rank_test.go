@@ -0,0 +1,77 @@
+package backfill
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+// urutkan seperti repositories (rank lalu public id) dan kembalikan nama item
+func sortedNames(items []rankItem, ranks map[int64]string, names map[int64]string) []string {
+	sorted := slices.Clone(items)
+	for i := range sorted {
+		if rank, ok := ranks[sorted[i].InternalID]; ok {
+			sorted[i].Rank = rank
+		}
+	}
+	slices.SortStableFunc(sorted, func(a, b rankItem) int {
+		if c := strings.Compare(a.Rank, b.Rank); c != 0 {
+			return c
+		}
+		return strings.Compare(a.PublicID.String(), b.PublicID.String())
+	})
+
+	result := make([]string, len(sorted))
+	for i, item := range sorted {
+		result[i] = names[item.InternalID]
+	}
+	return result
+}
+
+func TestFillMissingRanks(t *testing.T) {
+	names := map[int64]string{1: "a", 2: "b", 3: "c", 4: "new", 5: "orphan"}
+	ids := map[int64]uuid.UUID{}
+	for id := range names {
+		ids[id] = uuid.New()
+	}
+
+	//card_order lama : a b c new, tapi setelah ORDERING_MODE=rank c dipindah ke paling atas lewat rank
+	//new dan orphan belum punya rank, orphan tidak ada di card_order
+	items := []rankItem{
+		{1, ids[1], "M"},
+		{2, ids[2], "T"},
+		{3, ids[3], "F"},
+		{4, ids[4], ""},
+		{5, ids[5], ""},
+	}
+	legacy := []uuid.UUID{ids[1], ids[2], ids[3], ids[4]}
+
+	ranks := fillMissingRanks(items, legacy)
+	if len(ranks) != 2 {
+		t.Fatalf("fillMissingRanks() = %v, only missing ranks should be assigned", ranks)
+	}
+
+	//urutan rank c a b tidak boleh berubah, new ikut setelah c (sebelum nya di card_order)
+	want := []string{"c", "new", "orphan", "a", "b"}
+	if got := sortedNames(items, ranks, names); !slices.Equal(got, want) {
+		t.Errorf("order = %v, want %v", got, want)
+	}
+
+	if ranks := fillMissingRanks(items[:3], legacy); ranks != nil {
+		t.Errorf("fillMissingRanks() without missing ranks = %v, want nil", ranks)
+	}
+}
+
+func TestFillMissingRanks_AllMissing(t *testing.T) {
+	names := map[int64]string{1: "a", 2: "b", 3: "c"}
+	ids := map[int64]uuid.UUID{1: uuid.New(), 2: uuid.New(), 3: uuid.New()}
+	items := []rankItem{{1, ids[1], ""}, {2, ids[2], ""}, {3, ids[3], ""}}
+
+	ranks := fillMissingRanks(items, []uuid.UUID{ids[3], ids[1], ids[2]})
+	want := []string{"c", "a", "b"}
+	if got := sortedNames(items, ranks, names); !slices.Equal(got, want) {
+		t.Errorf("order = %v, want %v", got, want)
+	}
+}
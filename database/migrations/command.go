@@ -0,0 +1,64 @@
+package migrations
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+
+	"gorm.io/gorm"
+)
+
+const usage = "usage: migrate up | down [steps] | status"
+
+// Command menjalankan subcommand "migrate" dari cli, contoh : go run . migrate down 1
+func Command(db *gorm.DB, args []string, out io.Writer) error {
+	if len(args) == 0 {
+		return errors.New(usage)
+	}
+
+	switch args[0] {
+	case "up":
+		applied, err := Up(db)
+		for _, migration := range applied {
+			fmt.Fprintf(out, "applied %d_%s\n", migration.Version, migration.Name)
+		}
+		if err == nil && len(applied) == 0 {
+			fmt.Fprintln(out, "schema is up to date")
+		}
+		return err
+
+	case "down":
+		steps := 1
+		if len(args) > 1 {
+			n, err := strconv.Atoi(args[1])
+			if err != nil {
+				return fmt.Errorf("invalid steps %q", args[1])
+			}
+			steps = n
+		}
+
+		reverted, err := Down(db, steps)
+		for _, migration := range reverted {
+			fmt.Fprintf(out, "reverted %d_%s\n", migration.Version, migration.Name)
+		}
+		return err
+
+	case "status":
+		statuses, err := Status(db)
+		if err != nil {
+			return err
+		}
+		for _, status := range statuses {
+			appliedAt := "pending"
+			if status.AppliedAt != nil {
+				appliedAt = status.AppliedAt.Format("2006-01-02 15:04:05")
+			}
+			fmt.Fprintf(out, "%04d_%-30s %s\n", status.Version, status.Name, appliedAt)
+		}
+		return nil
+
+	default:
+		return errors.New(usage)
+	}
+}
@@ -0,0 +1,294 @@
+package migrations
+
+import (
+	"embed"
+	"errors"
+	"fmt"
+	"io/fs"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+//migrasi schema database, file nya ada di folder sql/<dialect> dengan format <version>_<nama>.up.sql / .down.sql
+//setiap dialect (postgres, sqlite) harus punya version yang sama
+//version yang sudah dijalankan dicatat di tabel schema_migrations
+
+//go:embed sql
+var files embed.FS
+
+type Migration struct {
+	Version int64
+	Name    string
+	Up      string
+	Down    string
+}
+
+// MigrationStatus dipakai oleh perintah "migrate status", AppliedAt nil berarti belum dijalankan
+type MigrationStatus struct {
+	Version   int64
+	Name      string
+	AppliedAt *time.Time
+}
+
+type schemaMigration struct {
+	Version   int64     `gorm:"primaryKey;autoIncrement:false"`
+	Name      string    `gorm:"not null"`
+	AppliedAt time.Time `gorm:"not null"`
+}
+
+func (schemaMigration) TableName() string {
+	return "schema_migrations"
+}
+
+// Load membaca semua migrasi yang di embed untuk dialect tertentu, diurutkan dari version terkecil
+func Load(dialect string) ([]Migration, error) {
+	if _, err := fs.Stat(files, "sql/"+dialect); err != nil {
+		return nil, fmt.Errorf("no migrations for database dialect %q", dialect)
+	}
+	return load(files, "sql/"+dialect)
+}
+
+func load(fsys fs.FS, dir string) ([]Migration, error) {
+	entries, err := fs.ReadDir(fsys, dir)
+	if err != nil {
+		return nil, err
+	}
+
+	byVersion := map[int64]*Migration{}
+	for _, entry := range entries {
+		name := entry.Name()
+		direction := ""
+		switch {
+		case strings.HasSuffix(name, ".up.sql"):
+			direction = "up"
+		case strings.HasSuffix(name, ".down.sql"):
+			direction = "down"
+		default:
+			continue
+		}
+
+		base := strings.TrimSuffix(name, "."+direction+".sql")
+		prefix, label, ok := strings.Cut(base, "_")
+		if !ok {
+			return nil, fmt.Errorf("invalid migration file name %s", name)
+		}
+		version, err := strconv.ParseInt(prefix, 10, 64)
+		if err != nil || version <= 0 {
+			return nil, fmt.Errorf("invalid migration version in %s", name)
+		}
+
+		content, err := fs.ReadFile(fsys, dir+"/"+name)
+		if err != nil {
+			return nil, err
+		}
+
+		migration, ok := byVersion[version]
+		if !ok {
+			migration = &Migration{Version: version, Name: label}
+			byVersion[version] = migration
+		}
+		if migration.Name != label {
+			return nil, fmt.Errorf("migration %d has different names: %s and %s", version, migration.Name, label)
+		}
+		if direction == "up" {
+			migration.Up = string(content)
+		} else {
+			migration.Down = string(content)
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, migration := range byVersion {
+		if migration.Up == "" || migration.Down == "" {
+			return nil, fmt.Errorf("migration %d_%s must have both up and down files", migration.Version, migration.Name)
+		}
+		migrations = append(migrations, *migration)
+	}
+	sort.Slice(migrations, func(i, j int) bool { return migrations[i].Version < migrations[j].Version })
+	return migrations, nil
+}
+
+// Up menjalankan semua migrasi yang belum tercatat, setiap migrasi dalam satu transaksi
+func Up(db *gorm.DB) ([]Migration, error) {
+	migrations, err := Load(db.Dialector.Name())
+	if err != nil {
+		return nil, err
+	}
+	if err := ensureTable(db); err != nil {
+		return nil, err
+	}
+
+	var applied []Migration
+	for _, migration := range migrations {
+		ran, err := run(db, migration.Version, func(tx *gorm.DB, done bool) (bool, error) {
+			if done {
+				return false, nil
+			}
+			if err := execScript(tx, migration.Up); err != nil {
+				return false, err
+			}
+			return true, tx.Create(&schemaMigration{Version: migration.Version, Name: migration.Name, AppliedAt: time.Now()}).Error
+		})
+		if err != nil {
+			return applied, fmt.Errorf("migration %d_%s failed: %w", migration.Version, migration.Name, err)
+		}
+		if ran {
+			applied = append(applied, migration)
+		}
+	}
+	return applied, nil
+}
+
+// Down membatalkan sejumlah steps migrasi terakhir yang sudah dijalankan
+func Down(db *gorm.DB, steps int) ([]Migration, error) {
+	if steps <= 0 {
+		return nil, errors.New("steps must be greater than zero")
+	}
+
+	migrations, err := Load(db.Dialector.Name())
+	if err != nil {
+		return nil, err
+	}
+	if err := ensureTable(db); err != nil {
+		return nil, err
+	}
+
+	byVersion := make(map[int64]Migration, len(migrations))
+	for _, migration := range migrations {
+		byVersion[migration.Version] = migration
+	}
+
+	var records []schemaMigration
+	if err := db.Order("version DESC").Limit(steps).Find(&records).Error; err != nil {
+		return nil, err
+	}
+
+	var reverted []Migration
+	for _, record := range records {
+		migration, ok := byVersion[record.Version]
+		if !ok {
+			return reverted, fmt.Errorf("migration %d_%s is applied but its files are missing", record.Version, record.Name)
+		}
+
+		ran, err := run(db, migration.Version, func(tx *gorm.DB, done bool) (bool, error) {
+			if !done {
+				return false, nil
+			}
+			if err := execScript(tx, migration.Down); err != nil {
+				return false, err
+			}
+			return true, tx.Delete(&schemaMigration{}, migration.Version).Error
+		})
+		if err != nil {
+			return reverted, fmt.Errorf("rollback %d_%s failed: %w", migration.Version, migration.Name, err)
+		}
+		if ran {
+			reverted = append(reverted, migration)
+		}
+	}
+	return reverted, nil
+}
+
+func Status(db *gorm.DB) ([]MigrationStatus, error) {
+	migrations, err := Load(db.Dialector.Name())
+	if err != nil {
+		return nil, err
+	}
+	if err := ensureTable(db); err != nil {
+		return nil, err
+	}
+
+	var records []schemaMigration
+	if err := db.Find(&records).Error; err != nil {
+		return nil, err
+	}
+	appliedAt := make(map[int64]time.Time, len(records))
+	for _, record := range records {
+		appliedAt[record.Version] = record.AppliedAt
+	}
+
+	statuses := make([]MigrationStatus, 0, len(migrations))
+	for _, migration := range migrations {
+		status := MigrationStatus{Version: migration.Version, Name: migration.Name}
+		if at, ok := appliedAt[migration.Version]; ok {
+			status.AppliedAt = &at
+		}
+		statuses = append(statuses, status)
+	}
+	return statuses, nil
+}
+
+func ensureTable(db *gorm.DB) error {
+	//driver sqlite hanya membaca kolom DATETIME sebagai time.Time
+	timestamp := "TIMESTAMPTZ"
+	if db.Dialector.Name() == "sqlite" {
+		timestamp = "DATETIME"
+	}
+	return db.Exec(`CREATE TABLE IF NOT EXISTS schema_migrations (
+		version BIGINT PRIMARY KEY,
+		name TEXT NOT NULL,
+		applied_at ` + timestamp + ` NOT NULL
+	)`).Error
+}
+
+// run membuka transaksi, lock supaya dua instance yang start bersamaan tidak menjalankan migrasi yang sama,
+// lalu cek ulang apakah version sudah tercatat sebelum memanggil fn
+func run(db *gorm.DB, version int64, fn func(tx *gorm.DB, done bool) (bool, error)) (bool, error) {
+	ran := false
+	err := db.Transaction(func(tx *gorm.DB) error {
+		if db.Dialector.Name() == "postgres" {
+			if err := tx.Exec("SELECT pg_advisory_xact_lock(?)", lockKey).Error; err != nil {
+				return err
+			}
+		}
+
+		var count int64
+		if err := tx.Model(&schemaMigration{}).Where("version = ?", version).Count(&count).Error; err != nil {
+			return err
+		}
+
+		var err error
+		ran, err = fn(tx, count > 0)
+		return err
+	})
+	return ran, err
+}
+
+// key advisory lock khusus migrasi, angka bebas asal tidak dipakai lock lain
+const lockKey = 7_201_704
+
+// statement dipisah per ";" di akhir baris supaya tidak bergantung pada dukungan multi statement dari driver
+func execScript(tx *gorm.DB, script string) error {
+	for _, statement := range splitStatements(script) {
+		if err := tx.Exec(statement).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func splitStatements(script string) []string {
+	var statements []string
+	var current strings.Builder
+	for _, line := range strings.Split(script, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || strings.HasPrefix(trimmed, "--") {
+			continue
+		}
+
+		current.WriteString(line)
+		current.WriteString("\n")
+		if strings.HasSuffix(trimmed, ";") {
+			statements = append(statements, strings.TrimSpace(current.String()))
+			current.Reset()
+		}
+	}
+	if rest := strings.TrimSpace(current.String()); rest != "" {
+		statements = append(statements, rest)
+	}
+	return statements
+}
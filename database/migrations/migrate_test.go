@@ -0,0 +1,168 @@
+package migrations
+
+import (
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/odink789/project-management/config"
+)
+
+var dialects = []string{"postgres", "sqlite"}
+
+func TestLoad_EmbeddedMigrations(t *testing.T) {
+	var versions []string
+	for _, dialect := range dialects {
+		migrations, err := Load(dialect)
+		if err != nil {
+			t.Fatalf("Load(%s) error = %v", dialect, err)
+		}
+		if len(migrations) == 0 {
+			t.Fatalf("expected embedded %s migrations", dialect)
+		}
+
+		var names []string
+		for i, migration := range migrations {
+			if i > 0 && migration.Version <= migrations[i-1].Version {
+				t.Errorf("%s migration %d is not sorted", dialect, migration.Version)
+			}
+			if len(splitStatements(migration.Up)) == 0 || len(splitStatements(migration.Down)) == 0 {
+				t.Errorf("%s migration %d_%s has an empty script", dialect, migration.Version, migration.Name)
+			}
+			names = append(names, migration.Name)
+		}
+		versions = append(versions, strings.Join(names, ","))
+	}
+
+	//setiap dialect harus punya daftar migrasi yang sama
+	if versions[0] != versions[1] {
+		t.Errorf("dialects have different migrations: %v", versions)
+	}
+
+	if _, err := Load("mysql"); err == nil {
+		t.Error("expected error for unknown dialect")
+	}
+}
+
+// setiap tabel yang dibuat harus di drop lagi oleh migrasi down nya
+func TestLoad_DownDropsEveryTable(t *testing.T) {
+	for _, dialect := range dialects {
+		migrations, err := Load(dialect)
+		if err != nil {
+			t.Fatal(err)
+		}
+		assertDropsEveryTable(t, migrations)
+	}
+}
+
+func assertDropsEveryTable(t *testing.T, migrations []Migration) {
+	t.Helper()
+	for _, migration := range migrations {
+		for _, statement := range splitStatements(migration.Up) {
+			rest, ok := strings.CutPrefix(statement, "CREATE TABLE ")
+			if !ok {
+				continue
+			}
+			table := strings.Fields(rest)[0]
+			if !strings.Contains(migration.Down, "DROP TABLE IF EXISTS "+table+";") {
+				t.Errorf("migration %d_%s does not drop table %s", migration.Version, migration.Name, table)
+			}
+		}
+	}
+}
+
+func TestLoad_Invalid(t *testing.T) {
+	tests := map[string]fstest.MapFS{
+		"missing down": {
+			"sql/0001_init.up.sql": {Data: []byte("CREATE TABLE a (id INT);")},
+		},
+		"bad version": {
+			"sql/abc_init.up.sql":   {Data: []byte("CREATE TABLE a (id INT);")},
+			"sql/abc_init.down.sql": {Data: []byte("DROP TABLE a;")},
+		},
+		"different names": {
+			"sql/0001_init.up.sql":    {Data: []byte("CREATE TABLE a (id INT);")},
+			"sql/0001_other.down.sql": {Data: []byte("DROP TABLE a;")},
+		},
+	}
+
+	for name, fsys := range tests {
+		t.Run(name, func(t *testing.T) {
+			if _, err := load(fsys, "sql"); err == nil {
+				t.Error("expected error")
+			}
+		})
+	}
+}
+
+func TestSplitStatements(t *testing.T) {
+	script := `-- komentar
+CREATE TABLE a (
+    id INT
+);
+
+CREATE INDEX idx_a ON a (id);
+ALTER TABLE a ADD COLUMN name TEXT`
+
+	statements := splitStatements(script)
+	if len(statements) != 3 {
+		t.Fatalf("len = %d, want 3: %q", len(statements), statements)
+	}
+	if !strings.HasPrefix(statements[0], "CREATE TABLE a (") || !strings.HasSuffix(statements[0], ");") {
+		t.Errorf("statement 0 = %q", statements[0])
+	}
+	if statements[2] != "ALTER TABLE a ADD COLUMN name TEXT" {
+		t.Errorf("statement 2 = %q", statements[2])
+	}
+}
+
+func TestUpDown_SQLite(t *testing.T) {
+	db, err := config.OpenDB(&config.Config{DBDriver: config.DBDriverSQLite, DBPath: ":memory:"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	migrations, err := Load("sqlite")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	applied, err := Up(db)
+	if err != nil {
+		t.Fatalf("Up() error = %v", err)
+	}
+	if len(applied) != len(migrations) {
+		t.Errorf("Up() applied %d migrations, want %d", len(applied), len(migrations))
+	}
+	if !db.Migrator().HasTable("card_attachments") {
+		t.Error("card_attachments table should exist after Up()")
+	}
+
+	//dijalankan ulang tidak melakukan apa apa
+	if applied, err := Up(db); err != nil || len(applied) != 0 {
+		t.Errorf("second Up() = %d migrations, error = %v", len(applied), err)
+	}
+
+	reverted, err := Down(db, 1)
+	if err != nil {
+		t.Fatalf("Down() error = %v", err)
+	}
+	if len(reverted) != 1 || reverted[0].Version != migrations[len(migrations)-1].Version {
+		t.Errorf("Down() reverted %+v", reverted)
+	}
+
+	statuses, err := Status(db)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if last := statuses[len(statuses)-1]; last.AppliedAt != nil {
+		t.Errorf("last migration should be pending after Down(), got %+v", last)
+	}
+
+	if _, err := Down(db, len(migrations)); err != nil {
+		t.Fatalf("Down() all error = %v", err)
+	}
+	if db.Migrator().HasTable("users") {
+		t.Error("users table should be dropped after reverting everything")
+	}
+}
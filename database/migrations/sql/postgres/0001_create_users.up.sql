@@ -0,0 +1,32 @@
+CREATE TABLE users (
+    internal_id BIGSERIAL PRIMARY KEY,
+    public_id UUID NOT NULL,
+    name TEXT NOT NULL DEFAULT '',
+    email TEXT NOT NULL,
+    password TEXT NOT NULL,
+    role TEXT NOT NULL DEFAULT 'user',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    deleted_at TIMESTAMPTZ
+);
+
+CREATE UNIQUE INDEX idx_users_public_id ON users (public_id);
+CREATE UNIQUE INDEX idx_users_email ON users (email);
+CREATE INDEX idx_users_deleted_at ON users (deleted_at);
+
+CREATE TABLE refresh_tokens (
+    internal_id BIGSERIAL PRIMARY KEY,
+    public_id UUID NOT NULL,
+    user_internal_id BIGINT NOT NULL REFERENCES users (internal_id) ON DELETE CASCADE,
+    family_id UUID NOT NULL,
+    token_hash TEXT NOT NULL,
+    device_label TEXT NOT NULL DEFAULT '',
+    expires_at TIMESTAMPTZ NOT NULL,
+    revoked_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX idx_refresh_tokens_public_id ON refresh_tokens (public_id);
+CREATE UNIQUE INDEX idx_refresh_tokens_token_hash ON refresh_tokens (token_hash);
+CREATE INDEX idx_refresh_tokens_user_internal_id ON refresh_tokens (user_internal_id);
+CREATE INDEX idx_refresh_tokens_family_id ON refresh_tokens (family_id);
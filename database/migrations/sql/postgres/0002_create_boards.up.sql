@@ -0,0 +1,49 @@
+CREATE TABLE boards (
+    internal_id BIGSERIAL PRIMARY KEY,
+    public_id UUID NOT NULL,
+    title TEXT NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+    owner_internal_id BIGINT NOT NULL REFERENCES users (internal_id),
+    owner_public_id UUID NOT NULL,
+    duedate TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX idx_boards_public_id ON boards (public_id);
+CREATE INDEX idx_boards_owner_internal_id ON boards (owner_internal_id);
+
+CREATE TABLE board_members (
+    board_internal_id BIGINT NOT NULL REFERENCES boards (internal_id) ON DELETE CASCADE,
+    user_internal_id BIGINT NOT NULL REFERENCES users (internal_id) ON DELETE CASCADE,
+    role TEXT NOT NULL DEFAULT 'member',
+    joined_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (board_internal_id, user_internal_id)
+);
+
+CREATE INDEX idx_board_members_user_internal_id ON board_members (user_internal_id);
+
+CREATE TABLE lists (
+    internal_id BIGSERIAL PRIMARY KEY,
+    public_id UUID NOT NULL,
+    board_internal_id BIGINT NOT NULL REFERENCES boards (internal_id) ON DELETE CASCADE,
+    board_public_id UUID NOT NULL,
+    tittle TEXT NOT NULL,
+    rank TEXT COLLATE "C" NOT NULL DEFAULT '',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX idx_lists_public_id ON lists (public_id);
+CREATE INDEX idx_lists_board_internal_id ON lists (board_internal_id);
+CREATE INDEX idx_lists_rank ON lists (board_internal_id, rank);
+
+-- satu baris per board, list_order dipakai saat ORDERING_MODE=array
+CREATE TABLE list_positions (
+    internal_id BIGSERIAL PRIMARY KEY,
+    public_id UUID NOT NULL,
+    board_internal_id BIGINT NOT NULL REFERENCES boards (internal_id) ON DELETE CASCADE,
+    list_order UUID[] NOT NULL DEFAULT '{}',
+    version BIGINT NOT NULL DEFAULT 0
+);
+
+CREATE UNIQUE INDEX idx_list_positions_public_id ON list_positions (public_id);
+CREATE UNIQUE INDEX idx_list_positions_board_internal_id ON list_positions (board_internal_id);
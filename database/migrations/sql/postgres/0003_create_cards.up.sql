@@ -0,0 +1,56 @@
+CREATE TABLE cards (
+    internal_id BIGSERIAL PRIMARY KEY,
+    public_id UUID NOT NULL,
+    list_internal_id BIGINT NOT NULL REFERENCES lists (internal_id) ON DELETE CASCADE,
+    title TEXT NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+    duedate TIMESTAMPTZ,
+    position INTEGER NOT NULL DEFAULT 0,
+    rank TEXT COLLATE "C" NOT NULL DEFAULT '',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX idx_cards_public_id ON cards (public_id);
+CREATE INDEX idx_cards_list_internal_id ON cards (list_internal_id);
+CREATE INDEX idx_cards_rank ON cards (list_internal_id, rank);
+
+-- satu baris per list, card_order dipakai saat ORDERING_MODE=array
+CREATE TABLE card_positions (
+    internal_id BIGSERIAL PRIMARY KEY,
+    public_id UUID NOT NULL,
+    list_internal_id BIGINT NOT NULL REFERENCES lists (internal_id) ON DELETE CASCADE,
+    card_order UUID[] NOT NULL DEFAULT '{}',
+    version BIGINT NOT NULL DEFAULT 0
+);
+
+CREATE UNIQUE INDEX idx_card_positions_public_id ON card_positions (public_id);
+CREATE UNIQUE INDEX idx_card_positions_list_internal_id ON card_positions (list_internal_id);
+
+CREATE TABLE card_assignees (
+    card_internal_id BIGINT NOT NULL REFERENCES cards (internal_id) ON DELETE CASCADE,
+    user_internal_id BIGINT NOT NULL REFERENCES users (internal_id) ON DELETE CASCADE,
+    assigned_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (card_internal_id, user_internal_id)
+);
+
+CREATE INDEX idx_card_assignees_user_internal_id ON card_assignees (user_internal_id);
+
+CREATE TABLE labels (
+    internal_id BIGSERIAL PRIMARY KEY,
+    public_id UUID NOT NULL,
+    board_internal_id BIGINT NOT NULL REFERENCES boards (internal_id) ON DELETE CASCADE,
+    name TEXT NOT NULL DEFAULT '',
+    color TEXT NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX idx_labels_public_id ON labels (public_id);
+CREATE INDEX idx_labels_board_internal_id ON labels (board_internal_id);
+
+CREATE TABLE cardlabels (
+    card_internal_id BIGINT NOT NULL REFERENCES cards (internal_id) ON DELETE CASCADE,
+    label_internal_id BIGINT NOT NULL REFERENCES labels (internal_id) ON DELETE CASCADE,
+    PRIMARY KEY (card_internal_id, label_internal_id)
+);
+
+CREATE INDEX idx_cardlabels_label_internal_id ON cardlabels (label_internal_id);
@@ -0,0 +1,25 @@
+CREATE TABLE comments (
+    internal_id BIGSERIAL PRIMARY KEY,
+    public_id UUID NOT NULL,
+    card_internal_id BIGINT NOT NULL REFERENCES cards (internal_id) ON DELETE CASCADE,
+    card_id UUID NOT NULL,
+    user_internal_id BIGINT NOT NULL REFERENCES users (internal_id),
+    user_id UUID NOT NULL,
+    message TEXT NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    edited_at TIMESTAMPTZ,
+    deleted_at TIMESTAMPTZ
+);
+
+CREATE UNIQUE INDEX idx_comments_public_id ON comments (public_id);
+CREATE INDEX idx_comments_card_internal_id ON comments (card_internal_id, created_at);
+
+CREATE TABLE comment_edits (
+    internal_id BIGSERIAL PRIMARY KEY,
+    comment_internal_id BIGINT NOT NULL REFERENCES comments (internal_id) ON DELETE CASCADE,
+    message TEXT NOT NULL,
+    edited_by BIGINT NOT NULL REFERENCES users (internal_id),
+    edited_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_comment_edits_comment_internal_id ON comment_edits (comment_internal_id);
@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS list_positions;
+DROP TABLE IF EXISTS lists;
+DROP TABLE IF EXISTS board_members;
+DROP TABLE IF EXISTS boards;
@@ -0,0 +1,49 @@
+CREATE TABLE boards (
+    internal_id INTEGER PRIMARY KEY AUTOINCREMENT,
+    public_id TEXT NOT NULL,
+    title TEXT NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+    owner_internal_id INTEGER NOT NULL REFERENCES users (internal_id),
+    owner_public_id TEXT NOT NULL,
+    duedate DATETIME,
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE UNIQUE INDEX idx_boards_public_id ON boards (public_id);
+CREATE INDEX idx_boards_owner_internal_id ON boards (owner_internal_id);
+
+CREATE TABLE board_members (
+    board_internal_id INTEGER NOT NULL REFERENCES boards (internal_id) ON DELETE CASCADE,
+    user_internal_id INTEGER NOT NULL REFERENCES users (internal_id) ON DELETE CASCADE,
+    role TEXT NOT NULL DEFAULT 'member',
+    joined_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (board_internal_id, user_internal_id)
+);
+
+CREATE INDEX idx_board_members_user_internal_id ON board_members (user_internal_id);
+
+CREATE TABLE lists (
+    internal_id INTEGER PRIMARY KEY AUTOINCREMENT,
+    public_id TEXT NOT NULL,
+    board_internal_id INTEGER NOT NULL REFERENCES boards (internal_id) ON DELETE CASCADE,
+    board_public_id TEXT NOT NULL,
+    tittle TEXT NOT NULL,
+    rank TEXT NOT NULL DEFAULT '',
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE UNIQUE INDEX idx_lists_public_id ON lists (public_id);
+CREATE INDEX idx_lists_board_internal_id ON lists (board_internal_id);
+CREATE INDEX idx_lists_rank ON lists (board_internal_id, rank);
+
+-- satu baris per board, list_order disimpan sebagai JSON array karena sqlite tidak punya uuid[]
+CREATE TABLE list_positions (
+    internal_id INTEGER PRIMARY KEY AUTOINCREMENT,
+    public_id TEXT NOT NULL,
+    board_internal_id INTEGER NOT NULL REFERENCES boards (internal_id) ON DELETE CASCADE,
+    list_order TEXT NOT NULL DEFAULT '[]',
+    version INTEGER NOT NULL DEFAULT 0
+);
+
+CREATE UNIQUE INDEX idx_list_positions_public_id ON list_positions (public_id);
+CREATE UNIQUE INDEX idx_list_positions_board_internal_id ON list_positions (board_internal_id);
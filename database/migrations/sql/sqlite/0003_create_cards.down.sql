@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS cardlabels;
+DROP TABLE IF EXISTS labels;
+DROP TABLE IF EXISTS card_assignees;
+DROP TABLE IF EXISTS card_positions;
+DROP TABLE IF EXISTS cards;
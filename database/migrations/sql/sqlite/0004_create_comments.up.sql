@@ -0,0 +1,25 @@
+CREATE TABLE comments (
+    internal_id INTEGER PRIMARY KEY AUTOINCREMENT,
+    public_id TEXT NOT NULL,
+    card_internal_id INTEGER NOT NULL REFERENCES cards (internal_id) ON DELETE CASCADE,
+    card_id TEXT NOT NULL,
+    user_internal_id INTEGER NOT NULL REFERENCES users (internal_id),
+    user_id TEXT NOT NULL,
+    message TEXT NOT NULL,
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    edited_at DATETIME,
+    deleted_at DATETIME
+);
+
+CREATE UNIQUE INDEX idx_comments_public_id ON comments (public_id);
+CREATE INDEX idx_comments_card_internal_id ON comments (card_internal_id, created_at);
+
+CREATE TABLE comment_edits (
+    internal_id INTEGER PRIMARY KEY AUTOINCREMENT,
+    comment_internal_id INTEGER NOT NULL REFERENCES comments (internal_id) ON DELETE CASCADE,
+    message TEXT NOT NULL,
+    edited_by INTEGER NOT NULL REFERENCES users (internal_id),
+    edited_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_comment_edits_comment_internal_id ON comment_edits (comment_internal_id);
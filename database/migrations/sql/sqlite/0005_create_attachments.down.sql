@@ -0,0 +1,3 @@
+ALTER TABLE cards DROP COLUMN cover_attachment_internal_id;
+DROP TABLE IF EXISTS attachment_thumbnails;
+DROP TABLE IF EXISTS card_attachments;
@@ -0,0 +1,33 @@
+CREATE TABLE card_attachments (
+    internal_id INTEGER PRIMARY KEY AUTOINCREMENT,
+    public_id TEXT NOT NULL,
+    card_internal_id INTEGER NOT NULL REFERENCES cards (internal_id) ON DELETE CASCADE,
+    user_internal_id INTEGER NOT NULL REFERENCES users (internal_id),
+    file TEXT NOT NULL,
+    file_name TEXT NOT NULL,
+    mime_type TEXT NOT NULL,
+    size INTEGER NOT NULL DEFAULT 0,
+    checksum TEXT NOT NULL DEFAULT '',
+    thumbnail_status TEXT NOT NULL DEFAULT '',
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE UNIQUE INDEX idx_card_attachments_public_id ON card_attachments (public_id);
+CREATE INDEX idx_card_attachments_card_internal_id ON card_attachments (card_internal_id);
+CREATE INDEX idx_card_attachments_user_internal_id ON card_attachments (user_internal_id);
+CREATE INDEX idx_card_attachments_thumbnail_status ON card_attachments (thumbnail_status);
+
+CREATE TABLE attachment_thumbnails (
+    internal_id INTEGER PRIMARY KEY AUTOINCREMENT,
+    attachment_internal_id INTEGER NOT NULL REFERENCES card_attachments (internal_id) ON DELETE CASCADE,
+    size TEXT NOT NULL,
+    file TEXT NOT NULL,
+    width INTEGER NOT NULL,
+    height INTEGER NOT NULL,
+    byte_size INTEGER NOT NULL DEFAULT 0
+);
+
+CREATE UNIQUE INDEX idx_attachment_thumbnails_attachment_size ON attachment_thumbnails (attachment_internal_id, size);
+
+-- cover card menunjuk ke attachment, dikosongkan kalau attachment nya dihapus
+ALTER TABLE cards ADD COLUMN cover_attachment_internal_id INTEGER REFERENCES card_attachments (internal_id) ON DELETE SET NULL;
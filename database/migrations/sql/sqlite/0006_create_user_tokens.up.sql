@@ -0,0 +1,18 @@
+ALTER TABLE users ADD COLUMN email_verified_at DATETIME;
+
+-- akun yang sudah ada sebelum verifikasi email dianggap sudah terverifikasi, supaya tidak terkunci dari route yang butuh email terverifikasi
+UPDATE users SET email_verified_at = CURRENT_TIMESTAMP;
+
+-- token sekali pakai yang dikirim lewat email (verifikasi email, dsb), yang disimpan hanya hash nya
+CREATE TABLE user_tokens (
+    internal_id INTEGER PRIMARY KEY AUTOINCREMENT,
+    user_internal_id INTEGER NOT NULL REFERENCES users (internal_id) ON DELETE CASCADE,
+    purpose TEXT NOT NULL,
+    token_hash TEXT NOT NULL,
+    expires_at DATETIME NOT NULL,
+    used_at DATETIME,
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE UNIQUE INDEX idx_user_tokens_token_hash ON user_tokens (token_hash);
+CREATE INDEX idx_user_tokens_user_internal_id ON user_tokens (user_internal_id);
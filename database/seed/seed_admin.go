@@ -1,29 +1,37 @@
 package seed
 
 import (
+	"context"
 	"log"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
 
 	"github.com/odink789/project-management/config"
 	"github.com/odink789/project-management/models"
 	"github.com/odink789/project-management/utils"
 )
 
+func SeedAdmin(ctx context.Context, db *gorm.DB) error {
+	password, err := utils.HashPassword(config.AppConfig.AdminPassword)
+	if err != nil {
+		return err
+	}
+	verifiedAt := time.Now() // email admin diisi lewat config, tidak perlu verifikasi
 
-
-
-func SeedAdmin() {
-	password,_ := utils.password("admin123")
-
+	//admin yang sudah ada tidak diubah, ADMIN_PASSWORD hanya dipakai saat pertama kali dibuat
 	admin := models.User{
-		Name: "Super admin",
-		Email: "admin@example",
-		Password: password,
-		Role: "admin",
+		PublicID:        uuid.New(),
+		Name:            "Super admin",
+		Email:           config.AppConfig.AdminEmail,
+		Password:        password,
+		Role:            config.AppConfig.AdminRole,
+		EmailVerifiedAt: &verifiedAt,
 	}
-	if err := config.DB.FirstOrCreate(&admin, models.User(Email: admin.Email)){
-		log.Fatal("Failed to seed admin user:", err)
-	} else {
-		log.Println("Admin user seeded successfully")
+	if err := db.WithContext(ctx).FirstOrCreate(&admin, models.User{Email: admin.Email}).Error; err != nil {
+		return err
 	}
-
-}
\ No newline at end of file
+	log.Println("Admin user seeded successfully")
+	return nil
+}
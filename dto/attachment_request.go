@@ -0,0 +1,5 @@
+package dto
+
+type SetCoverRequest struct {
+	AttachmentID string `json:"attachment_id" validate:"required,uuid"`
+}
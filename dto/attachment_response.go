@@ -0,0 +1,39 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/odink789/project-management/models"
+)
+
+// key file di storage tidak ikut, file hanya bisa diambil lewat link download yang di sign
+type AttachmentResponse struct {
+	ID              uuid.UUID `json:"public_id"`
+	FileName        string    `json:"file_name"`
+	MimeType        string    `json:"mime_type"`
+	Size            int64     `json:"size"`
+	Checksum        string    `json:"checksum"`
+	ThumbnailStatus string    `json:"thumbnail_status,omitempty"`
+	CreatedAt       time.Time `json:"created_at"`
+}
+
+func NewAttachmentResponse(attachment *models.CardAttachment) AttachmentResponse {
+	return AttachmentResponse{
+		ID:              attachment.PublicID,
+		FileName:        attachment.FileName,
+		MimeType:        attachment.MimeType,
+		Size:            attachment.Size,
+		Checksum:        attachment.Checksum,
+		ThumbnailStatus: attachment.Thumbnail,
+		CreatedAt:       attachment.CreatedAt,
+	}
+}
+
+func NewAttachmentResponses(attachments []models.CardAttachment) []AttachmentResponse {
+	responses := make([]AttachmentResponse, 0, len(attachments))
+	for i := range attachments {
+		responses = append(responses, NewAttachmentResponse(&attachments[i]))
+	}
+	return responses
+}
@@ -0,0 +1,32 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/odink789/project-management/models"
+)
+
+// dipakai untuk create dan update board
+type BoardRequest struct {
+	Title       string     `json:"title" validate:"required,max=100"`
+	Description string     `json:"description" validate:"max=2000"`
+	DueDate     *time.Time `json:"due_date"`
+}
+
+func (r *BoardRequest) ToModel() *models.Board {
+	return &models.Board{
+		Title:       r.Title,
+		Description: r.Description,
+		Duedate:     r.DueDate,
+	}
+}
+
+// role kosong berarti member, owner tidak bisa diberikan lewat endpoint ini
+type AddMemberRequest struct {
+	Email string `json:"email" validate:"required,email"`
+	Role  string `json:"role" validate:"omitempty,oneof=admin member observer"`
+}
+
+type UpdateMemberRoleRequest struct {
+	Role string `json:"role" validate:"required,oneof=admin member observer"`
+}
@@ -0,0 +1,63 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/odink789/project-management/models"
+)
+
+type BoardResponse struct {
+	ID          uuid.UUID  `json:"public_id"`
+	Title       string     `json:"title"`
+	Description string     `json:"description"`
+	OwnerID     uuid.UUID  `json:"owner_public_id"`
+	DueDate     *time.Time `json:"due_date,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+}
+
+func NewBoardResponse(board *models.Board) BoardResponse {
+	return BoardResponse{
+		ID:          board.PublicID,
+		Title:       board.Title,
+		Description: board.Description,
+		OwnerID:     board.OwnerPublicID,
+		DueDate:     board.Duedate,
+		CreatedAt:   board.CreatedAt,
+	}
+}
+
+func NewBoardResponses(boards []models.Board) []BoardResponse {
+	responses := make([]BoardResponse, 0, len(boards))
+	for i := range boards {
+		responses = append(responses, NewBoardResponse(&boards[i]))
+	}
+	return responses
+}
+
+// member diidentifikasi dengan public_id user nya, User diisi oleh repository / service
+type BoardMemberResponse struct {
+	UserID   uuid.UUID        `json:"user_id"`
+	Name     string           `json:"name"`
+	Email    string           `json:"email"`
+	Role     models.BoardRole `json:"role"`
+	JoinedAt time.Time        `json:"joined_at"`
+}
+
+func NewBoardMemberResponse(member *models.BoardMember) BoardMemberResponse {
+	response := BoardMemberResponse{Role: member.Role, JoinedAt: member.JoinedAt}
+	if member.User != nil {
+		response.UserID = member.User.PublicID
+		response.Name = member.User.Name
+		response.Email = member.User.Email
+	}
+	return response
+}
+
+func NewBoardMemberResponses(members []models.BoardMember) []BoardMemberResponse {
+	responses := make([]BoardMemberResponse, 0, len(members))
+	for i := range members {
+		responses = append(responses, NewBoardMemberResponse(&members[i]))
+	}
+	return responses
+}
@@ -0,0 +1,5 @@
+package dto
+
+type AssignRequest struct {
+	UserID string `json:"user_id" validate:"required,uuid"`
+}
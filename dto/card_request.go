@@ -0,0 +1,59 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/odink789/project-management/models"
+	"github.com/odink789/project-management/services"
+)
+
+type CreateCardRequest struct {
+	Title       string     `json:"title" validate:"required,max=200"`
+	Description string     `json:"description" validate:"max=5000"`
+	DueDate     *time.Time `json:"due_date"`
+	//posisi card yang baru di dalam list, kosong berarti ditaruh paling akhir
+	Position *int `json:"position" validate:"omitempty,min=0"`
+}
+
+func (r *CreateCardRequest) ToModel() *models.Card {
+	return &models.Card{
+		Title:       r.Title,
+		Description: r.Description,
+		Duedate:     r.DueDate,
+	}
+}
+
+// Index mengubah position yang kosong menjadi -1 (paling akhir)
+func (r *CreateCardRequest) Index() int {
+	return index(r.Position)
+}
+
+type UpdateCardRequest struct {
+	Title       string     `json:"title" validate:"required,max=200"`
+	Description string     `json:"description" validate:"max=5000"`
+	DueDate     *time.Time `json:"due_date"`
+}
+
+func (r *UpdateCardRequest) ToModel() *models.Card {
+	return &models.Card{
+		Title:       r.Title,
+		Description: r.Description,
+		Duedate:     r.DueDate,
+	}
+}
+
+type MoveCardRequest struct {
+	ListID        string `json:"list_id" validate:"required,uuid"`
+	Position      *int   `json:"position" validate:"omitempty,min=0"`
+	SourceVersion *int64 `json:"source_version" validate:"omitempty,min=0"`
+	TargetVersion *int64 `json:"target_version" validate:"omitempty,min=0"`
+}
+
+func (r *MoveCardRequest) ToInput() services.CardMoveInput {
+	return services.CardMoveInput{
+		ListID:        r.ListID,
+		Position:      r.Position,
+		SourceVersion: r.SourceVersion,
+		TargetVersion: r.TargetVersion,
+	}
+}
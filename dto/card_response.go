@@ -0,0 +1,75 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/odink789/project-management/models"
+	"github.com/odink789/project-management/repositories"
+	"github.com/odink789/project-management/services"
+)
+
+type CardResponse struct {
+	ID          uuid.UUID         `json:"public_id"`
+	Title       string            `json:"title"`
+	Description string            `json:"description"`
+	DueDate     *time.Time        `json:"due_date,omitempty"`
+	Rank        string            `json:"rank,omitempty"`
+	Cover       *models.CardCover `json:"cover,omitempty"`
+	CreatedAt   time.Time         `json:"created_at"`
+}
+
+func NewCardResponse(card *models.Card) CardResponse {
+	return CardResponse{
+		ID:          card.PublicID,
+		Title:       card.Title,
+		Description: card.Description,
+		DueDate:     card.Duedate,
+		Rank:        card.Rank,
+		Cover:       card.Cover,
+		CreatedAt:   card.CreatedAt,
+	}
+}
+
+type OrderedCardsResponse struct {
+	Version int64          `json:"version"`
+	Cards   []CardResponse `json:"cards"`
+}
+
+func NewOrderedCardsResponse(ordered *services.OrderedCards) OrderedCardsResponse {
+	cards := make([]CardResponse, 0, len(ordered.Cards))
+	for i := range ordered.Cards {
+		cards = append(cards, NewCardResponse(&ordered.Cards[i]))
+	}
+	return OrderedCardsResponse{Version: ordered.Version, Cards: cards}
+}
+
+type CardMoveResponse struct {
+	Card   CardResponse          `json:"card"`
+	Orders []services.OrderState `json:"orders"`
+}
+
+func NewCardMoveResponse(result *services.CardMoveResult) CardMoveResponse {
+	return CardMoveResponse{Card: NewCardResponse(result.Card), Orders: result.Orders}
+}
+
+// card yang di assign ke user beserta list dan board nya
+type AssignedCardResponse struct {
+	CardResponse
+	ListID     uuid.UUID `json:"list_public_id"`
+	BoardID    uuid.UUID `json:"board_public_id"`
+	BoardTitle string    `json:"board_title"`
+}
+
+func NewAssignedCardResponses(cards []repositories.AssignedCard) []AssignedCardResponse {
+	responses := make([]AssignedCardResponse, 0, len(cards))
+	for i := range cards {
+		responses = append(responses, AssignedCardResponse{
+			CardResponse: NewCardResponse(&cards[i].Card),
+			ListID:       cards[i].ListPublicID,
+			BoardID:      cards[i].BoardPublicID,
+			BoardTitle:   cards[i].BoardTitle,
+		})
+	}
+	return responses
+}
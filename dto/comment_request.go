@@ -0,0 +1,5 @@
+package dto
+
+type CommentRequest struct {
+	Message string `json:"message" validate:"required,max=5000"`
+}
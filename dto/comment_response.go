@@ -0,0 +1,65 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/odink789/project-management/models"
+	"github.com/odink789/project-management/services"
+)
+
+type CommentResponse struct {
+	ID        uuid.UUID  `json:"public_id"`
+	CardID    uuid.UUID  `json:"card_id"`
+	UserID    uuid.UUID  `json:"user_id"`
+	Message   string     `json:"message"`
+	CreatedAt time.Time  `json:"created_at"`
+	EditedAt  *time.Time `json:"edited_at,omitempty"`
+	DeletedAt *time.Time `json:"deleted_at,omitempty"`
+}
+
+func NewCommentResponse(comment *models.Comment) CommentResponse {
+	return CommentResponse{
+		ID:        comment.PublicID,
+		CardID:    comment.CardPubID,
+		UserID:    comment.UserPubID,
+		Message:   comment.Message,
+		CreatedAt: comment.CreatedAt,
+		EditedAt:  comment.EditedAt,
+		DeletedAt: comment.DeletedAt,
+	}
+}
+
+type CommentPageResponse struct {
+	Comments []CommentResponse `json:"comments"`
+	Page     int               `json:"page"`
+	Limit    int               `json:"limit"`
+	Total    int64             `json:"total"`
+}
+
+func NewCommentPageResponse(page *services.CommentPage) CommentPageResponse {
+	comments := make([]CommentResponse, 0, len(page.Comments))
+	for i := range page.Comments {
+		comments = append(comments, NewCommentResponse(&page.Comments[i]))
+	}
+	return CommentPageResponse{Comments: comments, Page: page.Page, Limit: page.Limit, Total: page.Total}
+}
+
+// EditedBy adalah public_id user yang mengubah, Editor diisi oleh repository
+type CommentEditResponse struct {
+	Message  string     `json:"message"`
+	EditedBy *uuid.UUID `json:"edited_by,omitempty"`
+	EditedAt time.Time  `json:"edited_at"`
+}
+
+func NewCommentEditResponses(edits []models.CommentEdit) []CommentEditResponse {
+	responses := make([]CommentEditResponse, 0, len(edits))
+	for _, edit := range edits {
+		response := CommentEditResponse{Message: edit.Message, EditedAt: edit.EditedAt}
+		if edit.Editor != nil {
+			response.EditedBy = &edit.Editor.PublicID
+		}
+		responses = append(responses, response)
+	}
+	return responses
+}
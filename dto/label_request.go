@@ -0,0 +1,17 @@
+package dto
+
+import "github.com/odink789/project-management/models"
+
+// name boleh kosong, format color dicek oleh service karena bisa nama palette atau hex
+type LabelRequest struct {
+	Name  string `json:"name" validate:"max=50"`
+	Color string `json:"color" validate:"required"`
+}
+
+func (r *LabelRequest) ToModel() *models.Label {
+	return &models.Label{Name: r.Name, Color: r.Color}
+}
+
+type AttachLabelRequest struct {
+	LabelID string `json:"label_id" validate:"required,uuid"`
+}
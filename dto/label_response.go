@@ -0,0 +1,32 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/odink789/project-management/models"
+)
+
+type LabelResponse struct {
+	ID        uuid.UUID `json:"public_id"`
+	Name      string    `json:"name"`
+	Color     string    `json:"color"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+func NewLabelResponse(label *models.Label) LabelResponse {
+	return LabelResponse{
+		ID:        label.PublicID,
+		Name:      label.Name,
+		Color:     label.Color,
+		CreatedAt: label.CreatedAt,
+	}
+}
+
+func NewLabelResponses(labels []models.Label) []LabelResponse {
+	responses := make([]LabelResponse, 0, len(labels))
+	for i := range labels {
+		responses = append(responses, NewLabelResponse(&labels[i]))
+	}
+	return responses
+}
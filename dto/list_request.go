@@ -0,0 +1,40 @@
+package dto
+
+import (
+	"github.com/google/uuid"
+	"github.com/odink789/project-management/models"
+)
+
+//nama field json list memang "tittle", mengikuti kolom di database
+
+type CreateListRequest struct {
+	Title string `json:"tittle" validate:"required,max=100"`
+	//posisi list yang baru, kosong berarti ditaruh paling akhir
+	Position *int `json:"position" validate:"omitempty,min=0"`
+}
+
+func (r *CreateListRequest) ToModel() *models.List {
+	return &models.List{Tittle: r.Title}
+}
+
+// Index mengubah position yang kosong menjadi -1 (paling akhir)
+func (r *CreateListRequest) Index() int {
+	return index(r.Position)
+}
+
+type RenameListRequest struct {
+	Title string `json:"tittle" validate:"required,max=100"`
+}
+
+type ReorderListsRequest struct {
+	ListOrder []uuid.UUID `json:"list_order" validate:"required"`
+	//version urutan list yang terakhir dilihat client
+	Version *int64 `json:"version" validate:"omitempty,min=0"`
+}
+
+func index(position *int) int {
+	if position == nil {
+		return -1
+	}
+	return *position
+}
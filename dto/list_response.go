@@ -0,0 +1,40 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/odink789/project-management/models"
+	"github.com/odink789/project-management/services"
+)
+
+type ListResponse struct {
+	ID        uuid.UUID `json:"public_id"`
+	BoardID   uuid.UUID `json:"board_public_id"`
+	Title     string    `json:"tittle"`
+	Rank      string    `json:"rank,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+func NewListResponse(list *models.List) ListResponse {
+	return ListResponse{
+		ID:        list.PublicID,
+		BoardID:   list.BoardPublicID,
+		Title:     list.Tittle,
+		Rank:      list.Rank,
+		CreatedAt: list.CreatedAt,
+	}
+}
+
+type OrderedListsResponse struct {
+	Version int64          `json:"version"`
+	Lists   []ListResponse `json:"lists"`
+}
+
+func NewOrderedListsResponse(ordered *services.OrderedLists) OrderedListsResponse {
+	lists := make([]ListResponse, 0, len(ordered.Lists))
+	for i := range ordered.Lists {
+		lists = append(lists, NewListResponse(&ordered.Lists[i]))
+	}
+	return OrderedListsResponse{Version: ordered.Version, Lists: lists}
+}
@@ -0,0 +1,76 @@
+package dto
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/odink789/project-management/models"
+	"github.com/odink789/project-management/services"
+	"golang.org/x/crypto/bcrypt"
+)
+
+func assertNoLeak(t *testing.T, name string, v interface{}, hash string) {
+	t.Helper()
+	encoded, err := json.Marshal(v)
+	if err != nil {
+		t.Fatalf("%s: json.Marshal() error = %v", name, err)
+	}
+	body := string(encoded)
+	for _, forbidden := range []string{"password", "internal_id", `"edited_by":7`, hash} {
+		if strings.Contains(body, forbidden) {
+			t.Errorf("%s leaks %q: %s", name, forbidden, body)
+		}
+	}
+}
+
+func TestResponses_HidePasswordAndInternalIDs(t *testing.T) {
+	hashed, err := bcrypt.GenerateFromPassword([]byte("Rahasia123"), bcrypt.MinCost)
+	if err != nil {
+		t.Fatal(err)
+	}
+	hash := string(hashed)
+
+	user := &models.User{InternalID: 7, PublicID: uuid.New(), Name: "Budi", Email: "budi@example.com", Password: hash, Role: "admin"}
+	board := &models.Board{InternalID: 3, PublicID: uuid.New(), Title: "Board", OwnerID: 7, OwnerPublicID: user.PublicID}
+	member := &models.BoardMember{BoardID: 3, UserID: 7, Role: "owner", User: user}
+	list := &models.List{InternalID: 5, PublicID: uuid.New(), BoardInternalID: 3, BoardPublicID: board.PublicID, Tittle: "Todo"}
+	card := &models.Card{InternalID: 9, PublicID: uuid.New(), ListID: 5, Title: "Card"}
+	comment := &models.Comment{InternalID: 11, PublicID: uuid.New(), CardID: 9, CardPubID: card.PublicID, UserID: 7, UserPubID: user.PublicID, Message: "halo"}
+	edit := models.CommentEdit{InternalID: 12, CommentID: 11, Message: "hai", EditedBy: 7, Editor: user}
+	attachment := &models.CardAttachment{InternalID: 13, PublicID: uuid.New(), CardID: 9, UserID: 7, FileName: "a.png"}
+	session := models.RefreshToken{InternalID: 14, PublicID: uuid.New(), UserID: 7, TokenHash: "secret-hash"}
+
+	views := map[string]interface{}{
+		"user":       NewUserResponse(user),
+		"login":      NewLoginResponse(user, &services.AuthToken{AccessToken: "a", RefreshToken: "r"}),
+		"board":      NewBoardResponse(board),
+		"member":     NewBoardMemberResponse(member),
+		"list":       NewListResponse(list),
+		"card":       NewCardResponse(card),
+		"comment":    NewCommentResponse(comment),
+		"edits":      NewCommentEditResponses([]models.CommentEdit{edit}),
+		"attachment": NewAttachmentResponse(attachment),
+		"sessions":   NewSessionResponses([]models.RefreshToken{session}),
+	}
+	for name, view := range views {
+		assertNoLeak(t, name, view, hash)
+	}
+
+	//model mentah juga tidak boleh membocorkan apa pun kalau tidak sengaja dikirim langsung
+	assertNoLeak(t, "raw user", user, hash)
+	assertNoLeak(t, "raw member", member, hash)
+	assertNoLeak(t, "raw session", session, "secret-hash")
+}
+
+func TestUserResponse_UsesPublicID(t *testing.T) {
+	user := &models.User{InternalID: 7, PublicID: uuid.New(), Name: "Budi"}
+	encoded, _ := json.Marshal(NewUserResponse(user))
+
+	body := map[string]interface{}{}
+	json.Unmarshal(encoded, &body)
+	if body["public_id"] != user.PublicID.String() {
+		t.Errorf("public_id = %v, want %s", body["public_id"], user.PublicID)
+	}
+}
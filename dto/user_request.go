@@ -0,0 +1,50 @@
+package dto
+
+import "github.com/odink789/project-management/models"
+
+// role, id dan timestamp sengaja tidak ada di sini, semua nya diisi oleh service
+type RegisterRequest struct {
+	Name     string `json:"name" validate:"required,max=100"`
+	Email    string `json:"email" validate:"required,email,max=255"`
+	Password string `json:"password" validate:"required,min=8,max=72,password"` // bcrypt hanya membaca 72 byte pertama
+}
+
+func (r *RegisterRequest) ToModel() *models.User {
+	return &models.User{
+		Name:     r.Name,
+		Email:    r.Email,
+		Password: r.Password,
+	}
+}
+
+// password login tidak dicek kekuatan nya supaya pesan error tidak membocorkan aturan akun lama
+type LoginRequest struct {
+	Email       string `json:"email" validate:"required,email"`
+	Password    string `json:"password" validate:"required"`
+	DeviceLabel string `json:"device_label" validate:"max=100"`
+}
+
+// dipakai oleh refresh dan logout
+type RefreshTokenRequest struct {
+	RefreshToken string `json:"refresh_token" validate:"required"`
+}
+
+// token dari link yang dikirim lewat email
+type VerifyEmailRequest struct {
+	Token string `json:"token" validate:"required,max=100"`
+}
+
+type ForgotPasswordRequest struct {
+	Email string `json:"email" validate:"required,email"`
+}
+
+// password baru memakai aturan yang sama dengan register
+type ResetPasswordRequest struct {
+	Token    string `json:"token" validate:"required,max=100"`
+	Password string `json:"password" validate:"required,min=8,max=72,password"`
+}
+
+type ChangePasswordRequest struct {
+	CurrentPassword string `json:"current_password" validate:"required"`
+	NewPassword     string `json:"new_password" validate:"required,min=8,max=72,password"`
+}
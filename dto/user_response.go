@@ -0,0 +1,79 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/odink789/project-management/models"
+	"github.com/odink789/project-management/services"
+)
+
+//response view hanya berisi public_id dan field yang aman dikirim ke client
+//password hash dan internal_id tidak pernah ikut walaupun model nya berubah
+
+type UserResponse struct {
+	ID              uuid.UUID  `json:"public_id"`
+	Name            string     `json:"name"`
+	Email           string     `json:"email"`
+	Role            string     `json:"role"`
+	EmailVerifiedAt *time.Time `json:"email_verified_at"`
+	CreatedAt       time.Time  `json:"created_at"`
+	UpdatedAt       time.Time  `json:"updated_at"`
+}
+
+func NewUserResponse(user *models.User) UserResponse {
+	return UserResponse{
+		ID:              user.PublicID,
+		Name:            user.Name,
+		Email:           user.Email,
+		Role:            user.Role,
+		EmailVerifiedAt: user.EmailVerifiedAt,
+		CreatedAt:       user.CreatedAt,
+		UpdatedAt:       user.UpdatedAt,
+	}
+}
+
+func NewUserResponses(users []models.User) []UserResponse {
+	responses := make([]UserResponse, 0, len(users))
+	for i := range users {
+		responses = append(responses, NewUserResponse(&users[i]))
+	}
+	return responses
+}
+
+type LoginResponse struct {
+	AccessToken  string       `json:"access_token"`
+	RefreshToken string       `json:"refresh_token"`
+	User         UserResponse `json:"user"`
+}
+
+func NewLoginResponse(user *models.User, token *services.AuthToken) LoginResponse {
+	return LoginResponse{
+		AccessToken:  token.AccessToken,
+		RefreshToken: token.RefreshToken,
+		User:         NewUserResponse(user),
+	}
+}
+
+// SessionResponse adalah refresh token yang masih aktif, hash token nya tidak ikut dikirim
+type SessionResponse struct {
+	ID          uuid.UUID  `json:"public_id"`
+	DeviceLabel string     `json:"device_label"`
+	ExpiresAt   time.Time  `json:"expires_at"`
+	RevokedAt   *time.Time `json:"revoked_at,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+}
+
+func NewSessionResponses(tokens []models.RefreshToken) []SessionResponse {
+	responses := make([]SessionResponse, 0, len(tokens))
+	for _, token := range tokens {
+		responses = append(responses, SessionResponse{
+			ID:          token.PublicID,
+			DeviceLabel: token.DeviceLabel,
+			ExpiresAt:   token.ExpiresAt,
+			RevokedAt:   token.RevokedAt,
+			CreatedAt:   token.CreatedAt,
+		})
+	}
+	return responses
+}
@@ -0,0 +1,99 @@
+package dto
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"unicode"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/odink789/project-management/services"
+)
+
+//dto adalah bentuk body request yang boleh dikirim client
+//field yang tidak ada di dto (internal_id, public_id, role, dll) tidak akan pernah sampai ke model
+//aturan validasi ditulis di tag validate, mapping ke model dilakukan manual di method ToModel
+
+var validate = newValidator()
+
+func newValidator() *validator.Validate {
+	v := validator.New(validator.WithRequiredStructEnabled())
+
+	//nama field di error memakai nama json supaya sama dengan yang dikirim client
+	v.RegisterTagNameFunc(func(field reflect.StructField) string {
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "-" {
+			return ""
+		}
+		return name
+	})
+	v.RegisterValidation("password", strongPassword)
+	return v
+}
+
+// password minimal punya huruf besar, huruf kecil dan angka, panjang nya dicek lewat tag min / max
+func strongPassword(fl validator.FieldLevel) bool {
+	var upper, lower, digit bool
+	for _, r := range fl.Field().String() {
+		switch {
+		case unicode.IsUpper(r):
+			upper = true
+		case unicode.IsLower(r):
+			lower = true
+		case unicode.IsDigit(r):
+			digit = true
+		}
+	}
+	return upper && lower && digit
+}
+
+// Validate menjalankan aturan di tag validate, semua field yang salah dikembalikan sekaligus
+func Validate(req interface{}) error {
+	err := validate.Struct(req)
+	if err == nil {
+		return nil
+	}
+
+	var invalid validator.ValidationErrors
+	if !errors.As(err, &invalid) {
+		return err
+	}
+
+	fields := make([]services.FieldError, 0, len(invalid))
+	for _, fe := range invalid {
+		fields = append(fields, services.FieldError{Field: fe.Field(), Message: fieldMessage(fe)})
+	}
+	return services.ValidationError(fields...)
+}
+
+func fieldMessage(fe validator.FieldError) string {
+	field := fe.Field()
+	switch fe.Tag() {
+	case "required":
+		return field + " is required"
+	case "email":
+		return field + " must be a valid email address"
+	case "uuid":
+		return field + " must be a valid UUID"
+	case "oneof":
+		return fmt.Sprintf("%s must be one of: %s", field, strings.ReplaceAll(fe.Param(), " ", ", "))
+	case "password":
+		return field + " must contain an upper case letter, a lower case letter and a number"
+	case "min", "max":
+		limit := "at least"
+		if fe.Tag() == "max" {
+			limit = "at most"
+		}
+		switch fe.Kind() {
+		case reflect.String:
+			return fmt.Sprintf("%s must be %s %s characters", field, limit, fe.Param())
+		case reflect.Slice:
+			return fmt.Sprintf("%s must contain %s %s items", field, limit, fe.Param())
+		default:
+			return fmt.Sprintf("%s must be %s %s", field, limit, fe.Param())
+		}
+	default:
+		return field + " is invalid"
+	}
+}
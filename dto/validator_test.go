@@ -0,0 +1,76 @@
+package dto
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/odink789/project-management/services"
+)
+
+func fieldErrors(t *testing.T, err error) map[string]string {
+	t.Helper()
+	var domainErr *services.Error
+	if !errors.As(err, &domainErr) || !errors.Is(err, services.ErrValidation) {
+		t.Fatalf("Validate() error = %v, want validation error", err)
+	}
+
+	fields := map[string]string{}
+	for _, field := range domainErr.Fields {
+		fields[field.Field] = field.Message
+	}
+	return fields
+}
+
+func TestValidate_RegisterRequest(t *testing.T) {
+	valid := RegisterRequest{Name: "Budi", Email: "budi@example.com", Password: "Rahasia123"}
+	if err := Validate(&valid); err != nil {
+		t.Fatalf("Validate() error = %v", err)
+	}
+
+	fields := fieldErrors(t, Validate(&RegisterRequest{Email: "not-an-email", Password: "lemah"}))
+	want := map[string]string{
+		"name":     "name is required",
+		"email":    "email must be a valid email address",
+		"password": "password must be at least 8 characters",
+	}
+	for field, message := range want {
+		if fields[field] != message {
+			t.Errorf("field %s = %q, want %q", field, fields[field], message)
+		}
+	}
+
+	fields = fieldErrors(t, Validate(&RegisterRequest{Name: "Budi", Email: "budi@example.com", Password: "semuakecil1"}))
+	if _, ok := fields["password"]; !ok || len(fields) != 1 {
+		t.Errorf("weak password fields = %v", fields)
+	}
+}
+
+func TestRegisterRequest_IgnoresPrivilegedFields(t *testing.T) {
+	var req RegisterRequest
+	body := `{"name":"Budi","email":"budi@example.com","password":"Rahasia123","role":"admin","internal_id":7,"public_id":"5b1f6c1e-7c4e-4c59-9d55-2b1f3c0a9d10"}`
+	if err := json.Unmarshal([]byte(body), &req); err != nil {
+		t.Fatal(err)
+	}
+
+	user := req.ToModel()
+	if user.Role != "" || user.InternalID != 0 || user.PublicID != uuid.Nil {
+		t.Errorf("ToModel() = %+v, privileged fields must stay empty", user)
+	}
+}
+
+func TestValidate_UUIDAndOneOf(t *testing.T) {
+	fields := fieldErrors(t, Validate(&MoveCardRequest{ListID: "123"}))
+	if fields["list_id"] != "list_id must be a valid UUID" {
+		t.Errorf("list_id = %q", fields["list_id"])
+	}
+
+	fields = fieldErrors(t, Validate(&AddMemberRequest{Email: "budi@example.com", Role: "owner"}))
+	if fields["role"] != "role must be one of: admin, member, observer" {
+		t.Errorf("role = %q", fields["role"])
+	}
+	if err := Validate(&AddMemberRequest{Email: "budi@example.com"}); err != nil {
+		t.Errorf("empty role should default to member, got %v", err)
+	}
+}
@@ -0,0 +1,57 @@
+package mailer
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/mail"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+//mailer untuk development, email tidak benar benar dikirim
+
+type fileMailer struct {
+	dir  string
+	from *mail.Address
+}
+
+// NewFileMailer menyimpan setiap email sebagai file .eml yang bisa dibuka dengan email client
+func NewFileMailer(dir, from string) (Mailer, error) {
+	sender, err := mail.ParseAddress(from)
+	if err != nil {
+		return nil, fmt.Errorf("invalid sender %q: %w", from, err)
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+	return &fileMailer{dir: dir, from: sender}, nil
+}
+
+func (m *fileMailer) Send(ctx context.Context, msg Message) error {
+	now := time.Now()
+	body, err := encode(m.from, msg, now)
+	if err != nil {
+		return err
+	}
+	name := fmt.Sprintf("%s-%s.eml", now.Format("20060102-150405.000000000"), messageID()[:8])
+	return os.WriteFile(filepath.Join(m.dir, name), body, 0o644)
+}
+
+type logMailer struct {
+	logger *log.Logger
+}
+
+// NewLogMailer hanya menulis tujuan, subject dan isi text email ke log
+func NewLogMailer() Mailer {
+	return &logMailer{logger: log.Default()}
+}
+
+func (m *logMailer) Send(ctx context.Context, msg Message) error {
+	if _, err := mail.ParseAddress(msg.To); err != nil {
+		return fmt.Errorf("invalid recipient %q: %w", msg.To, err)
+	}
+	m.logger.Printf("mail to %s, subject %q:\n%s", msg.To, msg.Subject, msg.Text)
+	return nil
+}
@@ -0,0 +1,231 @@
+package mailer
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net"
+	"net/mail"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/odink789/project-management/config"
+)
+
+// smtpSink adalah server smtp lokal minimal untuk test, email yang diterima dikirim ke channel
+type smtpSink struct {
+	listener net.Listener
+	received chan sinkMail
+}
+
+type sinkMail struct {
+	from, to string
+	data     string
+}
+
+func newSMTPSink(t *testing.T) *smtpSink {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	sink := &smtpSink{listener: listener, received: make(chan sinkMail, 1)}
+	t.Cleanup(func() { listener.Close() })
+	go sink.serve()
+	return sink
+}
+
+func (s *smtpSink) port() int {
+	return s.listener.Addr().(*net.TCPAddr).Port
+}
+
+func (s *smtpSink) serve() {
+	conn, err := s.listener.Accept()
+	if err != nil {
+		return
+	}
+	defer conn.Close()
+
+	r := bufio.NewReader(conn)
+	reply := func(line string) { io.WriteString(conn, line+"\r\n") }
+	reply("220 sink ready")
+
+	var current sinkMail
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return
+		}
+		command := strings.ToUpper(strings.TrimSpace(line))
+		switch {
+		case strings.HasPrefix(command, "EHLO"), strings.HasPrefix(command, "HELO"):
+			reply("250 sink")
+		case strings.HasPrefix(command, "MAIL FROM:"):
+			current.from = strings.Trim(strings.TrimSpace(line)[len("MAIL FROM:"):], "<>")
+			reply("250 ok")
+		case strings.HasPrefix(command, "RCPT TO:"):
+			current.to = strings.Trim(strings.TrimSpace(line)[len("RCPT TO:"):], "<>")
+			reply("250 ok")
+		case command == "DATA":
+			reply("354 send data")
+			var data strings.Builder
+			for {
+				dataLine, err := r.ReadString('\n')
+				if err != nil {
+					return
+				}
+				if dataLine == ".\r\n" {
+					break
+				}
+				data.WriteString(strings.TrimPrefix(dataLine, "."))
+			}
+			current.data = data.String()
+			s.received <- current
+			reply("250 queued")
+		case command == "QUIT":
+			reply("221 bye")
+			return
+		default:
+			reply("502 not implemented")
+		}
+	}
+}
+
+func testMessage(t *testing.T) Message {
+	t.Helper()
+	msg, err := NewMessage("budi@example.com", "Verify your email", "verify_email", TemplateData{
+		Name:      "Budi <script>",
+		URL:       "http://localhost:5173/verify-email?token=abc",
+		ExpiresIn: "24 hours",
+	})
+	if err != nil {
+		t.Fatalf("NewMessage() error = %v", err)
+	}
+	return msg
+}
+
+// readParts membaca email mime dan mengembalikan isi per content type
+func readParts(t *testing.T, raw string) (*mail.Message, map[string]string) {
+	t.Helper()
+	parsed, err := mail.ReadMessage(strings.NewReader(raw))
+	if err != nil {
+		t.Fatalf("mail.ReadMessage() error = %v", err)
+	}
+	_, params, err := mime.ParseMediaType(parsed.Header.Get("Content-Type"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	parts := map[string]string{}
+	reader := multipart.NewReader(parsed.Body, params["boundary"])
+	for {
+		part, err := reader.NextPart()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		content, _ := io.ReadAll(part)
+		mediaType, _, _ := mime.ParseMediaType(part.Header.Get("Content-Type"))
+		parts[mediaType] = string(content)
+	}
+	return parsed, parts
+}
+
+func TestNewMessage_RendersTemplates(t *testing.T) {
+	msg := testMessage(t)
+	if !strings.Contains(msg.Text, "http://localhost:5173/verify-email?token=abc") || !strings.Contains(msg.Text, "24 hours") {
+		t.Errorf("Text = %q", msg.Text)
+	}
+	if !strings.Contains(msg.HTML, `href="http://localhost:5173/verify-email?token=abc"`) {
+		t.Errorf("HTML = %q", msg.HTML)
+	}
+	if strings.Contains(msg.HTML, "<script>") {
+		t.Error("HTML template must escape user data")
+	}
+
+	if _, err := NewMessage("budi@example.com", "x", "missing", TemplateData{}); err == nil {
+		t.Error("NewMessage() with unknown template should fail")
+	}
+}
+
+func TestSMTPMailer_SendToSink(t *testing.T) {
+	sink := newSMTPSink(t)
+	m, err := NewSMTPMailer(SMTPOptions{Host: "127.0.0.1", Port: sink.port(), From: "Project Management <no-reply@example.com>"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := m.Send(context.Background(), testMessage(t)); err != nil {
+		t.Fatalf("Send() error = %v", err)
+	}
+
+	got := <-sink.received
+	if got.from != "no-reply@example.com" || got.to != "budi@example.com" {
+		t.Errorf("envelope = %s -> %s", got.from, got.to)
+	}
+	parsed, parts := readParts(t, got.data)
+	if parsed.Header.Get("Subject") != "Verify your email" {
+		t.Errorf("Subject = %q", parsed.Header.Get("Subject"))
+	}
+	if !strings.Contains(parts["text/plain"], "token=abc") || !strings.Contains(parts["text/html"], "Verify email") {
+		t.Errorf("parts = %v", parts)
+	}
+}
+
+func TestSMTPMailer_DialError(t *testing.T) {
+	listener, _ := net.Listen("tcp", "127.0.0.1:0")
+	port := listener.Addr().(*net.TCPAddr).Port
+	listener.Close()
+
+	m, _ := NewSMTPMailer(SMTPOptions{Host: "127.0.0.1", Port: port, From: "no-reply@example.com"})
+	if err := m.Send(context.Background(), testMessage(t)); err == nil {
+		t.Error("Send() to closed port should fail")
+	}
+	if _, err := NewSMTPMailer(SMTPOptions{Host: "127.0.0.1", Port: 25, From: "bukan email"}); err == nil {
+		t.Error("NewSMTPMailer() with invalid sender should fail")
+	}
+}
+
+func TestFileMailer(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "mails")
+	m, err := NewFileMailer(dir, "no-reply@example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := m.Send(context.Background(), testMessage(t)); err != nil {
+		t.Fatalf("Send() error = %v", err)
+	}
+	if err := m.Send(context.Background(), Message{To: "bukan email"}); err == nil {
+		t.Error("Send() with invalid recipient should fail")
+	}
+
+	entries, _ := os.ReadDir(dir)
+	if len(entries) != 1 || filepath.Ext(entries[0].Name()) != ".eml" {
+		t.Fatalf("files = %v", entries)
+	}
+	raw, _ := os.ReadFile(filepath.Join(dir, entries[0].Name()))
+	parsed, parts := readParts(t, string(raw))
+	if parsed.Header.Get("To") != "<budi@example.com>" || !strings.Contains(parts["text/plain"], "token=abc") {
+		t.Errorf("To = %q, parts = %v", parsed.Header.Get("To"), parts)
+	}
+}
+
+func TestNew(t *testing.T) {
+	m, err := New(&config.Config{MailDriver: config.MailDriverLog})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := m.Send(context.Background(), testMessage(t)); err != nil {
+		t.Errorf("log mailer Send() error = %v", err)
+	}
+
+	if _, err := New(&config.Config{MailDriver: "pigeon"}); err == nil {
+		t.Error("New() with unknown driver should fail")
+	}
+}
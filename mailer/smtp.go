@@ -0,0 +1,100 @@
+package mailer
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"strconv"
+	"time"
+)
+
+// batas waktu kirim kalau context tidak punya deadline
+const smtpTimeout = 30 * time.Second
+
+type SMTPOptions struct {
+	Host     string
+	Port     int // 465 memakai TLS langsung, port lain memakai STARTTLS kalau server mendukung
+	Username string
+	Password string
+	From     string
+}
+
+type smtpMailer struct {
+	opts SMTPOptions
+	from *mail.Address
+}
+
+func NewSMTPMailer(opts SMTPOptions) (Mailer, error) {
+	if opts.Host == "" {
+		return nil, fmt.Errorf("smtp host is required")
+	}
+	from, err := mail.ParseAddress(opts.From)
+	if err != nil {
+		return nil, fmt.Errorf("invalid sender %q: %w", opts.From, err)
+	}
+	return &smtpMailer{opts: opts, from: from}, nil
+}
+
+func (m *smtpMailer) Send(ctx context.Context, msg Message) error {
+	body, err := encode(m.from, msg, time.Now())
+	if err != nil {
+		return err
+	}
+	to, _ := mail.ParseAddress(msg.To)
+
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "tcp", net.JoinHostPort(m.opts.Host, strconv.Itoa(m.opts.Port)))
+	if err != nil {
+		return err
+	}
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		deadline = time.Now().Add(smtpTimeout)
+	}
+	conn.SetDeadline(deadline)
+
+	tlsConfig := &tls.Config{ServerName: m.opts.Host}
+	if m.opts.Port == 465 {
+		conn = tls.Client(conn, tlsConfig)
+	}
+
+	client, err := smtp.NewClient(conn, m.opts.Host)
+	if err != nil {
+		conn.Close()
+		return err
+	}
+	defer client.Close()
+
+	if ok, _ := client.Extension("STARTTLS"); ok && m.opts.Port != 465 {
+		if err := client.StartTLS(tlsConfig); err != nil {
+			return err
+		}
+	}
+	//PlainAuth menolak mengirim password tanpa TLS kecuali ke localhost
+	if m.opts.Username != "" {
+		if err := client.Auth(smtp.PlainAuth("", m.opts.Username, m.opts.Password, m.opts.Host)); err != nil {
+			return err
+		}
+	}
+
+	if err := client.Mail(m.from.Address); err != nil {
+		return err
+	}
+	if err := client.Rcpt(to.Address); err != nil {
+		return err
+	}
+	w, err := client.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(body); err != nil {
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+	return client.Quit()
+}
@@ -0,0 +1,37 @@
+package mailer
+
+import (
+	"bytes"
+	"embed"
+	htmltemplate "html/template"
+	texttemplate "text/template"
+)
+
+//template email ada di folder templates, setiap email punya versi <nama>.txt dan <nama>.html
+
+//go:embed templates
+var templateFiles embed.FS
+
+var (
+	textTemplates = texttemplate.Must(texttemplate.ParseFS(templateFiles, "templates/*.txt"))
+	htmlTemplates = htmltemplate.Must(htmltemplate.ParseFS(templateFiles, "templates/*.html"))
+)
+
+// TemplateData adalah isi yang bisa dipakai di template
+type TemplateData struct {
+	Name      string
+	URL       string
+	ExpiresIn string
+}
+
+// NewMessage merender template text dan html sekaligus menjadi satu Message
+func NewMessage(to, subject, name string, data TemplateData) (Message, error) {
+	var text, html bytes.Buffer
+	if err := textTemplates.ExecuteTemplate(&text, name+".txt", data); err != nil {
+		return Message{}, err
+	}
+	if err := htmlTemplates.ExecuteTemplate(&html, name+".html", data); err != nil {
+		return Message{}, err
+	}
+	return Message{To: to, Subject: subject, Text: text.String(), HTML: html.String()}, nil
+}
@@ -1,34 +1,169 @@
 package main
 
 import (
+	"context"
 	"log"
+	"os"
+	"os/signal"
+	"strconv"
+	"syscall"
+	"time"
 
 	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/cors"
 	"github.com/odink789/project-management/config"
 	"github.com/odink789/project-management/controllers"
+	"github.com/odink789/project-management/database/backfill"
+	"github.com/odink789/project-management/database/migrations"
 	"github.com/odink789/project-management/database/seed"
+	"github.com/odink789/project-management/mailer"
+	"github.com/odink789/project-management/policies"
 	"github.com/odink789/project-management/repositories"
 	"github.com/odink789/project-management/routes"
 	"github.com/odink789/project-management/services"
+	"github.com/odink789/project-management/storage"
+	"github.com/odink789/project-management/thumbnail"
 )
 
 func main() {
-	config.LoadEnv()
+	if err := config.LoadEnv(); err != nil {
+		log.Fatal("Invalid configuration:\n", err)
+	}
 	config.ConnectDB()
 
-	seed.SeedAdmin()
+	//go run . migrate up | down [steps] | status
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		if err := migrations.Command(config.DB, os.Args[2:], os.Stdout); err != nil {
+			log.Fatal("Failed to run migration:", err)
+		}
+		return
+	}
+
+	if config.AppConfig.AutoMigrate {
+		applied, err := migrations.Up(config.DB)
+		if err != nil {
+			log.Fatal("Failed to migrate database:", err)
+		}
+		log.Printf("Database migrated, %d migration(s) applied", len(applied))
+	}
+
+	//SIGINT / SIGTERM membatalkan ctx, server dan worker background berhenti dengan rapi
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	if err := seed.SeedAdmin(ctx, config.DB); err != nil {
+		log.Fatal("Failed to seed admin user:", err)
+	}
+
+	if config.AppConfig.OrderingMode == config.OrderingModeRank {
+		if err := backfill.BackfillRanks(ctx, config.DB); err != nil {
+			log.Fatal("Failed to backfill ranks:", err)
+		}
+	}
 	//inisialisasi fiber
 
-	app := fiber.New()
+	//body limit sedikit di atas batas upload untuk field multipart lain nya
+	app := fiber.New(fiber.Config{
+		BodyLimit:    int(config.AppConfig.MaxUploadSize) + 1<<20,
+		ErrorHandler: controllers.ErrorHandler,
+	})
+	app.Use(cors.New(cors.Config{
+		AllowOrigins: config.AppConfig.CORSOrigin,
+	}))
 
-	userRepo := repositories.NewUserRepository()
-	userService := services.NewUserService(userRepo)
+	transactor := repositories.NewTransactor(config.DB)
+
+	mail, err := mailer.New(config.AppConfig)
+	if err != nil {
+		log.Fatal("Failed to initialize mailer:", err)
+	}
+	accountEmail := services.AccountEmail{
+		AppURL:          config.AppConfig.AppURL,
+		VerificationTTL: config.AppConfig.EmailVerificationExpire,
+		ResetTTL:        config.AppConfig.PasswordResetExpire,
+	}
+
+	userRepo := repositories.NewUserRepository(config.DB)
+	refreshTokenRepo := repositories.NewRefreshTokenRepository(config.DB)
+	userTokenRepo := repositories.NewUserTokenRepository(config.DB)
+	userService := services.NewUserService(userRepo, refreshTokenRepo, userTokenRepo, transactor, mail, accountEmail)
 	userController := controllers.NewUserController(userService)
 
-	routes.Setup(app, userController)
+	fileStorage, err := storage.New(config.AppConfig)
+	if err != nil {
+		log.Fatal("Failed to initialize storage:", err)
+	}
+
+	boardRepo := repositories.NewBoardRepository(config.DB)
+	boardMemberRepo := repositories.NewBoardMemberRepository(config.DB)
+	boardPolicy := policies.NewBoardPolicy(boardMemberRepo)
+	boardService := services.NewBoardService(boardRepo, boardMemberRepo, userRepo, fileStorage, boardPolicy)
+	boardController := controllers.NewBoardController(boardService)
+
+	listRepo := repositories.NewListRepository(config.DB)
+	cardRepo := repositories.NewCardRepository(config.DB)
+	if config.AppConfig.OrderingMode == config.OrderingModeRank {
+		listRepo = repositories.NewListRankRepository(config.DB)
+		cardRepo = repositories.NewCardRankRepository(config.DB)
+	}
+
+	listService := services.NewListService(listRepo, boardRepo, fileStorage, boardPolicy)
+	listController := controllers.NewListController(listService)
+
+	attachmentRepo := repositories.NewAttachmentRepository(config.DB)
+	cardService := services.NewCardService(cardRepo, listRepo, boardRepo, attachmentRepo, transactor, fileStorage, boardPolicy)
+	cardController := controllers.NewCardController(cardService)
+
+	cardAssigneeRepo := repositories.NewCardAssigneeRepository(config.DB)
+	cardAssigneeService := services.NewCardAssigneeService(cardAssigneeRepo, cardRepo, listRepo, boardRepo, boardMemberRepo, userRepo, boardPolicy)
+	cardAssigneeController := controllers.NewCardAssigneeController(cardAssigneeService)
+
+	labelRepo := repositories.NewLabelRepository(config.DB)
+	labelService := services.NewLabelService(labelRepo, cardRepo, listRepo, boardRepo, boardPolicy)
+	labelController := controllers.NewLabelController(labelService)
+
+	commentRepo := repositories.NewCommentRepository(config.DB)
+	commentService := services.NewCommentService(commentRepo, cardRepo, listRepo, boardRepo, boardPolicy)
+	commentController := controllers.NewCommentController(commentService)
+
+	//thumbnail gambar dibuat di background oleh 2 worker
+	thumbnailWorker := thumbnail.NewWorker(attachmentRepo, fileStorage, 100)
+	if err := thumbnailWorker.Start(ctx, 2); err != nil {
+		log.Println("Failed to load pending thumbnails:", err)
+	}
+	attachmentQuota := services.AttachmentQuota{
+		MaxFileSize: config.AppConfig.MaxUploadSize,
+		BoardBytes:  config.AppConfig.BoardStorageQuota,
+		UserBytes:   config.AppConfig.UserStorageQuota,
+	}
+	attachmentService := services.NewAttachmentService(attachmentRepo, cardRepo, listRepo, boardRepo, fileStorage, thumbnailWorker, attachmentQuota, boardPolicy)
+	attachmentController := controllers.NewAttachmentController(attachmentService)
+
+	routes.Setup(app, userController, boardController, listController, cardController, cardAssigneeController, labelController, commentController, attachmentController)
+
+	//request yang sedang berjalan ditunggu dulu sebelum server berhenti
+	shutdownDone := make(chan struct{})
+	go func() {
+		defer close(shutdownDone)
+		<-ctx.Done()
+		log.Println("Shutting down server")
+		if err := app.ShutdownWithTimeout(10 * time.Second); err != nil {
+			log.Println("Failed to shutdown server:", err)
+		}
+	}()
 
 	port := config.AppConfig.AppPort
 	log.Println("Server Is running On port :", port)
-	log.Fatal(app.Listen(":" + port))
+	if err := app.Listen(":" + strconv.Itoa(port)); err != nil {
+		log.Fatal(err)
+	}
+	<-shutdownDone
 
+	//thumbnail yang belum selesai tetap pending dan diproses lagi saat startup berikut nya
+	//email yang sedang dikirim ditunggu sampai selesai atau timeout
+	thumbnailWorker.Stop()
+	userService.Close()
+	thumbnailWorker.Wait()
+	userService.Wait()
+	log.Println("Server stopped")
 }
@@ -0,0 +1,44 @@
+package middleware
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/odink789/project-management/utils"
+)
+
+//middleware untuk route yang butuh login
+//header yang diharapkan : Authorization: Bearer <access_token>
+
+func JWTProtected() fiber.Handler {
+	return func(ctx *fiber.Ctx) error {
+		header := ctx.Get(fiber.HeaderAuthorization)
+		tokenString, found := strings.CutPrefix(header, "Bearer ")
+		if !found || strings.TrimSpace(tokenString) == "" {
+			return utils.Unauthorized(ctx, "Unauthorized", "missing or malformed token")
+		}
+
+		claims, err := utils.ParseToken(strings.TrimSpace(tokenString))
+		if err != nil {
+			if errors.Is(err, jwt.ErrTokenExpired) {
+				return utils.Unauthorized(ctx, "Unauthorized", "token expired")
+			}
+			return utils.Unauthorized(ctx, "Unauthorized", "invalid token")
+		}
+
+		//refresh token tidak boleh dipakai untuk akses api
+		if claims["type"] != utils.TokenTypeAccess {
+			return utils.Unauthorized(ctx, "Unauthorized", "invalid token")
+		}
+
+		principal, err := utils.NewPrincipal(claims)
+		if err != nil {
+			return utils.Unauthorized(ctx, "Unauthorized", "invalid token")
+		}
+
+		ctx.Locals(utils.PrincipalKey, principal)
+		return ctx.Next()
+	}
+}
@@ -0,0 +1,155 @@
+package middleware
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"github.com/odink789/project-management/config"
+	"github.com/odink789/project-management/utils"
+)
+
+func setupApp(t *testing.T) *fiber.App {
+	t.Helper()
+	config.AppConfig = &config.Config{
+		JWTSecret:        "test-secret",
+		JWTExpire:        15 * time.Minute,
+		JWTRefreshExpire: 24 * time.Hour,
+	}
+
+	app := fiber.New()
+	app.Get("/protected", JWTProtected(), func(c *fiber.Ctx) error {
+		principal, ok := utils.GetPrincipal(c)
+		if !ok {
+			return c.SendStatus(fiber.StatusInternalServerError)
+		}
+		return c.JSON(fiber.Map{
+			"user_id": principal.UserID,
+			"pub_id":  principal.PublicID,
+			"role":    principal.Role,
+			"email":   principal.Email,
+		})
+	})
+	return app
+}
+
+func doRequest(t *testing.T, app *fiber.App, authHeader string) (int, map[string]interface{}) {
+	t.Helper()
+	req := httptest.NewRequest(fiber.MethodGet, "/protected", nil)
+	if authHeader != "" {
+		req.Header.Set(fiber.HeaderAuthorization, authHeader)
+	}
+
+	resp, err := app.Test(req)
+	if err != nil {
+		t.Fatalf("app.Test() error = %v", err)
+	}
+	defer resp.Body.Close()
+
+	body := map[string]interface{}{}
+	json.NewDecoder(resp.Body).Decode(&body)
+	return resp.StatusCode, body
+}
+
+func TestJWTProtected_ValidToken(t *testing.T) {
+	app := setupApp(t)
+	pubID := uuid.New()
+
+	token, err := utils.GenerateToken(42, "admin", "admin@example.com", pubID, true)
+	if err != nil {
+		t.Fatalf("GenerateToken() error = %v", err)
+	}
+
+	status, body := doRequest(t, app, "Bearer "+token)
+	if status != fiber.StatusOK {
+		t.Fatalf("status = %d, want 200", status)
+	}
+	if body["user_id"] != float64(42) {
+		t.Errorf("user_id = %v, want 42", body["user_id"])
+	}
+	if body["pub_id"] != pubID.String() {
+		t.Errorf("pub_id = %v, want %v", body["pub_id"], pubID)
+	}
+	if body["role"] != "admin" || body["email"] != "admin@example.com" {
+		t.Errorf("unexpected principal %v", body)
+	}
+}
+
+func TestJWTProtected_Rejects(t *testing.T) {
+	app := setupApp(t)
+
+	expired := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+		"user_id": 1,
+		"pub_id":  uuid.NewString(),
+		"type":    utils.TokenTypeAccess,
+		"exp":     time.Now().Add(-time.Minute).Unix(),
+	})
+	expiredToken, _ := expired.SignedString([]byte("test-secret"))
+
+	refreshToken, _, err := utils.GenerateRefreshToken(1, uuid.New())
+	if err != nil {
+		t.Fatalf("GenerateRefreshToken() error = %v", err)
+	}
+
+	tests := []struct {
+		name      string
+		header    string
+		wantError string
+	}{
+		{name: "missing header", header: "", wantError: "missing or malformed token"},
+		{name: "wrong scheme", header: "Basic abc", wantError: "missing or malformed token"},
+		{name: "garbage token", header: "Bearer abc.def.ghi", wantError: "invalid token"},
+		{name: "expired token", header: "Bearer " + expiredToken, wantError: "token expired"},
+		{name: "refresh token", header: "Bearer " + refreshToken, wantError: "invalid token"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status, body := doRequest(t, app, tt.header)
+			if status != fiber.StatusUnauthorized {
+				t.Fatalf("status = %d, want 401", status)
+			}
+			if body["response_code"] != float64(fiber.StatusUnauthorized) {
+				t.Errorf("response_code = %v, want 401", body["response_code"])
+			}
+			if body["error"] != tt.wantError {
+				t.Errorf("error = %v, want %v", body["error"], tt.wantError)
+			}
+		})
+	}
+}
+
+func TestRequireVerifiedEmail(t *testing.T) {
+	setupApp(t)
+	app := fiber.New()
+	app.Get("/boards", JWTProtected(), RequireVerifiedEmail(), func(c *fiber.Ctx) error {
+		return c.SendStatus(fiber.StatusOK)
+	})
+
+	for _, verified := range []bool{true, false} {
+		token, err := utils.GenerateToken(1, "user", "user@example.com", uuid.New(), verified)
+		if err != nil {
+			t.Fatalf("GenerateToken() error = %v", err)
+		}
+		req := httptest.NewRequest(fiber.MethodGet, "/boards", nil)
+		req.Header.Set(fiber.HeaderAuthorization, "Bearer "+token)
+		resp, err := app.Test(req)
+		if err != nil {
+			t.Fatalf("app.Test() error = %v", err)
+		}
+		body := map[string]interface{}{}
+		json.NewDecoder(resp.Body).Decode(&body)
+		resp.Body.Close()
+
+		switch {
+		case verified && resp.StatusCode != fiber.StatusOK:
+			t.Errorf("verified status = %d, want 200", resp.StatusCode)
+		case !verified && (resp.StatusCode != fiber.StatusForbidden || body["error_code"] != utils.CodeEmailNotVerified):
+			t.Errorf("unverified status = %d, error_code = %v, want 403 %s", resp.StatusCode, body["error_code"], utils.CodeEmailNotVerified)
+		}
+	}
+}
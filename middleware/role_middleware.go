@@ -0,0 +1,24 @@
+package middleware
+
+import (
+	"slices"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/odink789/project-management/utils"
+)
+
+//dipasang setelah JWTProtected, contoh : RequireRole(models.RoleAdmin)
+
+func RequireRole(roles ...string) fiber.Handler {
+	return func(ctx *fiber.Ctx) error {
+		principal, ok := utils.GetPrincipal(ctx)
+		if !ok {
+			return utils.Unauthorized(ctx, "Unauthorized", "missing principal")
+		}
+
+		if !slices.Contains(roles, principal.Role) {
+			return utils.Forbidden(ctx, "Forbidden", "insufficient role")
+		}
+		return ctx.Next()
+	}
+}
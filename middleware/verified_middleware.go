@@ -0,0 +1,23 @@
+package middleware
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/odink789/project-management/utils"
+)
+
+//dipasang setelah JWTProtected untuk route yang butuh email terverifikasi
+//claim email_verified diambil dari access token, setelah verifikasi client perlu refresh token dulu
+
+func RequireVerifiedEmail() fiber.Handler {
+	return func(ctx *fiber.Ctx) error {
+		principal, ok := utils.GetPrincipal(ctx)
+		if !ok {
+			return utils.Unauthorized(ctx, "Unauthorized", "missing principal")
+		}
+
+		if !principal.EmailVerified {
+			return utils.Fail(ctx, fiber.StatusForbidden, utils.CodeEmailNotVerified, "Forbidden", "email is not verified", nil, nil)
+		}
+		return ctx.Next()
+	}
+}
@@ -0,0 +1,20 @@
+package models
+
+// AttachmentThumbnail adalah hasil resize gambar attachment, dibuat oleh worker di background
+type AttachmentThumbnail struct {
+	InternalID   int64  `json:"-" db:"internal_id" gorm:"primaryKey"`
+	AttachmentID int64  `json:"-" db:"attachment_internal_id" gorm:"column:attachment_internal_id;index"`
+	Size         string `json:"size" db:"size"` // small, medium, large
+	File         string `json:"-" db:"file"`    // key file di storage
+	Width        int    `json:"width" db:"width"`
+	Height       int    `json:"height" db:"height"`
+	ByteSize     int64  `json:"byte_size" db:"byte_size"`
+}
+
+//status pembuatan thumbnail di card_attachments, kosong berarti bukan gambar
+
+const (
+	ThumbnailPending = "pending"
+	ThumbnailReady   = "ready"
+	ThumbnailFailed  = "failed"
+)
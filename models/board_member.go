@@ -3,9 +3,11 @@ package models
 import "time"
 
 type BoardMember struct {
-	BoardID  int64     `json:"board_internal_id" db:"board_internal_id" gorm:"column:board_internal_id;primaryKey"`
-	UserID   int64     `json:"user_internal_id" db:"user_internal_id" gorm:"column:user_internal_id;primaryKey"` // composite primary key
+	BoardID  int64     `json:"-" db:"board_internal_id" gorm:"column:board_internal_id;primaryKey"`
+	UserID   int64     `json:"-" db:"user_internal_id" gorm:"column:user_internal_id;primaryKey"` // composite primary key
+	Role     BoardRole `json:"role" db:"role" gorm:"default:member"`
 	JoinedAt time.Time `json:"joined_at" db:"joined_at"`
+	User     *User     `json:"user,omitempty" gorm:"foreignKey:UserID;references:InternalID"` // diisi saat daftar member diambil
 }
 
 //board_member adalah table penghubung antara user dengan board nya
@@ -7,12 +7,15 @@ import (
 )
 
 type Card struct {
-	InternalID  int64      `json:"internal_id db:"internal_id" gorm:"primaryKey"`
+	InternalID  int64      `json:"-" db:"internal_id" gorm:"primaryKey"`
 	PublicID    uuid.UUID  `json:"public_id" db:"public_id"`
-	ListID      int64      `json:"list_internal_id" db:"list_internal_id" gorm:"column:list_internal_id"`
+	ListID      int64      `json:"-" db:"list_internal_id" gorm:"column:list_internal_id;index:idx_cards_rank,priority:1"`
 	Title       string     `json:"title" db:"title"`
 	Description string     `json:"description" db:"description"`
 	Duedate     *time.Time `json:"due_date,omitempty" db:"due_date"`
 	Position    int        `json:"position" db:"position"`
+	Rank        string     `json:"rank" db:"rank" gorm:"index:idx_cards_rank,priority:2"` // dipakai saat ORDERING_MODE=rank
+	CoverID     *int64     `json:"-" db:"cover_attachment_internal_id" gorm:"column:cover_attachment_internal_id"`
+	Cover       *CardCover `json:"cover,omitempty" gorm:"-"`
 	CreatedAt   time.Time  `json:"created_at" db:"created_at"`
 }
@@ -1,6 +1,10 @@
 package models
 
+import "time"
+
+// satu user hanya bisa di assign sekali ke card yang sama (composite primary key)
 type CardAssignee struct {
-	CardID int64 `json:"card_internal_id" db:"card_internal_id" gorm:"column:card_internal_id"`
-	UserID int64 `json:"user_internal_id" db:"user_internal_id" gorm:"column:user_internal_id"`
+	CardID     int64     `json:"-" db:"card_internal_id" gorm:"column:card_internal_id;primaryKey;autoIncrement:false"`
+	UserID     int64     `json:"-" db:"user_internal_id" gorm:"column:user_internal_id;primaryKey;autoIncrement:false"`
+	AssignedAt time.Time `json:"assigned_at" db:"assigned_at"`
 }
@@ -7,10 +7,15 @@ import (
 )
 
 type CardAttachment struct {
-	InternalID int64     `json:"internal_id" db:"internal_id" gorm:"primaryKey;autoIncrement"`
+	InternalID int64     `json:"-" db:"internal_id" gorm:"primaryKey;autoIncrement"`
 	PublicID   uuid.UUID `json:"public_id" db:"public_id"`
-	CardID     int64     `json:"card_internal_id" db:card_internal_id"`
-	UserID     int64     `json:"user_internal_id" db:"user_internal_id"`
-	file       string    `json:"file" db:"file"`
+	CardID     int64     `json:"-" db:"card_internal_id" gorm:"column:card_internal_id;index"`
+	UserID     int64     `json:"-" db:"user_internal_id" gorm:"column:user_internal_id;index"`
+	File       string    `json:"-" db:"file"` // key file di storage
+	FileName   string    `json:"file_name" db:"file_name"`
+	MimeType   string    `json:"mime_type" db:"mime_type"`
+	Size       int64     `json:"size" db:"size"`
+	Checksum   string    `json:"checksum" db:"checksum"` // sha256 hex
+	Thumbnail  string    `json:"thumbnail_status,omitempty" db:"thumbnail_status" gorm:"column:thumbnail_status"`
 	CreatedAt  time.Time `json:"created_at" db:"created_at"`
 }
@@ -0,0 +1,18 @@
+package models
+
+import "github.com/google/uuid"
+
+// CardCover adalah cover card yang ikut dikirim di response card, tidak disimpan di db
+// URL thumbnail sudah di sign jadi bisa langsung dipakai di tag <img>
+type CardCover struct {
+	AttachmentID uuid.UUID    `json:"attachment_id"`
+	Status       string       `json:"status"`
+	Images       []CoverImage `json:"images,omitempty"`
+}
+
+type CoverImage struct {
+	Size   string `json:"size"`
+	Width  int    `json:"width"`
+	Height int    `json:"height"`
+	URL    string `json:"url"`
+}
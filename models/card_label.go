@@ -1,8 +1,8 @@
 package models
 
 type Cardlabel struct {
-	CardID  int64 `json:"card_internal_id" db:"card_internal_id" gorm:"column:card_internal_id"`
-	LabelID int64 `json:"label_internal_id" db:"label_internal_id" gorm:"column:label_internal_id"`
+	CardID  int64 `json:"-" db:"card_internal_id" gorm:"column:card_internal_id;primaryKey"`
+	LabelID int64 `json:"-" db:"label_internal_id" gorm:"column:label_internal_id;primaryKey"` // composite primary key
 }
 
 //card_label ini berfungsi sebagai pivot table
@@ -6,8 +6,9 @@ import (
 )
 
 type CardPosition struct {
-	InternalID int64           `json:"internal_id" gorm:"primaryKey;autoIncrement"`
+	InternalID int64           `json:"-" gorm:"primaryKey;autoIncrement"`
 	PublicID   uuid.UUID       `json:"public_id" gorm:"type:uuid;not null"`
-	ListID     int64           `json:"list_internal_id" gorm:"column:list_internal_id;not null"`
+	ListID     int64           `json:"-" gorm:"column:list_internal_id;not null"`
 	CardOrder  types.UUIDArray `json:"card_order" gorm:"type:uuid[]"`
-}
\ No newline at end of file
+	Version    int64           `json:"version" gorm:"not null;default:0"` // naik setiap card_order berubah
+}
@@ -7,12 +7,21 @@ import (
 )
 
 type Comment struct {
-	InternalID int64     `json:"internal_id" db:"internal_id" gorm:"primaryKey"`
-	PublicID   uuid.UUID `json:"public_id" db:"public_id"`
-	CardID     int64     `json:"card_internal_id" db:"card_internal_id"`
-	CardPubID  uuid.UUID `json:"card_id" db:"card_id"`
-	UserID     int64     `json:"user_internal_id" db:"user_internal_id"`
-	UserPubID  uuid.UUID `json:"user_id" db:"user_id"`
-	Message    string    `json:"message" db:"message"`
-	CreatedAt  time.Time `json:"created_at" db:"created_at"`
+	InternalID int64      `json:"-" db:"internal_id" gorm:"primaryKey"`
+	PublicID   uuid.UUID  `json:"public_id" db:"public_id"`
+	CardID     int64      `json:"-" db:"card_internal_id" gorm:"column:card_internal_id;index"`
+	CardPubID  uuid.UUID  `json:"card_id" db:"card_id" gorm:"column:card_id"`
+	UserID     int64      `json:"-" db:"user_internal_id" gorm:"column:user_internal_id"`
+	UserPubID  uuid.UUID  `json:"user_id" db:"user_id" gorm:"column:user_id"`
+	Message    string     `json:"message" db:"message"`
+	CreatedAt  time.Time  `json:"created_at" db:"created_at"`
+	EditedAt   *time.Time `json:"edited_at,omitempty" db:"edited_at"`
+	DeletedAt  *time.Time `json:"deleted_at,omitempty" db:"deleted_at"` // soft delete, comment tetap tampil sebagai placeholder
+}
+
+// isi comment yang sudah dihapus tidak dikembalikan ke client
+const CommentRemovedMessage = "comment removed"
+
+func (c *Comment) IsDeleted() bool {
+	return c.DeletedAt != nil
 }
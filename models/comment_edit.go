@@ -0,0 +1,13 @@
+package models
+
+import "time"
+
+// CommentEdit menyimpan isi comment sebelum di edit / dihapus, dipakai moderator untuk melihat riwayat
+type CommentEdit struct {
+	InternalID int64     `json:"-" db:"internal_id" gorm:"primaryKey"`
+	CommentID  int64     `json:"-" db:"comment_internal_id" gorm:"column:comment_internal_id;index"`
+	Message    string    `json:"message" db:"message"`
+	EditedBy   int64     `json:"-" db:"edited_by"`
+	EditedAt   time.Time `json:"edited_at" db:"edited_at"`
+	Editor     *User     `json:"editor,omitempty" gorm:"foreignKey:EditedBy;references:InternalID"`
+}
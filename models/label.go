@@ -1,10 +1,19 @@
 package models
 
-import "github.com/google/uuid"
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
 
 type Label struct {
-	InternalID int64     `json:"internal_id" db:"internal_id" gorm:"primaryKey;autoIncrement"`
-	PublicID   uuid.UUID `json""public_id" db:"public_id"`
+	InternalID int64     `json:"-" db:"internal_id" gorm:"primaryKey;autoIncrement"`
+	PublicID   uuid.UUID `json:"public_id" db:"public_id"`
+	BoardID    int64     `json:"-" db:"board_internal_id" gorm:"column:board_internal_id;index"`
 	Name       string    `json:"name" db:"name"`
-	Color      string    `json:"color" db:"color"`
+	Color      string    `json:"color" db:"color"` // nama warna dari palette atau hex (#RGB / #RRGGBB)
+	CreatedAt  time.Time `json:"created_at" db:"created_at"`
 }
+
+// warna label yang bisa dipilih selain hex
+var LabelPalette = []string{"green", "yellow", "orange", "red", "purple", "blue", "sky", "lime", "pink", "black"}
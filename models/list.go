@@ -16,10 +16,11 @@ import (
 // }
 
 type List struct {
-	InternalID      int64     `json:"internal_id" db:"internal_id" gorm:"primaryKey;autoIncrement"`
+	InternalID      int64     `json:"-" db:"internal_id" gorm:"primaryKey;autoIncrement"`
 	PublicID        uuid.UUID `json:"public_id" db:"public_id"`
 	BoardPublicID   uuid.UUID `json:"board_public_id" db:"board_public_id" gorm:"board_public_id"`
 	Tittle          string    `json:"tittle" db:"tittle"`
 	CreatedAt       time.Time `json:"created_at" db:"created_at"`
-	BoardInternalID int64     `json:"-" db:"board_internal_id"`
+	BoardInternalID int64     `json:"-" db:"board_internal_id" gorm:"index:idx_lists_rank,priority:1"`
+	Rank            string    `json:"rank" db:"rank" gorm:"index:idx_lists_rank,priority:2"` // dipakai saat ORDERING_MODE=rank
 }
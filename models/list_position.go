@@ -6,10 +6,11 @@ import (
 )
 
 type ListPosition struct {
-	InternalID int64           `json:"internal_id" db:"internal_id" gorm:"primaryKey;autoIncrement"`
+	InternalID int64           `json:"-" db:"internal_id" gorm:"primaryKey;autoIncrement"`
 	PublicID   uuid.UUID       `json:"public_id" db:"public_id" gorm:"column:public_id"`
-	BoardID    int64           `json:"board_internal_id" db:"board_internal_id" gorm:"column:board_internal_id"`
+	BoardID    int64           `json:"-" db:"board_internal_id" gorm:"column:board_internal_id"`
 	ListOrder  types.UUIDArray `json:"list_order"`
+	Version    int64           `json:"version" db:"version" gorm:"not null;default:0"` // naik setiap list_order berubah
 }
 
 //membuat type data sendiri untuk listOrder
@@ -0,0 +1,22 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type RefreshToken struct {
+	InternalID  int64      `json:"-" db:"internal_id" gorm:"primaryKey;autoIncrement"`
+	PublicID    uuid.UUID  `json:"public_id" db:"public_id"`
+	UserID      int64      `json:"-" db:"user_internal_id" gorm:"column:user_internal_id;index"`
+	FamilyID    uuid.UUID  `json:"family_id" db:"family_id" gorm:"index"`
+	TokenHash   string     `json:"-" db:"token_hash" gorm:"uniqueIndex"`
+	DeviceLabel string     `json:"device_label" db:"device_label"`
+	ExpiresAt   time.Time  `json:"expires_at" db:"expires_at"`
+	RevokedAt   *time.Time `json:"revoked_at,omitempty" db:"revoked_at"`
+	CreatedAt   time.Time  `json:"created_at" db:"created_at"`
+}
+
+//refresh_token menyimpan hash dari refresh token yang pernah diterbitkan
+//satu family = satu sesi login, setiap rotasi membuat row baru dengan family yang sama
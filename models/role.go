@@ -0,0 +1,41 @@
+package models
+
+//role global user, disimpan di kolom users.role
+
+const (
+	RoleAdmin = "admin"
+	RoleUser  = "user"
+)
+
+//role user di dalam sebuah board, disimpan di kolom board_members.role
+
+type BoardRole string
+
+const (
+	BoardRoleOwner    BoardRole = "owner"
+	BoardRoleAdmin    BoardRole = "admin"
+	BoardRoleMember   BoardRole = "member"
+	BoardRoleObserver BoardRole = "observer"
+)
+
+// semakin besar angka nya semakin tinggi hak akses nya
+var boardRoleRank = map[BoardRole]int{
+	BoardRoleObserver: 1,
+	BoardRoleMember:   2,
+	BoardRoleAdmin:    3,
+	BoardRoleOwner:    4,
+}
+
+func (r BoardRole) IsValid() bool {
+	_, ok := boardRoleRank[r]
+	return ok
+}
+
+// AtLeast mengecek apakah role r minimal setara dengan role min
+func (r BoardRole) AtLeast(min BoardRole) bool {
+	rank, ok := boardRoleRank[r]
+	if !ok {
+		return false
+	}
+	return rank >= boardRoleRank[min]
+}
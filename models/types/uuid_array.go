@@ -1,14 +1,20 @@
 package types
 
 import (
-    "database/sql/driver"
-    "errors"
-    "fmt"
-    "strings"
+	"context"
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
 
-    "github.com/google/uuid"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
 )
 
+// UUIDArray disimpan sebagai uuid[] di postgres dan JSON array (text) di sqlite
 type UUIDArray []uuid.UUID
 
 func (a *UUIDArray) Scan(value interface{}) error {
@@ -21,44 +27,79 @@ func (a *UUIDArray) Scan(value interface{}) error {
 	case string:
 		str = v
 	default:
-		return errors.New("failed to parse UUIDArray : unsupport data type")
+		return errors.New("failed to parse UUIDArray : unsupported data type")
 	}
-str = strings.TrimPrefix(str, "{")
-str = strings.TrimSuffix(str, "}")
-parts := strings.Split(str, ",")
 
-// make ([]T , length , capacity)
-*a = make (UUIDArray, 0, len(parts))
-for _, s := range parts {
-	s = strings.TrimSpace(strings.Trim(s, `"`)) // akan menghapus spasi
-	if s == "" {
-		continue
+	//format sqlite : ["213123asdad","asdasda"]
+	if strings.HasPrefix(strings.TrimSpace(str), "[") {
+		var ids []uuid.UUID
+		if err := json.Unmarshal([]byte(str), &ids); err != nil {
+			return fmt.Errorf("invalid UUID in Array : %v", err)
+		}
+		*a = append(make(UUIDArray, 0, len(ids)), ids...)
+		return nil
 	}
-	u, err := uuid.Parse(s)
-	if err != nil {
-		return fmt.Errorf("invalid UUID in Array : %v", err)
+
+	str = strings.TrimPrefix(str, "{")
+	str = strings.TrimSuffix(str, "}")
+	parts := strings.Split(str, ",")
+
+	// make ([]T , length , capacity)
+	*a = make(UUIDArray, 0, len(parts))
+	for _, s := range parts {
+		s = strings.TrimSpace(strings.Trim(s, `"`)) // akan menghapus spasi
+		if s == "" {
+			continue
+		}
+		u, err := uuid.Parse(s)
+		if err != nil {
+			return fmt.Errorf("invalid UUID in Array : %v", err)
+		}
+		*a = append(*a, u)
 	}
-	*a = append(*a, u)
-}
-return nil
+	return nil
 
 }
 
-//{"550e8400-e29b-41d4-a716-446655440000","123e4567-e89b-12d3-a456-426614174000"}
+// {"550e8400-e29b-41d4-a716-446655440000","123e4567-e89b-12d3-a456-426614174000"}
 // UUID array menjadi format postgres nya
-func (a UUIDArray) Value() (driver.Value, error){
-if len(a) == 0 {
-	return "{}" , nil
-}
-//variable penampung
-postgreFormat := make([]string, 0, len(a))
-for _, value := range a {
-	postgreFormat = append(postgreFormat, fmt.Sprintf(`"%s"`, value.String()))
-}
-return "{" + strings.Join(postgreFormat, ",") + " }",nil
+func (a UUIDArray) Value() (driver.Value, error) {
+	if len(a) == 0 {
+		return "{}", nil
+	}
+	//variable penampung
+	postgreFormat := make([]string, 0, len(a))
+	for _, value := range a {
+		postgreFormat = append(postgreFormat, fmt.Sprintf(`"%s"`, value.String()))
+	}
+	return "{" + strings.Join(postgreFormat, ",") + "}", nil
 }
 
 func (UUIDArray) GormDataType() string {
 	return "uuid[]"
 }
 
+func (UUIDArray) GormDBDataType(db *gorm.DB, field *schema.Field) string {
+	if db.Dialector.Name() == "sqlite" {
+		return "text"
+	}
+	return "uuid[]"
+}
+
+// GormValue dipakai gorm saat insert / update, format nya mengikuti dialect database
+func (a UUIDArray) GormValue(ctx context.Context, db *gorm.DB) clause.Expr {
+	if db.Dialector.Name() != "sqlite" {
+		value, _ := a.Value()
+		return clause.Expr{SQL: "?", Vars: []interface{}{value}}
+	}
+
+	ids := []uuid.UUID(a)
+	if ids == nil {
+		ids = []uuid.UUID{}
+	}
+	encoded, err := json.Marshal(ids)
+	if err != nil {
+		db.AddError(err)
+	}
+	return clause.Expr{SQL: "?", Vars: []interface{}{string(encoded)}}
+}
@@ -289,7 +289,7 @@ func TestUUIDArray_Scan_ByteArray(t *testing.T) {
 	}
 }
 
-func TestUUIDArray_GormDataType(t *testing.T) {
+func TestUUIDArray_GormDataTypeName(t *testing.T) {
 	var arr UUIDArray
 	result := arr.GormDataType()
 
@@ -298,3 +298,24 @@ func TestUUIDArray_GormDataType(t *testing.T) {
 		t.Errorf("GormDataType() = %s, want %s", result, expected)
 	}
 }
+
+func TestUUIDArray_Scan_JSON(t *testing.T) {
+	a := uuid.MustParse("aaaaaaaa-aaaa-aaaa-aaaa-aaaaaaaaaaaa")
+	b := uuid.MustParse("bbbbbbbb-bbbb-bbbb-bbbb-bbbbbbbbbbbb")
+
+	var array UUIDArray
+	if err := array.Scan(`["` + a.String() + `","` + b.String() + `"]`); err != nil {
+		t.Fatalf("Scan failed: %v", err)
+	}
+	if len(array) != 2 || array[0] != a || array[1] != b {
+		t.Errorf("Scan() = %v", array)
+	}
+
+	if err := array.Scan([]byte("[]")); err != nil || len(array) != 0 {
+		t.Errorf("Scan(empty json) = %v, %v", array, err)
+	}
+
+	if err := array.Scan(`["not-a-uuid"]`); err == nil {
+		t.Error("expected error for invalid UUID in JSON array")
+	}
+}
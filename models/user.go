@@ -8,13 +8,14 @@ import (
 )
 
 type User struct {
-	InternalID int64          `json:"internal_id" db:"internal_id" gorm:"primaryKey"`
-	PublicID   uuid.UUID      `json:"public_id" db:"public_id"`
-	Name       string         `json:"name" db:"name"`
-	Email      string         `json:"email" db:"email" gorm:"unique"`
-	Password   string         `json:"password" db:"password" gorm:"column:password"`
-	Role       string         `json:"role" db:"role"`
-	UpdatedAt  time.Time      `json:"updated_at" db:"updated_at"`
-	CreatedAt  time.Time      `json:"created_at" db:"created_at"`
-	DeletedAt  gorm.DeletedAt `json:"-" gorm:"index"`
+	InternalID      int64          `json:"-" db:"internal_id" gorm:"primaryKey"`
+	PublicID        uuid.UUID      `json:"public_id" db:"public_id"`
+	Name            string         `json:"name" db:"name"`
+	Email           string         `json:"email" db:"email" gorm:"unique"`
+	Password        string         `json:"-" db:"password" gorm:"column:password"`
+	Role            string         `json:"role" db:"role"`
+	EmailVerifiedAt *time.Time     `json:"email_verified_at" db:"email_verified_at"` // nil berarti email belum diverifikasi
+	UpdatedAt       time.Time      `json:"updated_at" db:"updated_at"`
+	CreatedAt       time.Time      `json:"created_at" db:"created_at"`
+	DeletedAt       gorm.DeletedAt `json:"-" gorm:"index"`
 }
@@ -0,0 +1,21 @@
+package models
+
+import "time"
+
+type UserToken struct {
+	InternalID int64      `json:"-" db:"internal_id" gorm:"primaryKey;autoIncrement"`
+	UserID     int64      `json:"-" db:"user_internal_id" gorm:"column:user_internal_id;index"`
+	Purpose    string     `json:"purpose" db:"purpose"`
+	TokenHash  string     `json:"-" db:"token_hash" gorm:"uniqueIndex"`
+	ExpiresAt  time.Time  `json:"expires_at" db:"expires_at"`
+	UsedAt     *time.Time `json:"used_at,omitempty" db:"used_at"`
+	CreatedAt  time.Time  `json:"created_at" db:"created_at"`
+}
+
+//user_token menyimpan hash dari token sekali pakai yang dikirim lewat email
+//purpose membedakan kegunaan token nya, token yang sudah dipakai diisi used_at
+
+const (
+	TokenPurposeEmailVerification = "email_verification"
+	TokenPurposePasswordReset     = "password_reset"
+)
@@ -0,0 +1,104 @@
+package policies
+
+import (
+	"context"
+	"errors"
+
+	"github.com/odink789/project-management/models"
+	"github.com/odink789/project-management/repositories"
+	"github.com/odink789/project-management/utils"
+	"gorm.io/gorm"
+)
+
+//policy dipakai oleh service untuk mengecek apakah principal boleh melakukan aksi di sebuah board
+//admin global selalu diizinkan, user biasa dicek dari role nya di board_members
+
+var ErrForbidden = errors.New("you don't have permission to perform this action")
+
+type BoardPolicy interface {
+	CanViewBoard(ctx context.Context, principal *utils.Principal, board *models.Board) error
+	CanEditBoard(ctx context.Context, principal *utils.Principal, board *models.Board) error
+	CanDeleteBoard(ctx context.Context, principal *utils.Principal, board *models.Board) error
+	CanManageMembers(ctx context.Context, principal *utils.Principal, board *models.Board) error
+	CanEditList(ctx context.Context, principal *utils.Principal, board *models.Board) error
+	CanEditCard(ctx context.Context, principal *utils.Principal, board *models.Board) error
+	CanComment(ctx context.Context, principal *utils.Principal, board *models.Board) error
+	CanModerateComment(ctx context.Context, principal *utils.Principal, board *models.Board, comment *models.Comment) error
+	BoardRole(ctx context.Context, principal *utils.Principal, board *models.Board) (models.BoardRole, error)
+}
+
+type boardPolicy struct {
+	memberRepo repositories.BoardMemberRepository
+}
+
+func NewBoardPolicy(memberRepo repositories.BoardMemberRepository) BoardPolicy {
+	return &boardPolicy{memberRepo}
+}
+
+func (p *boardPolicy) CanViewBoard(ctx context.Context, principal *utils.Principal, board *models.Board) error {
+	return p.require(ctx, principal, board, models.BoardRoleObserver)
+}
+
+func (p *boardPolicy) CanEditBoard(ctx context.Context, principal *utils.Principal, board *models.Board) error {
+	return p.require(ctx, principal, board, models.BoardRoleAdmin)
+}
+
+func (p *boardPolicy) CanDeleteBoard(ctx context.Context, principal *utils.Principal, board *models.Board) error {
+	return p.require(ctx, principal, board, models.BoardRoleOwner)
+}
+
+func (p *boardPolicy) CanManageMembers(ctx context.Context, principal *utils.Principal, board *models.Board) error {
+	return p.require(ctx, principal, board, models.BoardRoleAdmin)
+}
+
+func (p *boardPolicy) CanEditList(ctx context.Context, principal *utils.Principal, board *models.Board) error {
+	return p.require(ctx, principal, board, models.BoardRoleMember)
+}
+
+// observer hanya bisa melihat, tidak bisa membuat / memindahkan card
+func (p *boardPolicy) CanEditCard(ctx context.Context, principal *utils.Principal, board *models.Board) error {
+	return p.require(ctx, principal, board, models.BoardRoleMember)
+}
+
+func (p *boardPolicy) CanComment(ctx context.Context, principal *utils.Principal, board *models.Board) error {
+	return p.require(ctx, principal, board, models.BoardRoleMember)
+}
+
+// comment hanya bisa di edit / dihapus oleh penulis nya (selama masih member) atau admin board
+func (p *boardPolicy) CanModerateComment(ctx context.Context, principal *utils.Principal, board *models.Board, comment *models.Comment) error {
+	if principal != nil && comment != nil && principal.UserID == comment.UserID {
+		return p.CanComment(ctx, principal, board)
+	}
+	return p.require(ctx, principal, board, models.BoardRoleAdmin)
+}
+
+// BoardRole mengembalikan role principal di board, ErrForbidden kalau bukan member
+func (p *boardPolicy) BoardRole(ctx context.Context, principal *utils.Principal, board *models.Board) (models.BoardRole, error) {
+	if principal == nil || board == nil {
+		return "", ErrForbidden
+	}
+
+	member, err := p.memberRepo.FindMember(ctx, board.InternalID, principal.UserID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return "", ErrForbidden
+		}
+		return "", err
+	}
+	return member.Role, nil
+}
+
+func (p *boardPolicy) require(ctx context.Context, principal *utils.Principal, board *models.Board, min models.BoardRole) error {
+	if principal != nil && principal.Role == models.RoleAdmin {
+		return nil
+	}
+
+	role, err := p.BoardRole(ctx, principal, board)
+	if err != nil {
+		return err
+	}
+	if !role.AtLeast(min) {
+		return ErrForbidden
+	}
+	return nil
+}
@@ -0,0 +1,109 @@
+package policies
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/odink789/project-management/models"
+	"github.com/odink789/project-management/repositories"
+	"github.com/odink789/project-management/utils"
+	"gorm.io/gorm"
+)
+
+type fakeMemberRepo struct {
+	repositories.BoardMemberRepository
+	roles map[int64]models.BoardRole
+}
+
+func (r *fakeMemberRepo) FindMember(ctx context.Context, boardID, userID int64) (*models.BoardMember, error) {
+	role, ok := r.roles[userID]
+	if !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return &models.BoardMember{BoardID: boardID, UserID: userID, Role: role}, nil
+}
+
+func TestBoardRole_AtLeast(t *testing.T) {
+	tests := []struct {
+		role models.BoardRole
+		min  models.BoardRole
+		want bool
+	}{
+		{models.BoardRoleOwner, models.BoardRoleAdmin, true},
+		{models.BoardRoleAdmin, models.BoardRoleAdmin, true},
+		{models.BoardRoleMember, models.BoardRoleAdmin, false},
+		{models.BoardRoleObserver, models.BoardRoleMember, false},
+		{models.BoardRoleObserver, models.BoardRoleObserver, true},
+		{models.BoardRole("guest"), models.BoardRoleObserver, false},
+	}
+
+	for _, tt := range tests {
+		if got := tt.role.AtLeast(tt.min); got != tt.want {
+			t.Errorf("%s.AtLeast(%s) = %v, want %v", tt.role, tt.min, got, tt.want)
+		}
+	}
+}
+
+func TestBoardPolicy(t *testing.T) {
+	policy := NewBoardPolicy(&fakeMemberRepo{roles: map[int64]models.BoardRole{
+		1: models.BoardRoleOwner,
+		2: models.BoardRoleAdmin,
+		3: models.BoardRoleMember,
+		4: models.BoardRoleObserver,
+	}})
+	board := &models.Board{InternalID: 10, PublicID: uuid.New()}
+
+	principal := func(id int64, role string) *utils.Principal {
+		return &utils.Principal{UserID: id, Role: role}
+	}
+
+	tests := []struct {
+		name      string
+		principal *utils.Principal
+		check     func(context.Context, *utils.Principal, *models.Board) error
+		allowed   bool
+	}{
+		{"observer can view", principal(4, models.RoleUser), policy.CanViewBoard, true},
+		{"observer cannot edit card", principal(4, models.RoleUser), policy.CanEditCard, false},
+		{"member can edit card", principal(3, models.RoleUser), policy.CanEditCard, true},
+		{"member cannot edit board", principal(3, models.RoleUser), policy.CanEditBoard, false},
+		{"admin can manage members", principal(2, models.RoleUser), policy.CanManageMembers, true},
+		{"admin cannot delete board", principal(2, models.RoleUser), policy.CanDeleteBoard, false},
+		{"owner can delete board", principal(1, models.RoleUser), policy.CanDeleteBoard, true},
+		{"non member cannot view", principal(99, models.RoleUser), policy.CanViewBoard, false},
+		{"global admin bypasses membership", principal(99, models.RoleAdmin), policy.CanDeleteBoard, true},
+		{"nil principal", nil, policy.CanViewBoard, false},
+	}
+
+	comment := &models.Comment{UserID: 3}
+	moderate := func(ctx context.Context, p *utils.Principal, b *models.Board) error {
+		return policy.CanModerateComment(ctx, p, b, comment)
+	}
+	tests = append(tests, []struct {
+		name      string
+		principal *utils.Principal
+		check     func(context.Context, *utils.Principal, *models.Board) error
+		allowed   bool
+	}{
+		{"author can moderate own comment", principal(3, models.RoleUser), moderate, true},
+		{"admin can moderate comment", principal(2, models.RoleUser), moderate, true},
+		{"observer cannot moderate comment", principal(4, models.RoleUser), moderate, false},
+		{"demoted author cannot moderate comment", principal(4, models.RoleUser), func(ctx context.Context, p *utils.Principal, b *models.Board) error {
+			return policy.CanModerateComment(ctx, p, b, &models.Comment{UserID: 4})
+		}, false},
+	}...)
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.check(context.Background(), tt.principal, board)
+			if tt.allowed && err != nil {
+				t.Errorf("expected allowed, got %v", err)
+			}
+			if !tt.allowed && !errors.Is(err, ErrForbidden) {
+				t.Errorf("expected ErrForbidden, got %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,262 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/odink789/project-management/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var (
+	ErrBoardQuotaExceeded = errors.New("board storage quota exceeded")
+	ErrUserQuotaExceeded  = errors.New("user storage quota exceeded")
+	ErrAttachmentDeleted  = errors.New("attachment has been deleted")
+)
+
+// BoardStorageUsage adalah total ukuran attachment (termasuk thumbnail) di sebuah board, dipakai untuk laporan admin
+type BoardStorageUsage struct {
+	BoardPublicID uuid.UUID `json:"board_public_id"`
+	Title         string    `json:"title"`
+	Bytes         int64     `json:"bytes"`
+	Files         int64     `json:"files"`
+}
+
+// UserStorageUsage adalah total ukuran attachment (termasuk thumbnail) yang di upload seorang user
+type UserStorageUsage struct {
+	UserPublicID uuid.UUID `json:"user_public_id"`
+	Name         string    `json:"name"`
+	Email        string    `json:"email"`
+	Bytes        int64     `json:"bytes"`
+	Files        int64     `json:"files"`
+}
+
+type AttachmentRepository interface {
+	FindByCard(ctx context.Context, cardID int64) ([]models.CardAttachment, error)
+	FindByPublicID(ctx context.Context, publicID string) (*models.CardAttachment, error)
+	FindByID(ctx context.Context, id int64) (*models.CardAttachment, error)
+	FindByIDs(ctx context.Context, ids []int64) ([]models.CardAttachment, error)
+	Create(ctx context.Context, attachment *models.CardAttachment) error
+	CreateWithinQuota(ctx context.Context, attachment *models.CardAttachment, boardID int64, boardQuota, userQuota int64) error
+	Delete(ctx context.Context, attachment *models.CardAttachment) ([]string, error)
+
+	BoardUsage(ctx context.Context, boardID int64) (int64, error)
+	UserUsage(ctx context.Context, userID int64) (int64, error)
+	UsageByBoard(ctx context.Context, limit int) ([]BoardStorageUsage, error)
+	UsageByUser(ctx context.Context, limit int) ([]UserStorageUsage, error)
+
+	FindThumbnails(ctx context.Context, attachmentIDs []int64) ([]models.AttachmentThumbnail, error)
+	FindPendingThumbnails(ctx context.Context) ([]models.CardAttachment, error)
+	UpdateThumbnailStatus(ctx context.Context, attachment *models.CardAttachment, status string) error
+	SaveThumbnails(ctx context.Context, attachment *models.CardAttachment, thumbnails []models.AttachmentThumbnail) error
+}
+
+type attachmentRepository struct {
+	db *gorm.DB
+}
+
+func NewAttachmentRepository(db *gorm.DB) AttachmentRepository {
+	return &attachmentRepository{db}
+}
+
+func (r *attachmentRepository) FindByCard(ctx context.Context, cardID int64) ([]models.CardAttachment, error) {
+	var attachments []models.CardAttachment
+	err := conn(ctx, r.db).Where("card_internal_id = ?", cardID).Order("created_at ASC").Find(&attachments).Error
+	return attachments, err
+}
+
+func (r *attachmentRepository) FindByPublicID(ctx context.Context, publicID string) (*models.CardAttachment, error) {
+	var attachment models.CardAttachment
+	err := conn(ctx, r.db).Where("public_id = ?", publicID).First(&attachment).Error
+	return &attachment, err
+}
+
+func (r *attachmentRepository) FindByID(ctx context.Context, id int64) (*models.CardAttachment, error) {
+	var attachment models.CardAttachment
+	err := conn(ctx, r.db).First(&attachment, id).Error
+	return &attachment, err
+}
+
+func (r *attachmentRepository) FindByIDs(ctx context.Context, ids []int64) ([]models.CardAttachment, error) {
+	var attachments []models.CardAttachment
+	if len(ids) == 0 {
+		return attachments, nil
+	}
+	err := conn(ctx, r.db).Where("internal_id IN ?", ids).Find(&attachments).Error
+	return attachments, err
+}
+
+func (r *attachmentRepository) Create(ctx context.Context, attachment *models.CardAttachment) error {
+	return conn(ctx, r.db).Create(attachment).Error
+}
+
+// row board dan user di lock dulu supaya upload yang bersamaan tidak bisa melewati quota
+// quota 0 berarti tidak dibatasi
+func (r *attachmentRepository) CreateWithinQuota(ctx context.Context, attachment *models.CardAttachment, boardID int64, boardQuota, userQuota int64) error {
+	return conn(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&models.Board{}, boardID).Error; err != nil {
+			return err
+		}
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&models.User{}, attachment.UserID).Error; err != nil {
+			return err
+		}
+
+		if boardQuota > 0 {
+			used, err := boardUsage(tx, boardID)
+			if err != nil {
+				return err
+			}
+			if used+attachment.Size > boardQuota {
+				return ErrBoardQuotaExceeded
+			}
+		}
+		if userQuota > 0 {
+			used, err := userUsage(tx, attachment.UserID)
+			if err != nil {
+				return err
+			}
+			if used+attachment.Size > userQuota {
+				return ErrUserQuotaExceeded
+			}
+		}
+		return tx.Create(attachment).Error
+	})
+}
+
+// thumbnail ikut dihapus dan card yang memakai attachment ini sebagai cover dikosongkan
+// row attachment di lock dulu supaya worker thumbnail tidak bisa menyimpan thumbnail baru di tengah jalan
+// key file attachment dan thumbnail nya dikembalikan untuk dihapus dari storage setelah commit
+func (r *attachmentRepository) Delete(ctx context.Context, attachment *models.CardAttachment) ([]string, error) {
+	var files []string
+	err := conn(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&models.Card{}).
+			Where("cover_attachment_internal_id = ?", attachment.InternalID).
+			Update("cover_attachment_internal_id", nil).Error; err != nil {
+			return err
+		}
+
+		var err error
+		files, err = deleteAttachments(tx, "internal_id = ?", attachment.InternalID)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return files, nil
+}
+
+func (r *attachmentRepository) FindThumbnails(ctx context.Context, attachmentIDs []int64) ([]models.AttachmentThumbnail, error) {
+	var thumbnails []models.AttachmentThumbnail
+	if len(attachmentIDs) == 0 {
+		return thumbnails, nil
+	}
+	err := conn(ctx, r.db).Where("attachment_internal_id IN ?", attachmentIDs).Order("width ASC").Find(&thumbnails).Error
+	return thumbnails, err
+}
+
+// dipakai saat startup untuk melanjutkan thumbnail yang belum sempat dibuat
+func (r *attachmentRepository) FindPendingThumbnails(ctx context.Context) ([]models.CardAttachment, error) {
+	var attachments []models.CardAttachment
+	err := conn(ctx, r.db).Where("thumbnail_status = ?", models.ThumbnailPending).Order("internal_id ASC").Find(&attachments).Error
+	return attachments, err
+}
+
+func (r *attachmentRepository) UpdateThumbnailStatus(ctx context.Context, attachment *models.CardAttachment, status string) error {
+	if err := conn(ctx, r.db).Model(attachment).Update("thumbnail_status", status).Error; err != nil {
+		return err
+	}
+	attachment.Thumbnail = status
+	return nil
+}
+
+// thumbnail lama diganti semua lalu status nya jadi ready
+// attachment yang sudah dihapus selama thumbnail dibuat mengembalikan ErrAttachmentDeleted
+func (r *attachmentRepository) SaveThumbnails(ctx context.Context, attachment *models.CardAttachment, thumbnails []models.AttachmentThumbnail) error {
+	err := conn(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		var current models.CardAttachment
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Select("internal_id").First(&current, attachment.InternalID).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrAttachmentDeleted
+		}
+		if err != nil {
+			return err
+		}
+
+		if err := tx.Where("attachment_internal_id = ?", attachment.InternalID).Delete(&models.AttachmentThumbnail{}).Error; err != nil {
+			return err
+		}
+		if len(thumbnails) > 0 {
+			if err := tx.Create(&thumbnails).Error; err != nil {
+				return err
+			}
+		}
+		return tx.Model(attachment).Update("thumbnail_status", models.ThumbnailReady).Error
+	})
+	if err != nil {
+		return err
+	}
+	attachment.Thumbnail = models.ThumbnailReady
+	return nil
+}
+
+// ukuran satu attachment termasuk thumbnail nya, dipakai untuk quota dan laporan admin
+// thumbnail baru ada setelah diproses worker, jadi quota saat upload hanya memakai thumbnail yang sudah tersimpan
+const attachmentBytes = "card_attachments.size + COALESCE((SELECT SUM(attachment_thumbnails.byte_size) FROM attachment_thumbnails WHERE attachment_thumbnails.attachment_internal_id = card_attachments.internal_id), 0)"
+
+// pemakaian dihitung langsung dari card_attachments dan attachment_thumbnails,
+// jadi otomatis benar lagi saat attachment / card / board dihapus
+func (r *attachmentRepository) BoardUsage(ctx context.Context, boardID int64) (int64, error) {
+	return boardUsage(conn(ctx, r.db), boardID)
+}
+
+func (r *attachmentRepository) UserUsage(ctx context.Context, userID int64) (int64, error) {
+	return userUsage(conn(ctx, r.db), userID)
+}
+
+func (r *attachmentRepository) UsageByBoard(ctx context.Context, limit int) ([]BoardStorageUsage, error) {
+	var usage []BoardStorageUsage
+	err := conn(ctx, r.db).Table("card_attachments").
+		Select("boards.public_id AS board_public_id, boards.title, SUM(" + attachmentBytes + ") AS bytes, COUNT(*) AS files").
+		Joins("JOIN cards ON cards.internal_id = card_attachments.card_internal_id").
+		Joins("JOIN lists ON lists.internal_id = cards.list_internal_id").
+		Joins("JOIN boards ON boards.internal_id = lists.board_internal_id").
+		Group("boards.internal_id, boards.public_id, boards.title").
+		Order("bytes DESC").
+		Limit(limit).
+		Scan(&usage).Error
+	return usage, err
+}
+
+func (r *attachmentRepository) UsageByUser(ctx context.Context, limit int) ([]UserStorageUsage, error) {
+	var usage []UserStorageUsage
+	err := conn(ctx, r.db).Table("card_attachments").
+		Select("users.public_id AS user_public_id, users.name, users.email, SUM(" + attachmentBytes + ") AS bytes, COUNT(*) AS files").
+		Joins("JOIN users ON users.internal_id = card_attachments.user_internal_id").
+		Group("users.internal_id, users.public_id, users.name, users.email").
+		Order("bytes DESC").
+		Limit(limit).
+		Scan(&usage).Error
+	return usage, err
+}
+
+func boardUsage(tx *gorm.DB, boardID int64) (int64, error) {
+	var used int64
+	err := tx.Table("card_attachments").
+		Select("COALESCE(SUM("+attachmentBytes+"), 0)").
+		Joins("JOIN cards ON cards.internal_id = card_attachments.card_internal_id").
+		Joins("JOIN lists ON lists.internal_id = cards.list_internal_id").
+		Where("lists.board_internal_id = ?", boardID).
+		Scan(&used).Error
+	return used, err
+}
+
+func userUsage(tx *gorm.DB, userID int64) (int64, error) {
+	var used int64
+	err := tx.Table("card_attachments").
+		Select("COALESCE(SUM("+attachmentBytes+"), 0)").
+		Where("card_attachments.user_internal_id = ?", userID).
+		Scan(&used).Error
+	return used, err
+}
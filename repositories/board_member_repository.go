@@ -0,0 +1,59 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/odink789/project-management/models"
+	"gorm.io/gorm"
+)
+
+type BoardMemberRepository interface {
+	FindMember(ctx context.Context, boardID, userID int64) (*models.BoardMember, error)
+	FindByBoard(ctx context.Context, boardID int64) ([]models.BoardMember, error)
+	Create(ctx context.Context, member *models.BoardMember) error
+	UpdateRole(ctx context.Context, member *models.BoardMember) error
+	Delete(ctx context.Context, member *models.BoardMember) error
+}
+
+type boardMemberRepository struct {
+	db *gorm.DB
+}
+
+func NewBoardMemberRepository(db *gorm.DB) BoardMemberRepository {
+	return &boardMemberRepository{db}
+}
+
+func (r *boardMemberRepository) FindMember(ctx context.Context, boardID, userID int64) (*models.BoardMember, error) {
+	var member models.BoardMember
+	err := conn(ctx, r.db).Where("board_internal_id = ? AND user_internal_id = ?", boardID, userID).First(&member).Error
+	return &member, err
+}
+
+func (r *boardMemberRepository) FindByBoard(ctx context.Context, boardID int64) ([]models.BoardMember, error) {
+	var members []models.BoardMember
+	err := conn(ctx, r.db).Preload("User").Where("board_internal_id = ?", boardID).Order("joined_at ASC").Find(&members).Error
+	return members, err
+}
+
+func (r *boardMemberRepository) Create(ctx context.Context, member *models.BoardMember) error {
+	return conn(ctx, r.db).Omit("User").Create(member).Error
+}
+
+func (r *boardMemberRepository) UpdateRole(ctx context.Context, member *models.BoardMember) error {
+	return conn(ctx, r.db).Model(&models.BoardMember{}).
+		Where("board_internal_id = ? AND user_internal_id = ?", member.BoardID, member.UserID).
+		Update("role", member.Role).Error
+}
+
+// member yang dikeluarkan juga dilepas dari semua card di board tersebut
+func (r *boardMemberRepository) Delete(ctx context.Context, member *models.BoardMember) error {
+	return conn(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		listIDs := tx.Model(&models.List{}).Select("internal_id").Where("board_internal_id = ?", member.BoardID)
+		if err := tx.Where("user_internal_id = ? AND card_internal_id IN (?)", member.UserID, cardIDsInLists(tx, listIDs)).
+			Delete(&models.CardAssignee{}).Error; err != nil {
+			return err
+		}
+		return tx.Where("board_internal_id = ? AND user_internal_id = ?", member.BoardID, member.UserID).
+			Delete(&models.BoardMember{}).Error
+	})
+}
@@ -0,0 +1,114 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/odink789/project-management/models"
+	"github.com/odink789/project-management/models/types"
+	"gorm.io/gorm"
+)
+
+type BoardRepository interface {
+	Create(ctx context.Context, board *models.Board) error
+	FindByPublicID(ctx context.Context, publicID string) (*models.Board, error)
+	FindByID(ctx context.Context, id int64) (*models.Board, error)
+	FindByMember(ctx context.Context, userID int64) ([]models.Board, error)
+	Update(ctx context.Context, board *models.Board) error
+	Delete(ctx context.Context, board *models.Board) ([]string, error)
+}
+
+type boardRepository struct {
+	db *gorm.DB
+}
+
+func NewBoardRepository(db *gorm.DB) BoardRepository {
+	return &boardRepository{db}
+}
+
+// board baru langsung punya owner di board_members dan list_position kosong
+func (r *boardRepository) Create(ctx context.Context, board *models.Board) error {
+	return conn(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(board).Error; err != nil {
+			return err
+		}
+
+		owner := models.BoardMember{
+			BoardID:  board.InternalID,
+			UserID:   board.OwnerID,
+			Role:     models.BoardRoleOwner,
+			JoinedAt: board.CreatedAt,
+		}
+		if err := tx.Create(&owner).Error; err != nil {
+			return err
+		}
+
+		position := models.ListPosition{
+			PublicID:  uuid.New(),
+			BoardID:   board.InternalID,
+			ListOrder: types.UUIDArray{},
+		}
+		return tx.Create(&position).Error
+	})
+}
+
+func (r *boardRepository) FindByPublicID(ctx context.Context, publicID string) (*models.Board, error) {
+	var board models.Board
+	err := conn(ctx, r.db).Where("public_id = ?", publicID).First(&board).Error
+	return &board, err
+}
+
+func (r *boardRepository) FindByID(ctx context.Context, id int64) (*models.Board, error) {
+	var board models.Board
+	err := conn(ctx, r.db).First(&board, id).Error
+	return &board, err
+}
+
+func (r *boardRepository) FindByMember(ctx context.Context, userID int64) ([]models.Board, error) {
+	var boards []models.Board
+	err := conn(ctx, r.db).
+		Joins("JOIN board_members ON board_members.board_internal_id = boards.internal_id").
+		Where("board_members.user_internal_id = ?", userID).
+		Order("boards.created_at DESC").
+		Find(&boards).Error
+	return boards, err
+}
+
+func (r *boardRepository) Update(ctx context.Context, board *models.Board) error {
+	return conn(ctx, r.db).Model(board).
+		Select("title", "description", "duedate").
+		Updates(board).Error
+}
+
+// hapus board beserta list, card, label dan member nya
+func (r *boardRepository) Delete(ctx context.Context, board *models.Board) ([]string, error) {
+	var files []string
+	err := conn(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		listIDs := tx.Model(&models.List{}).Select("internal_id").Where("board_internal_id = ?", board.InternalID)
+		var err error
+		files, err = deleteCards(tx, cardIDsInLists(tx, listIDs))
+		if err != nil {
+			return err
+		}
+		if err := tx.Where("list_internal_id IN (?)", listIDs).Delete(&models.CardPosition{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("board_internal_id = ?", board.InternalID).Delete(&models.Label{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("board_internal_id = ?", board.InternalID).Delete(&models.List{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("board_internal_id = ?", board.InternalID).Delete(&models.BoardMember{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("board_internal_id = ?", board.InternalID).Delete(&models.ListPosition{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(board).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return files, nil
+}
@@ -0,0 +1,86 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/odink789/project-management/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var ErrAlreadyAssigned = errors.New("user is already assigned to this card")
+
+// AssignedCard adalah card yang di assign ke user beserta list dan board nya, dipakai untuk dashboard
+type AssignedCard struct {
+	models.Card
+	ListPublicID  uuid.UUID `json:"list_public_id"`
+	BoardPublicID uuid.UUID `json:"board_public_id"`
+	BoardTitle    string    `json:"board_title"`
+}
+
+type CardAssigneeRepository interface {
+	FindUsersByCard(ctx context.Context, cardID int64) ([]models.User, error)
+	FindCardsByUser(ctx context.Context, userID int64) ([]AssignedCard, error)
+	Create(ctx context.Context, assignee *models.CardAssignee) error
+	Delete(ctx context.Context, cardID, userID int64) error
+}
+
+type cardAssigneeRepository struct {
+	db *gorm.DB
+}
+
+func NewCardAssigneeRepository(db *gorm.DB) CardAssigneeRepository {
+	return &cardAssigneeRepository{db}
+}
+
+func (r *cardAssigneeRepository) FindUsersByCard(ctx context.Context, cardID int64) ([]models.User, error) {
+	var users []models.User
+	err := conn(ctx, r.db).
+		Joins("JOIN card_assignees ON card_assignees.user_internal_id = users.internal_id").
+		Where("card_assignees.card_internal_id = ?", cardID).
+		Order("card_assignees.assigned_at ASC").
+		Find(&users).Error
+	return users, err
+}
+
+// hanya card dari board yang user nya masih jadi member
+func (r *cardAssigneeRepository) FindCardsByUser(ctx context.Context, userID int64) ([]AssignedCard, error) {
+	var cards []AssignedCard
+	err := conn(ctx, r.db).Table("cards").
+		Select("cards.*, lists.public_id AS list_public_id, boards.public_id AS board_public_id, boards.title AS board_title").
+		Joins("JOIN card_assignees ON card_assignees.card_internal_id = cards.internal_id").
+		Joins("JOIN lists ON lists.internal_id = cards.list_internal_id").
+		Joins("JOIN boards ON boards.internal_id = lists.board_internal_id").
+		Joins("JOIN board_members ON board_members.board_internal_id = boards.internal_id AND board_members.user_internal_id = card_assignees.user_internal_id").
+		Where("card_assignees.user_internal_id = ?", userID).
+		Order("cards.duedate IS NULL, cards.duedate ASC, cards.created_at DESC").
+		Scan(&cards).Error
+	return cards, err
+}
+
+// composite primary key mencegah assign dobel, walaupun ada dua request bersamaan
+func (r *cardAssigneeRepository) Create(ctx context.Context, assignee *models.CardAssignee) error {
+	result := conn(ctx, r.db).Clauses(clause.OnConflict{DoNothing: true}).Create(assignee)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrAlreadyAssigned
+	}
+	return nil
+}
+
+func (r *cardAssigneeRepository) Delete(ctx context.Context, cardID, userID int64) error {
+	result := conn(ctx, r.db).
+		Where("card_internal_id = ? AND user_internal_id = ?", cardID, userID).
+		Delete(&models.CardAssignee{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
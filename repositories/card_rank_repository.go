@@ -0,0 +1,161 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/odink789/project-management/models"
+	"github.com/odink789/project-management/models/types"
+	"gorm.io/gorm"
+)
+
+// cardRankRepository mengurutkan card memakai kolom rank (ORDERING_MODE=rank)
+// card_order di card_positions tidak lagi ditulis, row nya hanya dipakai untuk lock dan version
+type cardRankRepository struct {
+	cardRepository
+}
+
+func NewCardRankRepository(db *gorm.DB) CardRepository {
+	return &cardRankRepository{cardRepository{db}}
+}
+
+func (r *cardRankRepository) FindByList(ctx context.Context, listID int64) ([]models.Card, error) {
+	var cards []models.Card
+	err := conn(ctx, r.db).Where("list_internal_id = ?", listID).Order(rankOrder).Find(&cards).Error
+	return cards, err
+}
+
+// card_order tidak ditulis di mode rank, position hanya membawa version
+// urutan lengkap dibaca lewat FindByList / FindOrdered
+func (r *cardRankRepository) FindPosition(ctx context.Context, listID int64) (*models.CardPosition, error) {
+	position, err := r.cardRepository.FindPosition(ctx, listID)
+	if err != nil {
+		return nil, err
+	}
+	position.CardOrder = nil
+	return position, nil
+}
+
+// card cukup dibaca sekali, card_order diisi dari hasil nya
+func (r *cardRankRepository) FindOrdered(ctx context.Context, listID int64) ([]models.Card, *models.CardPosition, error) {
+	cards, err := r.FindByList(ctx, listID)
+	if err != nil {
+		return nil, nil, err
+	}
+	position, err := r.cardRepository.FindPosition(ctx, listID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	position.CardOrder = make(types.UUIDArray, len(cards))
+	for i, card := range cards {
+		position.CardOrder[i] = card.PublicID
+	}
+	return cards, position, nil
+}
+
+func (r *cardRankRepository) Create(ctx context.Context, card *models.Card, index int) error {
+	return conn(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		save := func(rank string) error {
+			card.Rank = rank
+			if card.InternalID == 0 {
+				return tx.Create(card).Error
+			}
+			return tx.Model(card).Update("rank", rank).Error
+		}
+		lock := func() error {
+			position, err := lockCardPosition(tx, card.ListID)
+			if err != nil {
+				return err
+			}
+			return bumpCardVersion(tx, position)
+		}
+
+		_, err := placeRank(tx, cardRankScope(card.ListID), &card.InternalID, index, save, lock)
+		return err
+	})
+}
+
+// version dinaikkan setelah card dihapus supaya lock card_position dipegang sesingkat mungkin
+func (r *cardRankRepository) Delete(ctx context.Context, card *models.Card) ([]string, error) {
+	var files []string
+	err := conn(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		var err error
+		files, err = deleteCards(tx, []int64{card.InternalID})
+		if err != nil {
+			return err
+		}
+
+		position, err := lockCardPosition(tx, card.ListID)
+		if err != nil {
+			return err
+		}
+		return bumpCardVersion(tx, position)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return files, nil
+}
+
+// cukup update rank dan list_internal_id card yang dipindah, card lain tidak disentuh kecuali saat rebalance
+// card_order tidak dibangun ulang, position yang dikembalikan hanya membawa version
+// card_position baru di lock setelah rank disimpan (lihat placeRank), version dicek saat itu juga
+func (r *cardRankRepository) Move(ctx context.Context, card *models.Card, targetListID int64, index int, sourceVersion, targetVersion *int64) (*models.CardPosition, *models.CardPosition, error) {
+	var source, target *models.CardPosition
+	err := conn(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		if err := lockCardList(tx, card); err != nil {
+			return err
+		}
+
+		sourceListID := card.ListID
+		save := func(rank string) error {
+			return tx.Model(card).Updates(map[string]interface{}{
+				"list_internal_id": targetListID,
+				"rank":             rank,
+			}).Error
+		}
+		lock := func() error {
+			var err error
+			source, target, err = lockMovePositions(tx, sourceListID, targetListID)
+			if err != nil {
+				return err
+			}
+			if err := checkVersion(sourceVersion, source.Version); err != nil {
+				return err
+			}
+			if err := checkVersion(targetVersion, target.Version); err != nil {
+				return err
+			}
+
+			if err := bumpCardVersion(tx, source); err != nil {
+				return err
+			}
+			if target != source {
+				return bumpCardVersion(tx, target)
+			}
+			return nil
+		}
+
+		rank, err := placeRank(tx, cardRankScope(targetListID), &card.InternalID, index, save, lock)
+		if err != nil {
+			return err
+		}
+		card.ListID = targetListID
+		card.Rank = rank
+		source.CardOrder, target.CardOrder = nil, nil
+		return nil
+	})
+	return source, target, err
+}
+
+func cardRankScope(listID int64) rankScope {
+	return rankScope{model: &models.Card{}, column: "list_internal_id", parentID: listID}
+}
+
+func bumpCardVersion(tx *gorm.DB, position *models.CardPosition) error {
+	if err := tx.Model(position).Update("version", gorm.Expr("version + 1")).Error; err != nil {
+		return err
+	}
+	position.Version++
+	return nil
+}
@@ -0,0 +1,259 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/odink789/project-management/models"
+	"github.com/odink789/project-management/models/types"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type CardRepository interface {
+	FindByList(ctx context.Context, listID int64) ([]models.Card, error)
+	FindByPublicID(ctx context.Context, publicID string) (*models.Card, error)
+	FindByID(ctx context.Context, id int64) (*models.Card, error)
+	FindPosition(ctx context.Context, listID int64) (*models.CardPosition, error)
+	FindOrdered(ctx context.Context, listID int64) ([]models.Card, *models.CardPosition, error)
+	Create(ctx context.Context, card *models.Card, index int) error
+	Update(ctx context.Context, card *models.Card) error
+	UpdateCover(ctx context.Context, card *models.Card) error
+	Delete(ctx context.Context, card *models.Card) ([]string, error)
+	Move(ctx context.Context, card *models.Card, targetListID int64, index int, sourceVersion, targetVersion *int64) (*models.CardPosition, *models.CardPosition, error)
+}
+
+type cardRepository struct {
+	db *gorm.DB
+}
+
+func NewCardRepository(db *gorm.DB) CardRepository {
+	return &cardRepository{db}
+}
+
+func (r *cardRepository) FindByList(ctx context.Context, listID int64) ([]models.Card, error) {
+	var cards []models.Card
+	err := conn(ctx, r.db).Where("list_internal_id = ?", listID).Order("created_at ASC").Find(&cards).Error
+	return cards, err
+}
+
+func (r *cardRepository) FindByPublicID(ctx context.Context, publicID string) (*models.Card, error) {
+	var card models.Card
+	err := conn(ctx, r.db).Where("public_id = ?", publicID).First(&card).Error
+	return &card, err
+}
+
+func (r *cardRepository) FindByID(ctx context.Context, id int64) (*models.Card, error) {
+	var card models.Card
+	err := conn(ctx, r.db).First(&card, id).Error
+	return &card, err
+}
+
+func (r *cardRepository) FindPosition(ctx context.Context, listID int64) (*models.CardPosition, error) {
+	var position models.CardPosition
+	err := conn(ctx, r.db).Where("list_internal_id = ?", listID).First(&position).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return &models.CardPosition{ListID: listID, CardOrder: types.UUIDArray{}}, nil
+	}
+	return &position, err
+}
+
+// card di list sesuai urutan card_order, position nya ikut dikembalikan untuk version
+func (r *cardRepository) FindOrdered(ctx context.Context, listID int64) ([]models.Card, *models.CardPosition, error) {
+	cards, err := r.FindByList(ctx, listID)
+	if err != nil {
+		return nil, nil, err
+	}
+	position, err := r.FindPosition(ctx, listID)
+	if err != nil {
+		return nil, nil, err
+	}
+	return SortByOrder(cards, position.CardOrder, func(c models.Card) uuid.UUID { return c.PublicID }), position, nil
+}
+
+// simpan card dan sisipkan public id nya ke card_order di index tertentu (index < 0 berarti paling akhir)
+func (r *cardRepository) Create(ctx context.Context, card *models.Card, index int) error {
+	return conn(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(card).Error; err != nil {
+			return err
+		}
+
+		position, err := lockCardPosition(tx, card.ListID)
+		if err != nil {
+			return err
+		}
+
+		position.CardOrder = insertAt(position.CardOrder, index, card.PublicID)
+		return saveCardOrder(tx, position)
+	})
+}
+
+func (r *cardRepository) Update(ctx context.Context, card *models.Card) error {
+	return conn(ctx, r.db).Model(card).
+		Select("title", "description", "duedate").
+		Updates(card).Error
+}
+
+func (r *cardRepository) UpdateCover(ctx context.Context, card *models.Card) error {
+	return conn(ctx, r.db).Model(card).Update("cover_attachment_internal_id", card.CoverID).Error
+}
+
+func (r *cardRepository) Delete(ctx context.Context, card *models.Card) ([]string, error) {
+	var files []string
+	err := conn(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		position, err := lockCardPosition(tx, card.ListID)
+		if err != nil {
+			return err
+		}
+
+		files, err = deleteCards(tx, []int64{card.InternalID})
+		if err != nil {
+			return err
+		}
+
+		position.CardOrder = removeID(position.CardOrder, card.PublicID)
+		return saveCardOrder(tx, position)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return files, nil
+}
+
+// pindahkan card ke list tujuan di index tertentu
+// card_order list asal & tujuan serta list_internal_id card diubah dalam satu transaksi
+// sourceVersion / targetVersion opsional, kalau diisi dan berbeda dengan db kembalikan ErrStaleVersion
+// card yang sudah dipindah request lain juga dianggap ErrStaleVersion walaupun version tidak dikirim
+func (r *cardRepository) Move(ctx context.Context, card *models.Card, targetListID int64, index int, sourceVersion, targetVersion *int64) (*models.CardPosition, *models.CardPosition, error) {
+	var source, target *models.CardPosition
+	err := conn(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		if card.ListID == targetListID {
+			position, err := lockCardPosition(tx, card.ListID)
+			if err != nil {
+				return err
+			}
+			source, target = position, position
+
+			if err := lockCardList(tx, card); err != nil {
+				return err
+			}
+			if err := checkVersion(sourceVersion, position.Version); err != nil {
+				return err
+			}
+			if err := checkVersion(targetVersion, position.Version); err != nil {
+				return err
+			}
+
+			position.CardOrder = insertAt(removeID(position.CardOrder, card.PublicID), index, card.PublicID)
+			return saveCardOrder(tx, position)
+		}
+
+		var err error
+		source, target, err = lockMovePositions(tx, card.ListID, targetListID)
+		if err != nil {
+			return err
+		}
+
+		if err := lockCardList(tx, card); err != nil {
+			return err
+		}
+		if err := checkVersion(sourceVersion, source.Version); err != nil {
+			return err
+		}
+		if err := checkVersion(targetVersion, target.Version); err != nil {
+			return err
+		}
+
+		source.CardOrder = removeID(source.CardOrder, card.PublicID)
+		target.CardOrder = insertAt(removeID(target.CardOrder, card.PublicID), index, card.PublicID)
+
+		if err := saveCardOrder(tx, source); err != nil {
+			return err
+		}
+		if err := saveCardOrder(tx, target); err != nil {
+			return err
+		}
+
+		if err := tx.Model(card).Update("list_internal_id", targetListID).Error; err != nil {
+			return err
+		}
+		card.ListID = targetListID
+		return nil
+	})
+	return source, target, err
+}
+
+// card_position dibuat saat list dibuat, tapi list lama mungkin belum punya jadi dibuat di sini
+func lockCardPosition(tx *gorm.DB, listID int64) (*models.CardPosition, error) {
+	var position models.CardPosition
+	err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("list_internal_id = ?", listID).
+		First(&position).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		position = models.CardPosition{
+			PublicID:  uuid.New(),
+			ListID:    listID,
+			CardOrder: types.UUIDArray{},
+		}
+		err = tx.Create(&position).Error
+	}
+	return &position, err
+}
+
+// card dibaca sebelum card_position di lock, jadi bisa saja sudah dipindah request lain
+// row card di lock lalu list_internal_id nya dicek ulang, kalau sudah berubah urutan yang dipakai sudah basi
+func lockCardList(tx *gorm.DB, card *models.Card) error {
+	var current models.Card
+	err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Select("internal_id", "list_internal_id").
+		First(&current, card.InternalID).Error
+	if err != nil {
+		return err
+	}
+	if current.ListID != card.ListID {
+		return ErrStaleVersion
+	}
+	return nil
+}
+
+// lock card_position list asal dan tujuan, selalu dari list id terkecil
+// supaya dua move yang berlawanan arah tidak deadlock
+func lockMovePositions(tx *gorm.DB, sourceListID, targetListID int64) (*models.CardPosition, *models.CardPosition, error) {
+	if sourceListID == targetListID {
+		position, err := lockCardPosition(tx, sourceListID)
+		return position, position, err
+	}
+
+	firstID, secondID := sourceListID, targetListID
+	if firstID > secondID {
+		firstID, secondID = secondID, firstID
+	}
+
+	first, err := lockCardPosition(tx, firstID)
+	if err != nil {
+		return nil, nil, err
+	}
+	second, err := lockCardPosition(tx, secondID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if first.ListID != sourceListID {
+		return second, first, nil
+	}
+	return first, second, nil
+}
+
+// setiap perubahan card_order menaikkan version, dipakai untuk optimistic concurrency
+func saveCardOrder(tx *gorm.DB, position *models.CardPosition) error {
+	err := tx.Model(position).Updates(map[string]interface{}{
+		"card_order": position.CardOrder,
+		"version":    gorm.Expr("version + 1"),
+	}).Error
+	if err != nil {
+		return err
+	}
+	position.Version++
+	return nil
+}
@@ -0,0 +1,74 @@
+package repositories
+
+import (
+	"github.com/odink789/project-management/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// hapus card beserta data yang bergantung pada card (assignee, label, comment, attachment)
+// cardIDs bisa berupa slice internal id atau subquery
+// file di storage tidak dihapus di sini, key file attachment dan thumbnail nya dikembalikan
+// supaya service bisa menghapus nya setelah transaksi commit
+func deleteCards(tx *gorm.DB, cardIDs interface{}) ([]string, error) {
+	files, err := deleteAttachments(tx, "card_internal_id IN (?)", cardIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Where("card_internal_id IN (?)", cardIDs).Delete(&models.CardAssignee{}).Error; err != nil {
+		return nil, err
+	}
+	if err := tx.Where("card_internal_id IN (?)", cardIDs).Delete(&models.Cardlabel{}).Error; err != nil {
+		return nil, err
+	}
+	commentIDs := tx.Model(&models.Comment{}).Select("internal_id").Where("card_internal_id IN (?)", cardIDs)
+	if err := tx.Where("comment_internal_id IN (?)", commentIDs).Delete(&models.CommentEdit{}).Error; err != nil {
+		return nil, err
+	}
+	if err := tx.Where("card_internal_id IN (?)", cardIDs).Delete(&models.Comment{}).Error; err != nil {
+		return nil, err
+	}
+	if err := tx.Where("internal_id IN (?)", cardIDs).Delete(&models.Card{}).Error; err != nil {
+		return nil, err
+	}
+	return files, nil
+}
+
+// hapus attachment yang cocok dengan query beserta thumbnail nya, key file di storage dikembalikan
+// row attachment di lock sebelum thumbnail dibaca, jadi SaveThumbnails yang sedang berjalan ditunggu dulu
+// dan thumbnail yang baru disimpan nya ikut terhapus
+func deleteAttachments(tx *gorm.DB, query string, args ...interface{}) ([]string, error) {
+	var attachments []models.CardAttachment
+	err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Select("internal_id", "file").
+		Where(query, args...).
+		Find(&attachments).Error
+	if err != nil || len(attachments) == 0 {
+		return nil, err
+	}
+
+	ids := make([]int64, len(attachments))
+	files := make([]string, len(attachments))
+	for i, attachment := range attachments {
+		ids[i] = attachment.InternalID
+		files[i] = attachment.File
+	}
+
+	var thumbnails []string
+	if err := tx.Model(&models.AttachmentThumbnail{}).Where("attachment_internal_id IN ?", ids).Pluck("file", &thumbnails).Error; err != nil {
+		return nil, err
+	}
+	if err := tx.Where("attachment_internal_id IN ?", ids).Delete(&models.AttachmentThumbnail{}).Error; err != nil {
+		return nil, err
+	}
+	if err := tx.Where("internal_id IN ?", ids).Delete(&models.CardAttachment{}).Error; err != nil {
+		return nil, err
+	}
+	return append(files, thumbnails...), nil
+}
+
+// subquery internal id card di dalam list tertentu
+func cardIDsInLists(tx *gorm.DB, listIDs interface{}) *gorm.DB {
+	return tx.Model(&models.Card{}).Select("internal_id").Where("list_internal_id IN (?)", listIDs)
+}
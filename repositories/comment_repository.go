@@ -0,0 +1,128 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/odink789/project-management/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var ErrCommentRemoved = errors.New("comment has been removed")
+
+type CommentRepository interface {
+	FindByCard(ctx context.Context, cardID int64, offset, limit int) ([]models.Comment, int64, error)
+	FindByPublicID(ctx context.Context, publicID string) (*models.Comment, error)
+	FindEdits(ctx context.Context, commentID int64) ([]models.CommentEdit, error)
+	Create(ctx context.Context, comment *models.Comment) error
+	Update(ctx context.Context, comment *models.Comment, editorID int64, message string) error
+	SoftDelete(ctx context.Context, comment *models.Comment, editorID int64) error
+}
+
+type commentRepository struct {
+	db *gorm.DB
+}
+
+func NewCommentRepository(db *gorm.DB) CommentRepository {
+	return &commentRepository{db}
+}
+
+// comment yang sudah dihapus tetap ikut supaya thread nya tidak bolong
+func (r *commentRepository) FindByCard(ctx context.Context, cardID int64, offset, limit int) ([]models.Comment, int64, error) {
+	var total int64
+	if err := conn(ctx, r.db).Model(&models.Comment{}).Where("card_internal_id = ?", cardID).Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var comments []models.Comment
+	err := conn(ctx, r.db).Where("card_internal_id = ?", cardID).
+		Order("created_at ASC, internal_id ASC").
+		Offset(offset).
+		Limit(limit).
+		Find(&comments).Error
+	return comments, total, err
+}
+
+func (r *commentRepository) FindByPublicID(ctx context.Context, publicID string) (*models.Comment, error) {
+	var comment models.Comment
+	err := conn(ctx, r.db).Where("public_id = ?", publicID).First(&comment).Error
+	return &comment, err
+}
+
+func (r *commentRepository) FindEdits(ctx context.Context, commentID int64) ([]models.CommentEdit, error) {
+	var edits []models.CommentEdit
+	err := conn(ctx, r.db).Preload("Editor").Where("comment_internal_id = ?", commentID).Order("edited_at ASC, internal_id ASC").Find(&edits).Error
+	return edits, err
+}
+
+func (r *commentRepository) Create(ctx context.Context, comment *models.Comment) error {
+	return conn(ctx, r.db).Create(comment).Error
+}
+
+// isi lama disimpan ke comment_edits sebelum comment di update
+func (r *commentRepository) Update(ctx context.Context, comment *models.Comment, editorID int64, message string) error {
+	now := time.Now()
+	err := conn(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		if err := lockCommentForEdit(tx, comment); err != nil {
+			return err
+		}
+		if err := saveCommentEdit(tx, comment, editorID, now); err != nil {
+			return err
+		}
+		return tx.Model(comment).Updates(map[string]interface{}{
+			"message":   message,
+			"edited_at": now,
+		}).Error
+	})
+	if err != nil {
+		return err
+	}
+	comment.Message = message
+	comment.EditedAt = &now
+	return nil
+}
+
+// isi comment dipindah ke comment_edits lalu dikosongkan, row nya tetap ada sebagai placeholder
+func (r *commentRepository) SoftDelete(ctx context.Context, comment *models.Comment, editorID int64) error {
+	now := time.Now()
+	err := conn(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		if err := lockCommentForEdit(tx, comment); err != nil {
+			return err
+		}
+		if err := saveCommentEdit(tx, comment, editorID, now); err != nil {
+			return err
+		}
+		return tx.Model(comment).Updates(map[string]interface{}{
+			"message":    "",
+			"deleted_at": now,
+		}).Error
+	})
+	if err != nil {
+		return err
+	}
+	comment.Message = ""
+	comment.DeletedAt = &now
+	return nil
+}
+
+// ambil ulang comment dengan lock supaya dua edit bersamaan tidak menyimpan isi lama yang sama
+func lockCommentForEdit(tx *gorm.DB, comment *models.Comment) error {
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(comment, comment.InternalID).Error; err != nil {
+		return err
+	}
+	if comment.IsDeleted() {
+		return ErrCommentRemoved
+	}
+	return nil
+}
+
+func saveCommentEdit(tx *gorm.DB, comment *models.Comment, editorID int64, editedAt time.Time) error {
+	return tx.Create(&models.CommentEdit{
+		CommentID: comment.InternalID,
+		Message:   comment.Message,
+		EditedBy:  editorID,
+		EditedAt:  editedAt,
+	}).Error
+}
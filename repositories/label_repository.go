@@ -0,0 +1,89 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/odink789/project-management/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type LabelRepository interface {
+	FindByBoard(ctx context.Context, boardID int64) ([]models.Label, error)
+	FindByPublicID(ctx context.Context, publicID string) (*models.Label, error)
+	FindByCard(ctx context.Context, cardID int64) ([]models.Label, error)
+	Create(ctx context.Context, label *models.Label) error
+	Update(ctx context.Context, label *models.Label) error
+	Delete(ctx context.Context, label *models.Label) error
+	Attach(ctx context.Context, cardID, labelID int64) error
+	Detach(ctx context.Context, cardID, labelID int64) error
+}
+
+type labelRepository struct {
+	db *gorm.DB
+}
+
+func NewLabelRepository(db *gorm.DB) LabelRepository {
+	return &labelRepository{db}
+}
+
+func (r *labelRepository) FindByBoard(ctx context.Context, boardID int64) ([]models.Label, error) {
+	var labels []models.Label
+	err := conn(ctx, r.db).Where("board_internal_id = ?", boardID).Order("created_at ASC").Find(&labels).Error
+	return labels, err
+}
+
+func (r *labelRepository) FindByPublicID(ctx context.Context, publicID string) (*models.Label, error) {
+	var label models.Label
+	err := conn(ctx, r.db).Where("public_id = ?", publicID).First(&label).Error
+	return &label, err
+}
+
+func (r *labelRepository) FindByCard(ctx context.Context, cardID int64) ([]models.Label, error) {
+	var labels []models.Label
+	err := conn(ctx, r.db).
+		Joins("JOIN cardlabels ON cardlabels.label_internal_id = labels.internal_id").
+		Where("cardlabels.card_internal_id = ?", cardID).
+		Order("labels.created_at ASC").
+		Find(&labels).Error
+	return labels, err
+}
+
+func (r *labelRepository) Create(ctx context.Context, label *models.Label) error {
+	return conn(ctx, r.db).Create(label).Error
+}
+
+func (r *labelRepository) Update(ctx context.Context, label *models.Label) error {
+	return conn(ctx, r.db).Model(label).
+		Select("name", "color").
+		Updates(label).Error
+}
+
+// label yang dihapus juga dilepas dari semua card
+func (r *labelRepository) Delete(ctx context.Context, label *models.Label) error {
+	return conn(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("label_internal_id = ?", label.InternalID).Delete(&models.Cardlabel{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(label).Error
+	})
+}
+
+// attach label yang sudah terpasang tidak dianggap error
+func (r *labelRepository) Attach(ctx context.Context, cardID, labelID int64) error {
+	return conn(ctx, r.db).Clauses(clause.OnConflict{DoNothing: true}).
+		Create(&models.Cardlabel{CardID: cardID, LabelID: labelID}).Error
+}
+
+func (r *labelRepository) Detach(ctx context.Context, cardID, labelID int64) error {
+	result := conn(ctx, r.db).
+		Where("card_internal_id = ? AND label_internal_id = ?", cardID, labelID).
+		Delete(&models.Cardlabel{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
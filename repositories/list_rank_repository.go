@@ -0,0 +1,153 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/odink789/project-management/models"
+	"github.com/odink789/project-management/models/types"
+	"gorm.io/gorm"
+)
+
+// listRankRepository mengurutkan list memakai kolom rank (ORDERING_MODE=rank)
+// list_order di list_positions tidak lagi ditulis, row nya hanya dipakai untuk lock dan version
+type listRankRepository struct {
+	listRepository
+}
+
+func NewListRankRepository(db *gorm.DB) ListRepository {
+	return &listRankRepository{listRepository{db}}
+}
+
+func (r *listRankRepository) FindByBoard(ctx context.Context, boardID int64) ([]models.List, error) {
+	var lists []models.List
+	err := conn(ctx, r.db).Where("board_internal_id = ?", boardID).Order(rankOrder).Find(&lists).Error
+	return lists, err
+}
+
+// list_order tidak ditulis di mode rank, position hanya membawa version
+func (r *listRankRepository) FindPosition(ctx context.Context, boardID int64) (*models.ListPosition, error) {
+	position, err := r.listRepository.FindPosition(ctx, boardID)
+	if err != nil {
+		return nil, err
+	}
+	position.ListOrder = nil
+	return position, nil
+}
+
+// list cukup dibaca sekali, list_order diisi dari hasil nya
+func (r *listRankRepository) FindOrdered(ctx context.Context, boardID int64) ([]models.List, *models.ListPosition, error) {
+	lists, err := r.FindByBoard(ctx, boardID)
+	if err != nil {
+		return nil, nil, err
+	}
+	position, err := r.listRepository.FindPosition(ctx, boardID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	position.ListOrder = make(types.UUIDArray, len(lists))
+	for i, list := range lists {
+		position.ListOrder[i] = list.PublicID
+	}
+	return lists, position, nil
+}
+
+func (r *listRankRepository) Create(ctx context.Context, list *models.List, index int) error {
+	return conn(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		save := func(rank string) error {
+			list.Rank = rank
+			if list.InternalID == 0 {
+				return tx.Create(list).Error
+			}
+			return tx.Model(list).Update("rank", rank).Error
+		}
+		lock := func() error {
+			position, err := lockListPosition(tx, list.BoardInternalID)
+			if err != nil {
+				return err
+			}
+			return bumpListVersion(tx, position)
+		}
+
+		scope := rankScope{model: &models.List{}, column: "board_internal_id", parentID: list.BoardInternalID}
+		if _, err := placeRank(tx, scope, &list.InternalID, index, save, lock); err != nil {
+			return err
+		}
+
+		//card_position tetap dibuat untuk lock dan version urutan card
+		cardPosition := models.CardPosition{
+			PublicID:  uuid.New(),
+			ListID:    list.InternalID,
+			CardOrder: types.UUIDArray{},
+		}
+		return tx.Create(&cardPosition).Error
+	})
+}
+
+func (r *listRankRepository) Delete(ctx context.Context, list *models.List) ([]string, error) {
+	var files []string
+	err := conn(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		position, err := lockListPosition(tx, list.BoardInternalID)
+		if err != nil {
+			return err
+		}
+
+		files, err = deleteCards(tx, cardIDsInLists(tx, []int64{list.InternalID}))
+		if err != nil {
+			return err
+		}
+		if err := tx.Where("list_internal_id = ?", list.InternalID).Delete(&models.CardPosition{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Delete(list).Error; err != nil {
+			return err
+		}
+		return bumpListVersion(tx, position)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return files, nil
+}
+
+// hanya list yang posisi relatif nya berubah yang ditulis ulang rank nya (lihat reorderRanks)
+func (r *listRankRepository) Reorder(ctx context.Context, boardID int64, order types.UUIDArray, expectedVersion *int64) (*models.ListPosition, error) {
+	var position *models.ListPosition
+	err := conn(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		var err error
+		position, err = lockListPosition(tx, boardID)
+		if err != nil {
+			return err
+		}
+
+		if err := checkVersion(expectedVersion, position.Version); err != nil {
+			return err
+		}
+
+		scope := rankScope{model: &models.List{}, column: "board_internal_id", parentID: boardID}
+		rows, err := loadRanked(tx, scope, 0)
+		if err != nil {
+			return err
+		}
+		if !isPermutation(order, rankedOrder(rows)) {
+			return ErrInvalidListOrder
+		}
+
+		if err := applyRanks(tx, &models.List{}, reorderRanks(rows, order)); err != nil {
+			return err
+		}
+
+		position.ListOrder = order
+		return bumpListVersion(tx, position)
+	})
+	return position, err
+}
+
+func bumpListVersion(tx *gorm.DB, position *models.ListPosition) error {
+	if err := tx.Model(position).Update("version", gorm.Expr("version + 1")).Error; err != nil {
+		return err
+	}
+	position.Version++
+	return nil
+}
@@ -0,0 +1,181 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/odink789/project-management/models"
+	"github.com/odink789/project-management/models/types"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var ErrInvalidListOrder = errors.New("list order must contain every list of the board exactly once")
+
+type ListRepository interface {
+	FindByBoard(ctx context.Context, boardID int64) ([]models.List, error)
+	FindByPublicID(ctx context.Context, publicID string) (*models.List, error)
+	FindByID(ctx context.Context, id int64) (*models.List, error)
+	FindPosition(ctx context.Context, boardID int64) (*models.ListPosition, error)
+	FindOrdered(ctx context.Context, boardID int64) ([]models.List, *models.ListPosition, error)
+	Create(ctx context.Context, list *models.List, index int) error
+	Update(ctx context.Context, list *models.List) error
+	Delete(ctx context.Context, list *models.List) ([]string, error)
+	Reorder(ctx context.Context, boardID int64, order types.UUIDArray, expectedVersion *int64) (*models.ListPosition, error)
+}
+
+type listRepository struct {
+	db *gorm.DB
+}
+
+func NewListRepository(db *gorm.DB) ListRepository {
+	return &listRepository{db}
+}
+
+func (r *listRepository) FindByBoard(ctx context.Context, boardID int64) ([]models.List, error) {
+	var lists []models.List
+	err := conn(ctx, r.db).Where("board_internal_id = ?", boardID).Order("created_at ASC").Find(&lists).Error
+	return lists, err
+}
+
+func (r *listRepository) FindByPublicID(ctx context.Context, publicID string) (*models.List, error) {
+	var list models.List
+	err := conn(ctx, r.db).Where("public_id = ?", publicID).First(&list).Error
+	return &list, err
+}
+
+func (r *listRepository) FindByID(ctx context.Context, id int64) (*models.List, error) {
+	var list models.List
+	err := conn(ctx, r.db).First(&list, id).Error
+	return &list, err
+}
+
+func (r *listRepository) FindPosition(ctx context.Context, boardID int64) (*models.ListPosition, error) {
+	var position models.ListPosition
+	err := conn(ctx, r.db).Where("board_internal_id = ?", boardID).First(&position).Error
+	return &position, err
+}
+
+// list di board sesuai urutan list_order, position nya ikut dikembalikan untuk version
+func (r *listRepository) FindOrdered(ctx context.Context, boardID int64) ([]models.List, *models.ListPosition, error) {
+	lists, err := r.FindByBoard(ctx, boardID)
+	if err != nil {
+		return nil, nil, err
+	}
+	position, err := r.FindPosition(ctx, boardID)
+	if err != nil {
+		return nil, nil, err
+	}
+	return SortByOrder(lists, position.ListOrder, func(l models.List) uuid.UUID { return l.PublicID }), position, nil
+}
+
+// simpan list dan sisipkan public id nya ke list_order di index tertentu (index < 0 berarti paling akhir)
+func (r *listRepository) Create(ctx context.Context, list *models.List, index int) error {
+	return conn(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(list).Error; err != nil {
+			return err
+		}
+
+		position, err := lockListPosition(tx, list.BoardInternalID)
+		if err != nil {
+			return err
+		}
+
+		position.ListOrder = insertAt(position.ListOrder, index, list.PublicID)
+		if err := saveListOrder(tx, position); err != nil {
+			return err
+		}
+
+		//setiap list punya satu card_position untuk urutan card di dalam nya
+		cardPosition := models.CardPosition{
+			PublicID:  uuid.New(),
+			ListID:    list.InternalID,
+			CardOrder: types.UUIDArray{},
+		}
+		return tx.Create(&cardPosition).Error
+	})
+}
+
+func (r *listRepository) Update(ctx context.Context, list *models.List) error {
+	return conn(ctx, r.db).Model(list).Update("tittle", list.Tittle).Error
+}
+
+// hapus list beserta card di dalam nya, lalu buang public id nya dari list_order
+func (r *listRepository) Delete(ctx context.Context, list *models.List) ([]string, error) {
+	var files []string
+	err := conn(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		position, err := lockListPosition(tx, list.BoardInternalID)
+		if err != nil {
+			return err
+		}
+
+		files, err = deleteCards(tx, cardIDsInLists(tx, []int64{list.InternalID}))
+		if err != nil {
+			return err
+		}
+		if err := tx.Where("list_internal_id = ?", list.InternalID).Delete(&models.CardPosition{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Delete(list).Error; err != nil {
+			return err
+		}
+
+		position.ListOrder = removeID(position.ListOrder, list.PublicID)
+		return saveListOrder(tx, position)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return files, nil
+}
+
+// order baru harus berisi semua list di board, tidak boleh ada yang hilang atau dobel
+// kalau expectedVersion diisi dan tidak sama dengan version di db, kembalikan ErrStaleVersion
+func (r *listRepository) Reorder(ctx context.Context, boardID int64, order types.UUIDArray, expectedVersion *int64) (*models.ListPosition, error) {
+	var position *models.ListPosition
+	err := conn(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		var err error
+		position, err = lockListPosition(tx, boardID)
+		if err != nil {
+			return err
+		}
+
+		if err := checkVersion(expectedVersion, position.Version); err != nil {
+			return err
+		}
+
+		var existing []uuid.UUID
+		if err := tx.Model(&models.List{}).Where("board_internal_id = ?", boardID).Pluck("public_id", &existing).Error; err != nil {
+			return err
+		}
+		if !isPermutation(order, existing) {
+			return ErrInvalidListOrder
+		}
+
+		position.ListOrder = order
+		return saveListOrder(tx, position)
+	})
+	return position, err
+}
+
+func lockListPosition(tx *gorm.DB, boardID int64) (*models.ListPosition, error) {
+	var position models.ListPosition
+	err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("board_internal_id = ?", boardID).
+		First(&position).Error
+	return &position, err
+}
+
+// setiap perubahan list_order menaikkan version, dipakai untuk optimistic concurrency
+func saveListOrder(tx *gorm.DB, position *models.ListPosition) error {
+	err := tx.Model(position).Updates(map[string]interface{}{
+		"list_order": position.ListOrder,
+		"version":    gorm.Expr("version + 1"),
+	}).Error
+	if err != nil {
+		return err
+	}
+	position.Version++
+	return nil
+}
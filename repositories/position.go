@@ -0,0 +1,76 @@
+package repositories
+
+import (
+	"errors"
+	"slices"
+
+	"github.com/google/uuid"
+	"github.com/odink789/project-management/models/types"
+)
+
+//helper untuk memanipulasi list_order / card_order
+
+// ErrStaleVersion dikembalikan kalau version yang dikirim client sudah tidak sama dengan yang ada di db
+var ErrStaleVersion = errors.New("order version is stale")
+
+func checkVersion(expected *int64, current int64) error {
+	if expected != nil && *expected != current {
+		return ErrStaleVersion
+	}
+	return nil
+}
+
+func insertAt(order types.UUIDArray, index int, id uuid.UUID) types.UUIDArray {
+	if index < 0 || index > len(order) {
+		index = len(order)
+	}
+	return slices.Insert(order, index, id)
+}
+
+func removeID(order types.UUIDArray, id uuid.UUID) types.UUIDArray {
+	return slices.DeleteFunc(order, func(existing uuid.UUID) bool {
+		return existing == id
+	})
+}
+
+func isPermutation(order []uuid.UUID, existing []uuid.UUID) bool {
+	if len(order) != len(existing) {
+		return false
+	}
+
+	seen := make(map[uuid.UUID]bool, len(existing))
+	for _, id := range existing {
+		seen[id] = false
+	}
+	for _, id := range order {
+		used, ok := seen[id]
+		if !ok || used {
+			return false
+		}
+		seen[id] = true
+	}
+	return true
+}
+
+// SortByOrder mengurutkan items sesuai order, item yang tidak ada di order ditaruh paling akhir
+func SortByOrder[T any](items []T, order []uuid.UUID, id func(T) uuid.UUID) []T {
+	index := make(map[uuid.UUID]int, len(order))
+	for i, publicID := range order {
+		index[publicID] = i
+	}
+
+	sorted := make([]T, 0, len(items))
+	var rest []T
+	for _, item := range items {
+		if _, ok := index[id(item)]; ok {
+			sorted = append(sorted, item)
+		} else {
+			rest = append(rest, item)
+		}
+	}
+
+	slices.SortStableFunc(sorted, func(a, b T) int {
+		return index[id(a)] - index[id(b)]
+	})
+	return append(sorted, rest...)
+}
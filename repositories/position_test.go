@@ -0,0 +1,112 @@
+package repositories
+
+import (
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/odink789/project-management/models"
+	"github.com/odink789/project-management/models/types"
+)
+
+func TestInsertAt(t *testing.T) {
+	a, b, c := uuid.New(), uuid.New(), uuid.New()
+
+	tests := []struct {
+		name  string
+		index int
+		want  types.UUIDArray
+	}{
+		{name: "front", index: 0, want: types.UUIDArray{c, a, b}},
+		{name: "middle", index: 1, want: types.UUIDArray{a, c, b}},
+		{name: "end", index: 2, want: types.UUIDArray{a, b, c}},
+		{name: "negative appends", index: -1, want: types.UUIDArray{a, b, c}},
+		{name: "out of range appends", index: 99, want: types.UUIDArray{a, b, c}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := insertAt(types.UUIDArray{a, b}, tt.index, c)
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("insertAt() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRemoveID(t *testing.T) {
+	a, b, c := uuid.New(), uuid.New(), uuid.New()
+
+	got := removeID(types.UUIDArray{a, b, c}, b)
+	if !slices.Equal(got, types.UUIDArray{a, c}) {
+		t.Errorf("removeID() = %v, want %v", got, types.UUIDArray{a, c})
+	}
+
+	got = removeID(types.UUIDArray{a, c}, b)
+	if !slices.Equal(got, types.UUIDArray{a, c}) {
+		t.Errorf("removeID() with missing id = %v, want unchanged", got)
+	}
+}
+
+func TestIsPermutation(t *testing.T) {
+	a, b, c := uuid.New(), uuid.New(), uuid.New()
+	existing := []uuid.UUID{a, b, c}
+
+	tests := []struct {
+		name  string
+		order []uuid.UUID
+		want  bool
+	}{
+		{name: "same order", order: []uuid.UUID{a, b, c}, want: true},
+		{name: "reordered", order: []uuid.UUID{c, a, b}, want: true},
+		{name: "missing id", order: []uuid.UUID{a, b}, want: false},
+		{name: "duplicate id", order: []uuid.UUID{a, a, b}, want: false},
+		{name: "unknown id", order: []uuid.UUID{a, b, uuid.New()}, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isPermutation(tt.order, existing); got != tt.want {
+				t.Errorf("isPermutation() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCheckVersion(t *testing.T) {
+	current := int64(3)
+	stale := int64(2)
+
+	if err := checkVersion(nil, current); err != nil {
+		t.Errorf("nil expected version should skip the check, got %v", err)
+	}
+	if err := checkVersion(&current, current); err != nil {
+		t.Errorf("matching version should pass, got %v", err)
+	}
+	if err := checkVersion(&stale, current); !errors.Is(err, ErrStaleVersion) {
+		t.Errorf("stale version should return ErrStaleVersion, got %v", err)
+	}
+}
+
+func TestSortByOrder(t *testing.T) {
+	a, b, c, orphan := uuid.New(), uuid.New(), uuid.New(), uuid.New()
+	lists := []models.List{
+		{PublicID: orphan, Tittle: "orphan"},
+		{PublicID: a, Tittle: "a"},
+		{PublicID: b, Tittle: "b"},
+		{PublicID: c, Tittle: "c"},
+	}
+
+	sorted := SortByOrder(lists, []uuid.UUID{c, a, b}, func(l models.List) uuid.UUID { return l.PublicID })
+
+	want := []string{"c", "a", "b", "orphan"}
+	if len(sorted) != len(want) {
+		t.Fatalf("len = %d, want %d", len(sorted), len(want))
+	}
+	for i, title := range want {
+		if sorted[i].Tittle != title {
+			t.Errorf("index %d = %s, want %s", i, sorted[i].Tittle, title)
+		}
+	}
+}
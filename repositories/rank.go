@@ -0,0 +1,321 @@
+package repositories
+
+import (
+	"github.com/google/uuid"
+	"github.com/odink789/project-management/models/types"
+	"github.com/odink789/project-management/utils"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+//helper untuk ORDERING_MODE=rank, dipakai oleh card & list rank repository
+//kolom rank di postgres memakai COLLATE "C" jadi ORDER BY rank sama dengan perbandingan byte per byte
+
+// urutan sibling, public_id dipakai kalau ada rank yang sama
+const rankOrder = "rank ASC, public_id ASC"
+
+type rankedRow struct {
+	InternalID int64
+	PublicID   uuid.UUID
+	Rank       string
+}
+
+// rankScope menunjuk sibling yang diurutkan bersama : card di satu list atau list di satu board
+type rankScope struct {
+	model    interface{}
+	column   string
+	parentID int64
+}
+
+func (s rankScope) siblings(tx *gorm.DB, excludeID int64) *gorm.DB {
+	return tx.Model(s.model).
+		Select("internal_id", "public_id", "rank").
+		Where(s.column+" = ? AND internal_id <> ?", s.parentID, excludeID)
+}
+
+// loadRanked mengambil seluruh sibling terurut berdasarkan rank, hanya dipakai saat rebalance / reorder seluruh list
+func loadRanked(tx *gorm.DB, scope rankScope, excludeID int64) ([]rankedRow, error) {
+	var rows []rankedRow
+	err := scope.siblings(tx, excludeID).Order(rankOrder).Scan(&rows).Error
+	return rows, err
+}
+
+// rankGap adalah dua tetangga di sekitar posisi item, nil berarti item ada di awal / akhir
+type rankGap struct {
+	before, after *rankedRow
+}
+
+// findGap hanya membaca tetangga di sekitar index (index < 0 berarti paling akhir)
+// memakai index (parent, rank) jadi tidak perlu membaca seluruh sibling
+func findGap(tx *gorm.DB, scope rankScope, excludeID int64, index int) (rankGap, error) {
+	var rows []rankedRow
+	if index >= 0 {
+		query := scope.siblings(tx, excludeID).Order(rankOrder)
+		if index == 0 {
+			query = query.Limit(1)
+		} else {
+			query = query.Offset(index - 1).Limit(2)
+		}
+		if err := query.Scan(&rows).Error; err != nil {
+			return rankGap{}, err
+		}
+
+		if index == 0 {
+			if len(rows) == 0 {
+				return rankGap{}, nil
+			}
+			return rankGap{after: &rows[0]}, nil
+		}
+		switch len(rows) {
+		case 2:
+			return rankGap{before: &rows[0], after: &rows[1]}, nil
+		case 1:
+			return rankGap{before: &rows[0]}, nil
+		}
+		//index melewati jumlah sibling, sama dengan paling akhir
+	}
+
+	err := scope.siblings(tx, excludeID).Order("rank DESC, public_id DESC").Limit(1).Scan(&rows).Error
+	if err != nil || len(rows) == 0 {
+		return rankGap{}, err
+	}
+	return rankGap{before: &rows[0]}, nil
+}
+
+// rank di antara dua tetangga, false kalau tidak ada ruang atau rank nya sudah terlalu panjang
+// tetangga dengan rank kosong berarti data lama yang belum di backfill, dianggap tidak ada ruang
+func (g rankGap) rank() (string, bool) {
+	before, after := "", ""
+	if g.before != nil {
+		if g.before.Rank == "" {
+			return "", false
+		}
+		before = g.before.Rank
+	}
+	if g.after != nil {
+		if g.after.Rank == "" {
+			return "", false
+		}
+		after = g.after.Rank
+	}
+
+	rank, err := utils.RankBetween(before, after)
+	if err != nil || len(rank) > utils.MaxRankLength {
+		return "", false
+	}
+	return rank, true
+}
+
+func (g rankGap) equal(other rankGap) bool {
+	return sameRow(g.before, other.before) && sameRow(g.after, other.after)
+}
+
+func sameRow(a, b *rankedRow) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
+
+// placeRank menaruh item di index tertentu dan mengembalikan rank nya
+// rank dihitung dari dua tetangga lalu langsung disimpan (save), baru setelah itu lock() dipanggil
+// untuk lock position parent dan menaikkan version, jadi lock hanya dipegang sampai commit
+// setelah lock tetangga dibaca ulang, kalau sudah berubah karena request lain rank dihitung dan disimpan ulang
+// rebalance seluruh sibling hanya dilakukan kalau tidak ada ruang di antara tetangga dan selalu di bawah lock
+// id menunjuk internal id item, untuk item baru nilai nya baru terisi setelah save pertama
+func placeRank(tx *gorm.DB, scope rankScope, id *int64, index int, save func(rank string) error, lock func() error) (string, error) {
+	gap, err := findGap(tx, scope, *id, index)
+	if err != nil {
+		return "", err
+	}
+	rank, saved := gap.rank()
+	if saved {
+		if err := save(rank); err != nil {
+			return "", err
+		}
+	}
+
+	if err := lock(); err != nil {
+		return "", err
+	}
+
+	current, err := findGap(tx, scope, *id, index)
+	if err != nil {
+		return "", err
+	}
+	if saved && current.equal(gap) {
+		return rank, nil
+	}
+
+	rank, ok := current.rank()
+	if !ok {
+		if rank, err = rebalanceRanked(tx, scope, *id, index); err != nil {
+			return "", err
+		}
+	}
+	return rank, save(rank)
+}
+
+// rebalanceRanked menulis ulang rank seluruh sibling dengan jarak yang sama dan mengembalikan rank untuk index
+// item lain yang sedang disimpan sebelum lock memegang row nya, row itu tidak ditunggu (SKIP LOCKED)
+// supaya tidak deadlock, kalau ada yang terlewat rebalance dibatalkan dengan ErrStaleVersion
+func rebalanceRanked(tx *gorm.DB, scope rankScope, excludeID int64, index int) (string, error) {
+	var rows []rankedRow
+	err := scope.siblings(tx, excludeID).
+		Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+		Order(rankOrder).
+		Scan(&rows).Error
+	if err != nil {
+		return "", err
+	}
+
+	var total int64
+	err = tx.Model(scope.model).
+		Where(scope.column+" = ? AND internal_id <> ?", scope.parentID, excludeID).
+		Count(&total).Error
+	if err != nil {
+		return "", err
+	}
+	if total != int64(len(rows)) {
+		return "", ErrStaleVersion
+	}
+
+	rank, rebalanced := placeRanked(rows, index)
+	if err := applyRanks(tx, scope.model, rebalanced); err != nil {
+		return "", err
+	}
+	return rank, nil
+}
+
+// placeRanked menghitung rank untuk item baru di index tertentu (index < 0 berarti paling akhir)
+// kalau tidak ada ruang atau rank sudah terlalu panjang, semua sibling di rebalance
+// dan rank baru untuk sibling dikembalikan di map rebalanced
+func placeRanked(rows []rankedRow, index int) (string, map[int64]string) {
+	if index < 0 || index > len(rows) {
+		index = len(rows)
+	}
+
+	var gap rankGap
+	if index > 0 {
+		gap.before = &rows[index-1]
+	}
+	if index < len(rows) {
+		gap.after = &rows[index]
+	}
+	if rank, ok := gap.rank(); ok {
+		return rank, nil
+	}
+
+	ranks := utils.RankSequence(len(rows) + 1)
+	rebalanced := make(map[int64]string, len(rows))
+	for i, row := range rows {
+		position := i
+		if i >= index {
+			position++
+		}
+		rebalanced[row.InternalID] = ranks[position]
+	}
+	return ranks[index], rebalanced
+}
+
+// reorderRanks mengembalikan rank baru hanya untuk item yang posisi nya berubah
+// item yang urutan relatif nya tetap (longest increasing subsequence dari urutan lama) tidak disentuh,
+// item lain diberi rank di antara tetangga nya. kalau ada yang tidak muat semua item di rebalance
+// rows harus terurut berdasarkan rank dan order harus permutasi dari rows
+func reorderRanks(rows []rankedRow, order []uuid.UUID) map[int64]string {
+	indexByID := make(map[uuid.UUID]int, len(rows))
+	for i, row := range rows {
+		indexByID[row.PublicID] = i
+	}
+	sequence := make([]int, len(order))
+	for i, publicID := range order {
+		sequence[i] = indexByID[publicID]
+	}
+	kept := longestIncreasing(sequence)
+
+	ranks := make(map[int64]string)
+	var before *rankedRow
+	for i, current := range sequence {
+		if kept[i] {
+			before = &rows[current]
+			continue
+		}
+
+		gap := rankGap{before: before}
+		for j := i + 1; j < len(sequence); j++ {
+			if kept[j] {
+				gap.after = &rows[sequence[j]]
+				break
+			}
+		}
+		rank, ok := gap.rank()
+		if !ok {
+			return rebalanceOrder(rows, sequence)
+		}
+		ranks[rows[current].InternalID] = rank
+		before = &rankedRow{Rank: rank}
+	}
+	return ranks
+}
+
+func rebalanceOrder(rows []rankedRow, sequence []int) map[int64]string {
+	sequenceRanks := utils.RankSequence(len(sequence))
+	ranks := make(map[int64]string, len(sequence))
+	for i, current := range sequence {
+		if rows[current].Rank != sequenceRanks[i] {
+			ranks[rows[current].InternalID] = sequenceRanks[i]
+		}
+	}
+	return ranks
+}
+
+// longestIncreasing menandai index yang termasuk longest increasing subsequence (patience sorting)
+func longestIncreasing(values []int) []bool {
+	tails := make([]int, 0, len(values))
+	previous := make([]int, len(values))
+	for i, value := range values {
+		low, high := 0, len(tails)
+		for low < high {
+			mid := (low + high) / 2
+			if values[tails[mid]] < value {
+				low = mid + 1
+			} else {
+				high = mid
+			}
+		}
+		previous[i] = -1
+		if low > 0 {
+			previous[i] = tails[low-1]
+		}
+		if low == len(tails) {
+			tails = append(tails, i)
+		} else {
+			tails[low] = i
+		}
+	}
+
+	kept := make([]bool, len(values))
+	if len(tails) > 0 {
+		for i := tails[len(tails)-1]; i >= 0; i = previous[i] {
+			kept[i] = true
+		}
+	}
+	return kept
+}
+
+func applyRanks(tx *gorm.DB, model interface{}, ranks map[int64]string) error {
+	for id, rank := range ranks {
+		if err := tx.Model(model).Where("internal_id = ?", id).Update("rank", rank).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func rankedOrder(rows []rankedRow) types.UUIDArray {
+	order := make(types.UUIDArray, 0, len(rows))
+	for _, row := range rows {
+		order = append(order, row.PublicID)
+	}
+	return order
+}
@@ -0,0 +1,98 @@
+package repositories
+
+import (
+	"sort"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func rankedRows(ranks ...string) []rankedRow {
+	rows := make([]rankedRow, len(ranks))
+	for i, rank := range ranks {
+		rows[i] = rankedRow{InternalID: int64(i + 1), PublicID: uuid.New(), Rank: rank}
+	}
+	return rows
+}
+
+func TestPlaceRanked(t *testing.T) {
+	rows := rankedRows("A", "M", "Z")
+
+	tests := []struct {
+		name   string
+		index  int
+		before string
+		after  string
+	}{
+		{name: "front", index: 0, before: "", after: "A"},
+		{name: "middle", index: 2, before: "M", after: "Z"},
+		{name: "end", index: 3, before: "Z", after: ""},
+		{name: "negative appends", index: -1, before: "Z", after: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rank, rebalanced := placeRanked(rows, tt.index)
+			if rebalanced != nil {
+				t.Fatalf("unexpected rebalance %v", rebalanced)
+			}
+			if tt.before != "" && rank <= tt.before {
+				t.Errorf("rank %q should be after %q", rank, tt.before)
+			}
+			if tt.after != "" && rank >= tt.after {
+				t.Errorf("rank %q should be before %q", rank, tt.after)
+			}
+		})
+	}
+}
+
+func TestPlaceRanked_RebalanceOnBrokenRanks(t *testing.T) {
+	//data lama sebelum backfill, semua rank masih kosong
+	rows := rankedRows("", "", "")
+
+	rank, rebalanced := placeRanked(rows, 1)
+	if len(rebalanced) != len(rows) {
+		t.Fatalf("rebalanced = %d rows, want %d", len(rebalanced), len(rows))
+	}
+
+	got := []string{rebalanced[1], rank, rebalanced[2], rebalanced[3]}
+	if !sort.StringsAreSorted(got) {
+		t.Errorf("ranks after rebalance are not sorted: %v", got)
+	}
+}
+
+func TestReorderRanks(t *testing.T) {
+	rows := rankedRows("A", "G", "M", "T", "Z")
+	ids := make([]uuid.UUID, len(rows))
+	for i, row := range rows {
+		ids[i] = row.PublicID
+	}
+
+	//list terakhir dipindah ke depan, hanya list itu yang rank nya berubah
+	order := []uuid.UUID{ids[4], ids[0], ids[1], ids[2], ids[3]}
+	ranks := reorderRanks(rows, order)
+	if len(ranks) != 1 {
+		t.Fatalf("reorderRanks() changed %d rows, want 1: %v", len(ranks), ranks)
+	}
+	if rank := ranks[rows[4].InternalID]; rank == "" || rank >= "A" {
+		t.Errorf("moved rank = %q, want before %q", rank, "A")
+	}
+
+	if ranks := reorderRanks(rows, ids); len(ranks) != 0 {
+		t.Errorf("reorderRanks() with same order changed %v", ranks)
+	}
+}
+
+func TestReorderRanks_RebalanceOnBrokenRanks(t *testing.T) {
+	rows := rankedRows("", "", "")
+	order := []uuid.UUID{rows[2].PublicID, rows[0].PublicID, rows[1].PublicID}
+
+	ranks := reorderRanks(rows, order)
+	if len(ranks) != len(rows) {
+		t.Fatalf("reorderRanks() changed %d rows, want %d", len(ranks), len(rows))
+	}
+	got := []string{ranks[3], ranks[1], ranks[2]}
+	if !sort.StringsAreSorted(got) {
+		t.Errorf("ranks after rebalance are not sorted: %v", got)
+	}
+}
@@ -0,0 +1,78 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/odink789/project-management/models"
+	"gorm.io/gorm"
+)
+
+var ErrRefreshTokenRevoked = errors.New("refresh token already revoked")
+
+type RefreshTokenRepository interface {
+	Create(ctx context.Context, token *models.RefreshToken) error
+	FindByHash(ctx context.Context, hash string) (*models.RefreshToken, error)
+	FindActiveByUser(ctx context.Context, userID int64) ([]models.RefreshToken, error)
+	Rotate(ctx context.Context, old *models.RefreshToken, next *models.RefreshToken) error
+	RevokeFamily(ctx context.Context, familyID uuid.UUID) error
+	RevokeAllByUser(ctx context.Context, userID int64) error
+}
+
+type refreshTokenRepository struct {
+	db *gorm.DB
+}
+
+func NewRefreshTokenRepository(db *gorm.DB) RefreshTokenRepository {
+	return &refreshTokenRepository{db}
+}
+
+func (r *refreshTokenRepository) Create(ctx context.Context, token *models.RefreshToken) error {
+	return conn(ctx, r.db).Create(token).Error
+}
+
+func (r *refreshTokenRepository) FindByHash(ctx context.Context, hash string) (*models.RefreshToken, error) {
+	var token models.RefreshToken
+	err := conn(ctx, r.db).Where("token_hash = ?", hash).First(&token).Error
+	return &token, err
+}
+
+func (r *refreshTokenRepository) FindActiveByUser(ctx context.Context, userID int64) ([]models.RefreshToken, error) {
+	var tokens []models.RefreshToken
+	err := conn(ctx, r.db).
+		Where("user_internal_id = ? AND revoked_at IS NULL AND expires_at > ?", userID, time.Now()).
+		Order("created_at DESC").
+		Find(&tokens).Error
+	return tokens, err
+}
+
+// revoke token lama dan simpan token baru dalam satu transaksi
+// kalau token lama ternyata sudah di revoke duluan (request paralel), kembalikan ErrRefreshTokenRevoked
+func (r *refreshTokenRepository) Rotate(ctx context.Context, old *models.RefreshToken, next *models.RefreshToken) error {
+	return conn(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		res := tx.Model(&models.RefreshToken{}).
+			Where("internal_id = ? AND revoked_at IS NULL", old.InternalID).
+			Update("revoked_at", time.Now())
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 0 {
+			return ErrRefreshTokenRevoked
+		}
+		return tx.Create(next).Error
+	})
+}
+
+func (r *refreshTokenRepository) RevokeFamily(ctx context.Context, familyID uuid.UUID) error {
+	return conn(ctx, r.db).Model(&models.RefreshToken{}).
+		Where("family_id = ? AND revoked_at IS NULL", familyID).
+		Update("revoked_at", time.Now()).Error
+}
+
+func (r *refreshTokenRepository) RevokeAllByUser(ctx context.Context, userID int64) error {
+	return conn(ctx, r.db).Model(&models.RefreshToken{}).
+		Where("user_internal_id = ? AND revoked_at IS NULL", userID).
+		Update("revoked_at", time.Now()).Error
+}
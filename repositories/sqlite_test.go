@@ -0,0 +1,410 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/odink789/project-management/config"
+	"github.com/odink789/project-management/database/migrations"
+	"github.com/odink789/project-management/models"
+	"github.com/odink789/project-management/models/types"
+	"gorm.io/gorm"
+)
+
+// setupTestDB membuka sqlite in memory yang sudah di migrasi
+func setupTestDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	db, err := config.OpenDB(&config.Config{DBDriver: config.DBDriverSQLite, DBPath: ":memory:"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := migrations.Up(db); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Cleanup(func() {
+		if sqlDB, err := db.DB(); err == nil {
+			sqlDB.Close()
+		}
+	})
+	return db
+}
+
+func createTestUser(t *testing.T, db *gorm.DB, email string) *models.User {
+	t.Helper()
+	user := &models.User{PublicID: uuid.New(), Name: email, Email: email, Password: "hash", Role: models.RoleUser}
+	if err := NewUserRepository(db).Create(context.Background(), user); err != nil {
+		t.Fatal(err)
+	}
+	return user
+}
+
+func createTestBoard(t *testing.T, db *gorm.DB, owner *models.User) *models.Board {
+	t.Helper()
+	board := &models.Board{PublicID: uuid.New(), Title: "Board", OwnerID: owner.InternalID, OwnerPublicID: owner.PublicID, CreatedAt: time.Now()}
+	if err := NewBoardRepository(db).Create(context.Background(), board); err != nil {
+		t.Fatal(err)
+	}
+	return board
+}
+
+func TestListRepository_SQLite(t *testing.T) {
+	db := setupTestDB(t)
+	ctx := context.Background()
+	board := createTestBoard(t, db, createTestUser(t, db, "owner@example.com"))
+	repo := NewListRepository(db)
+
+	var lists []*models.List
+	for _, title := range []string{"todo", "doing", "done"} {
+		list := &models.List{PublicID: uuid.New(), BoardPublicID: board.PublicID, BoardInternalID: board.InternalID, Tittle: title, CreatedAt: time.Now()}
+		if err := repo.Create(ctx, list, -1); err != nil {
+			t.Fatalf("Create() error = %v", err)
+		}
+		lists = append(lists, list)
+	}
+
+	//list_order disimpan sebagai JSON di sqlite dan harus terbaca lagi sebagai UUIDArray
+	position, err := repo.FindPosition(ctx, board.InternalID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := types.UUIDArray{lists[0].PublicID, lists[1].PublicID, lists[2].PublicID}
+	if !slices.Equal(position.ListOrder, want) {
+		t.Errorf("ListOrder = %v, want %v", position.ListOrder, want)
+	}
+
+	reordered := types.UUIDArray{lists[2].PublicID, lists[0].PublicID, lists[1].PublicID}
+	stale := position.Version - 1
+	if _, err := repo.Reorder(ctx, board.InternalID, reordered, &stale); !errors.Is(err, ErrStaleVersion) {
+		t.Errorf("Reorder() with stale version error = %v, want ErrStaleVersion", err)
+	}
+	updated, err := repo.Reorder(ctx, board.InternalID, reordered, &position.Version)
+	if err != nil {
+		t.Fatalf("Reorder() error = %v", err)
+	}
+	if !slices.Equal(updated.ListOrder, reordered) || updated.Version != position.Version+1 {
+		t.Errorf("Reorder() = %+v", updated)
+	}
+}
+
+func TestAttachmentRepository_Quota_SQLite(t *testing.T) {
+	db := setupTestDB(t)
+	ctx := context.Background()
+	owner := createTestUser(t, db, "owner@example.com")
+	board := createTestBoard(t, db, owner)
+
+	list := &models.List{PublicID: uuid.New(), BoardPublicID: board.PublicID, BoardInternalID: board.InternalID, Tittle: "todo", CreatedAt: time.Now()}
+	if err := NewListRepository(db).Create(ctx, list, -1); err != nil {
+		t.Fatal(err)
+	}
+	card := &models.Card{PublicID: uuid.New(), ListID: list.InternalID, Title: "card", CreatedAt: time.Now()}
+	if err := NewCardRepository(db).Create(ctx, card, -1); err != nil {
+		t.Fatal(err)
+	}
+
+	repo := NewAttachmentRepository(db)
+	newAttachment := func(size int64) *models.CardAttachment {
+		return &models.CardAttachment{PublicID: uuid.New(), CardID: card.InternalID, UserID: owner.InternalID, File: "key", FileName: "a.txt", MimeType: "text/plain", Size: size, CreatedAt: time.Now()}
+	}
+
+	first := newAttachment(60)
+	if err := repo.CreateWithinQuota(ctx, first, board.InternalID, 100, 0); err != nil {
+		t.Fatalf("CreateWithinQuota() error = %v", err)
+	}
+	if err := repo.CreateWithinQuota(ctx, newAttachment(50), board.InternalID, 100, 0); !errors.Is(err, ErrBoardQuotaExceeded) {
+		t.Errorf("CreateWithinQuota() over board quota error = %v", err)
+	}
+	if err := repo.CreateWithinQuota(ctx, newAttachment(50), board.InternalID, 0, 80); !errors.Is(err, ErrUserQuotaExceeded) {
+		t.Errorf("CreateWithinQuota() over user quota error = %v", err)
+	}
+
+	//ukuran thumbnail ikut dihitung di quota dan laporan
+	thumbnails := []models.AttachmentThumbnail{{AttachmentID: first.InternalID, Size: "small", File: "thumb", ByteSize: 25}}
+	if err := repo.SaveThumbnails(ctx, first, thumbnails); err != nil {
+		t.Fatal(err)
+	}
+	if err := repo.CreateWithinQuota(ctx, newAttachment(10), board.InternalID, 90, 0); !errors.Is(err, ErrBoardQuotaExceeded) {
+		t.Errorf("CreateWithinQuota() over board quota with thumbnails error = %v", err)
+	}
+
+	usage, err := repo.UsageByBoard(ctx, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(usage) != 1 || usage[0].BoardPublicID != board.PublicID || usage[0].Bytes != 85 || usage[0].Files != 1 {
+		t.Errorf("UsageByBoard() = %+v", usage)
+	}
+	users, err := repo.UsageByUser(ctx, 10)
+	if err != nil || len(users) != 1 || users[0].Bytes != 85 {
+		t.Errorf("UsageByUser() = %+v, %v", users, err)
+	}
+
+	if _, err := repo.Delete(ctx, first); err != nil {
+		t.Fatal(err)
+	}
+	if used, err := repo.BoardUsage(ctx, board.InternalID); err != nil || used != 0 {
+		t.Errorf("BoardUsage() after delete = %d, %v", used, err)
+	}
+	if err := repo.SaveThumbnails(ctx, first, nil); !errors.Is(err, ErrAttachmentDeleted) {
+		t.Errorf("SaveThumbnails() after delete error = %v, want ErrAttachmentDeleted", err)
+	}
+}
+
+func TestTransactor_SQLite(t *testing.T) {
+	db := setupTestDB(t)
+	ctx := context.Background()
+	repo := NewUserRepository(db)
+	transactor := NewTransactor(db)
+
+	//semua repository di dalam fn ikut di rollback kalau fn gagal
+	failure := errors.New("boom")
+	err := transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := repo.Create(ctx, &models.User{PublicID: uuid.New(), Email: "rollback@example.com", Password: "hash"}); err != nil {
+			return err
+		}
+		return failure
+	})
+	if !errors.Is(err, failure) {
+		t.Fatalf("WithinTransaction() error = %v", err)
+	}
+	if _, err := repo.FindByEmail(ctx, "rollback@example.com"); !errors.Is(err, gorm.ErrRecordNotFound) {
+		t.Errorf("user should be rolled back, got %v", err)
+	}
+
+	err = transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		return repo.Create(ctx, &models.User{PublicID: uuid.New(), Email: "commit@example.com", Password: "hash"})
+	})
+	if err != nil {
+		t.Fatalf("WithinTransaction() error = %v", err)
+	}
+	if _, err := repo.FindByEmail(ctx, "commit@example.com"); err != nil {
+		t.Errorf("user should be committed, got %v", err)
+	}
+
+	//ctx yang sudah dibatalkan tidak boleh menjalankan query
+	cancelled, cancel := context.WithCancel(ctx)
+	cancel()
+	if _, err := repo.FindByEmail(cancelled, "commit@example.com"); !errors.Is(err, context.Canceled) {
+		t.Errorf("FindByEmail() with cancelled context error = %v", err)
+	}
+}
+
+func TestUserTokenRepository_SQLite(t *testing.T) {
+	db := setupTestDB(t)
+	ctx := context.Background()
+	user := createTestUser(t, db, "budi@example.com")
+	repo := NewUserTokenRepository(db)
+
+	token := &models.UserToken{UserID: user.InternalID, Purpose: models.TokenPurposeEmailVerification, TokenHash: "hash", ExpiresAt: time.Now().Add(time.Hour)}
+	if err := repo.Create(ctx, token); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := repo.FindByHash(ctx, "other_purpose", "hash"); !errors.Is(err, gorm.ErrRecordNotFound) {
+		t.Errorf("FindByHash() with other purpose error = %v", err)
+	}
+
+	found, err := repo.FindByHash(ctx, models.TokenPurposeEmailVerification, "hash")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := repo.Consume(ctx, found); err != nil {
+		t.Fatalf("Consume() error = %v", err)
+	}
+	if err := repo.Consume(ctx, found); !errors.Is(err, ErrUserTokenUsed) {
+		t.Errorf("Consume() twice error = %v, want ErrUserTokenUsed", err)
+	}
+
+	if err := NewUserRepository(db).MarkEmailVerified(ctx, user); err != nil {
+		t.Fatal(err)
+	}
+	reloaded, err := NewUserRepository(db).FindByID(ctx, user.InternalID)
+	if err != nil || reloaded.EmailVerifiedAt == nil {
+		t.Errorf("EmailVerifiedAt = %v, %v", reloaded.EmailVerifiedAt, err)
+	}
+}
+
+func TestCardRepository_MoveRace_SQLite(t *testing.T) {
+	repos := map[string]func(db *gorm.DB) CardRepository{
+		"order": NewCardRepository,
+		"rank":  NewCardRankRepository,
+	}
+	for name, newRepo := range repos {
+		t.Run(name, func(t *testing.T) {
+			db := setupTestDB(t)
+			ctx := context.Background()
+			board := createTestBoard(t, db, createTestUser(t, db, "owner@example.com"))
+			repo := newRepo(db)
+
+			var lists []*models.List
+			for _, title := range []string{"todo", "doing", "done"} {
+				list := &models.List{PublicID: uuid.New(), BoardPublicID: board.PublicID, BoardInternalID: board.InternalID, Tittle: title, CreatedAt: time.Now()}
+				if err := NewListRepository(db).Create(ctx, list, -1); err != nil {
+					t.Fatal(err)
+				}
+				lists = append(lists, list)
+			}
+			card := &models.Card{PublicID: uuid.New(), ListID: lists[0].InternalID, Title: "card", CreatedAt: time.Now()}
+			if err := repo.Create(ctx, card, -1); err != nil {
+				t.Fatal(err)
+			}
+
+			//dua request membaca card yang sama sebelum salah satunya memindahkan card
+			versioned, unversioned := *card, *card
+			source, err := repo.FindPosition(ctx, lists[0].InternalID)
+			if err != nil {
+				t.Fatal(err)
+			}
+			target, err := repo.FindPosition(ctx, lists[1].InternalID)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if _, _, err := repo.Move(ctx, &versioned, lists[1].InternalID, 0, &source.Version, &target.Version); err != nil {
+				t.Fatalf("versioned Move() error = %v", err)
+			}
+
+			//move tanpa version masih memakai list lama, harus ditolak baik ke list lain maupun di list yang sama
+			if _, _, err := repo.Move(ctx, &unversioned, lists[2].InternalID, 0, nil, nil); !errors.Is(err, ErrStaleVersion) {
+				t.Errorf("unversioned Move() to other list error = %v, want ErrStaleVersion", err)
+			}
+			if _, _, err := repo.Move(ctx, &unversioned, lists[0].InternalID, 0, nil, nil); !errors.Is(err, ErrStaleVersion) {
+				t.Errorf("unversioned Move() within old list error = %v, want ErrStaleVersion", err)
+			}
+
+			//card hanya boleh ada di list tujuan move yang menang
+			for i, list := range lists {
+				cards, _, err := repo.FindOrdered(ctx, list.InternalID)
+				if err != nil {
+					t.Fatal(err)
+				}
+				var got []uuid.UUID
+				for _, c := range cards {
+					got = append(got, c.PublicID)
+				}
+				var want []uuid.UUID
+				if i == 1 {
+					want = []uuid.UUID{card.PublicID}
+				}
+				if !slices.Equal(got, want) {
+					t.Errorf("list %s cards = %v, want %v", list.Tittle, got, want)
+				}
+			}
+			stored, err := repo.FindByID(ctx, card.InternalID)
+			if err != nil || stored.ListID != lists[1].InternalID {
+				t.Errorf("card list = %d, %v, want %d", stored.ListID, err, lists[1].InternalID)
+			}
+		})
+	}
+}
+
+func TestCardRankRepository_SQLite(t *testing.T) {
+	db := setupTestDB(t)
+	ctx := context.Background()
+	board := createTestBoard(t, db, createTestUser(t, db, "owner@example.com"))
+	list := &models.List{PublicID: uuid.New(), BoardPublicID: board.PublicID, BoardInternalID: board.InternalID, Tittle: "todo", CreatedAt: time.Now()}
+	if err := NewListRankRepository(db).Create(ctx, list, -1); err != nil {
+		t.Fatal(err)
+	}
+	repo := NewCardRankRepository(db)
+
+	//urutan akhir : c, a, d, b
+	cards := map[string]*models.Card{}
+	for _, step := range []struct {
+		title string
+		index int
+	}{{"a", -1}, {"b", -1}, {"c", 0}, {"d", 2}} {
+		card := &models.Card{PublicID: uuid.New(), ListID: list.InternalID, Title: step.title, CreatedAt: time.Now()}
+		if err := repo.Create(ctx, card, step.index); err != nil {
+			t.Fatalf("Create(%s) error = %v", step.title, err)
+		}
+		cards[step.title] = card
+	}
+
+	titles := func() []string {
+		t.Helper()
+		found, err := repo.FindByList(ctx, list.InternalID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var got []string
+		for _, c := range found {
+			got = append(got, c.Title)
+		}
+		return got
+	}
+	if got := titles(); !slices.Equal(got, []string{"c", "a", "d", "b"}) {
+		t.Fatalf("FindByList() = %v", got)
+	}
+
+	//move hanya mengubah rank card yang dipindah dan tidak membangun ulang card_order
+	before, _ := repo.FindByList(ctx, list.InternalID)
+	moved := *cards["b"]
+	source, target, err := repo.Move(ctx, &moved, list.InternalID, 1, nil, nil)
+	if err != nil {
+		t.Fatalf("Move() error = %v", err)
+	}
+	if source.CardOrder != nil || target.CardOrder != nil || target.Version != 5 {
+		t.Errorf("Move() position = %+v, want version 5 without card_order", target)
+	}
+	if got := titles(); !slices.Equal(got, []string{"c", "b", "a", "d"}) {
+		t.Errorf("FindByList() after Move = %v", got)
+	}
+	after, _ := repo.FindByList(ctx, list.InternalID)
+	for _, old := range before {
+		for _, c := range after {
+			if c.InternalID == old.InternalID && c.InternalID != moved.InternalID && c.Rank != old.Rank {
+				t.Errorf("rank of %s changed from %q to %q", c.Title, old.Rank, c.Rank)
+			}
+		}
+	}
+
+	//rank dibandingkan byte per byte, bukan memakai collation bahasa
+	for title, rank := range map[string]string{"c": "b", "b": "B", "a": "a", "d": "1"} {
+		if err := db.Model(cards[title]).Update("rank", rank).Error; err != nil {
+			t.Fatal(err)
+		}
+	}
+	if got := titles(); !slices.Equal(got, []string{"d", "b", "a", "c"}) {
+		t.Errorf("FindByList() byte order = %v", got)
+	}
+}
+
+func TestListRankRepository_Reorder_SQLite(t *testing.T) {
+	db := setupTestDB(t)
+	ctx := context.Background()
+	board := createTestBoard(t, db, createTestUser(t, db, "owner@example.com"))
+	repo := NewListRankRepository(db)
+
+	var lists []*models.List
+	for _, title := range []string{"todo", "doing", "review", "done"} {
+		list := &models.List{PublicID: uuid.New(), BoardPublicID: board.PublicID, BoardInternalID: board.InternalID, Tittle: title, CreatedAt: time.Now()}
+		if err := repo.Create(ctx, list, -1); err != nil {
+			t.Fatal(err)
+		}
+		lists = append(lists, list)
+	}
+
+	//done dipindah ke depan, rank list lain tidak ditulis ulang
+	order := types.UUIDArray{lists[3].PublicID, lists[0].PublicID, lists[1].PublicID, lists[2].PublicID}
+	if _, err := repo.Reorder(ctx, board.InternalID, order, nil); err != nil {
+		t.Fatalf("Reorder() error = %v", err)
+	}
+
+	found, position, err := repo.FindOrdered(ctx, board.InternalID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(position.ListOrder, order) {
+		t.Errorf("ListOrder = %v, want %v", position.ListOrder, order)
+	}
+	for _, list := range found {
+		for _, old := range lists[:3] {
+			if list.InternalID == old.InternalID && list.Rank != old.Rank {
+				t.Errorf("rank of %s changed from %q to %q", list.Tittle, old.Rank, list.Rank)
+			}
+		}
+	}
+}
@@ -0,0 +1,40 @@
+package repositories
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+)
+
+//unit of work : transaksi dibawa lewat context, jadi repository yang dipanggil dengan ctx dari
+//WithinTransaction otomatis memakai transaksi yang sama tanpa perlu tahu ada transaksi
+
+type txKey struct{}
+
+type Transactor interface {
+	// WithinTransaction menjalankan fn dalam satu transaksi, rollback kalau fn mengembalikan error
+	// kalau ctx sudah membawa transaksi, fn dijalankan sebagai savepoint di dalam transaksi tersebut
+	WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error
+}
+
+type transactor struct {
+	db *gorm.DB
+}
+
+func NewTransactor(db *gorm.DB) Transactor {
+	return &transactor{db}
+}
+
+func (t *transactor) WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	return conn(ctx, t.db).Transaction(func(tx *gorm.DB) error {
+		return fn(context.WithValue(ctx, txKey{}, tx))
+	})
+}
+
+// conn mengembalikan transaksi yang sedang berjalan di ctx, atau db biasa yang membawa ctx
+func conn(ctx context.Context, db *gorm.DB) *gorm.DB {
+	if tx, ok := ctx.Value(txKey{}).(*gorm.DB); ok {
+		return tx
+	}
+	return db.WithContext(ctx)
+}
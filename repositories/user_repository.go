@@ -1,29 +1,65 @@
 package repositories
 
 import (
-	"github.com/odink789/project-management/config"
+	"context"
+	"time"
+
 	"github.com/odink789/project-management/models"
+	"gorm.io/gorm"
 )
 
 type UserRepository interface {
-	Create(user *models.User) error
-	FindByEmail(email string) (*models.User, error)
+	Create(ctx context.Context, user *models.User) error
+	FindByEmail(ctx context.Context, email string) (*models.User, error)
+	FindByPublicID(ctx context.Context, publicID string) (*models.User, error)
+	FindByID(ctx context.Context, id int64) (*models.User, error)
+	MarkEmailVerified(ctx context.Context, user *models.User) error
+	UpdatePassword(ctx context.Context, user *models.User, hash string) error
 }
 
 type userRepository struct {
+	db *gorm.DB
+}
+
+func NewUserRepository(db *gorm.DB) UserRepository {
+	return &userRepository{db}
 }
 
-func NewUserRepository() UserRepository {
-	return &userRepository{}
+func (r *userRepository) Create(ctx context.Context, user *models.User) error {
+	return conn(ctx, r.db).Create(user).Error
+}
+
+func (r *userRepository) FindByEmail(ctx context.Context, email string) (*models.User, error) {
+	var user models.User
+	err := conn(ctx, r.db).Where("email = ?", email).First(&user).Error
+	return &user, err
 }
 
-func (r *userRepository) Create(user *models.User) error {
-	return config.DB.Create(user).Error
+func (r *userRepository) FindByPublicID(ctx context.Context, publicID string) (*models.User, error) {
+	var user models.User
+	err := conn(ctx, r.db).Where("public_id = ?", publicID).First(&user).Error
+	return &user, err
 }
 
-func (r *userRepository) FindByEmail(email string) (*models.User, error) {
+func (r *userRepository) FindByID(ctx context.Context, id int64) (*models.User, error) {
 	var user models.User
-	err := config.DB.Where("email = ? ",email).First(&user).Error
+	err := conn(ctx, r.db).First(&user, id).Error
 	return &user, err
 }
 
+func (r *userRepository) MarkEmailVerified(ctx context.Context, user *models.User) error {
+	now := time.Now()
+	if err := conn(ctx, r.db).Model(user).Update("email_verified_at", now).Error; err != nil {
+		return err
+	}
+	user.EmailVerifiedAt = &now
+	return nil
+}
+
+func (r *userRepository) UpdatePassword(ctx context.Context, user *models.User, hash string) error {
+	if err := conn(ctx, r.db).Model(user).Update("password", hash).Error; err != nil {
+		return err
+	}
+	user.Password = hash
+	return nil
+}
@@ -0,0 +1,60 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/odink789/project-management/models"
+	"gorm.io/gorm"
+)
+
+var ErrUserTokenUsed = errors.New("user token already used")
+
+type UserTokenRepository interface {
+	Create(ctx context.Context, token *models.UserToken) error
+	FindByHash(ctx context.Context, purpose, hash string) (*models.UserToken, error)
+	Consume(ctx context.Context, token *models.UserToken) error
+	InvalidateByUser(ctx context.Context, userID int64, purpose string) error
+}
+
+type userTokenRepository struct {
+	db *gorm.DB
+}
+
+func NewUserTokenRepository(db *gorm.DB) UserTokenRepository {
+	return &userTokenRepository{db}
+}
+
+func (r *userTokenRepository) Create(ctx context.Context, token *models.UserToken) error {
+	return conn(ctx, r.db).Create(token).Error
+}
+
+func (r *userTokenRepository) FindByHash(ctx context.Context, purpose, hash string) (*models.UserToken, error) {
+	var token models.UserToken
+	err := conn(ctx, r.db).Where("purpose = ? AND token_hash = ?", purpose, hash).First(&token).Error
+	return &token, err
+}
+
+// tandai token sudah dipakai, kalau ternyata sudah dipakai duluan (request paralel) kembalikan ErrUserTokenUsed
+func (r *userTokenRepository) Consume(ctx context.Context, token *models.UserToken) error {
+	now := time.Now()
+	res := conn(ctx, r.db).Model(&models.UserToken{}).
+		Where("internal_id = ? AND used_at IS NULL", token.InternalID).
+		Update("used_at", now)
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return ErrUserTokenUsed
+	}
+	token.UsedAt = &now
+	return nil
+}
+
+// token lama yang belum dipakai dimatikan, dipakai saat token baru dikirim ulang
+func (r *userTokenRepository) InvalidateByUser(ctx context.Context, userID int64, purpose string) error {
+	return conn(ctx, r.db).Model(&models.UserToken{}).
+		Where("user_internal_id = ? AND purpose = ? AND used_at IS NULL", userID, purpose).
+		Update("used_at", time.Now()).Error
+}
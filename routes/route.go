@@ -1,18 +1,91 @@
 package routes
 
 import (
-	"log"
-
 	"github.com/gofiber/fiber/v2"
-	"github.com/joho/godotenv"
 	"github.com/odink789/project-management/controllers"
+	"github.com/odink789/project-management/middleware"
+	"github.com/odink789/project-management/models"
 )
 
-func Setup(app *fiber.App, uc *controllers.UserController) {
-	err := godotenv.Load()
-	if err != nil {
-		log.Fatal("Error Loading .env file")
-	}
+func Setup(app *fiber.App, uc *controllers.UserController, bc *controllers.BoardController, lc *controllers.ListController, cc *controllers.CardController, ac *controllers.CardAssigneeController, lbc *controllers.LabelController, cmc *controllers.CommentController, atc *controllers.AttachmentController) {
 	app.Post("/v1/auth/register", uc.Register)
+	app.Post("/v1/auth/login", uc.Login)
+	app.Post("/v1/auth/refresh", uc.Refresh)
+	app.Post("/v1/auth/verify-email", uc.VerifyEmail)
+	app.Post("/v1/auth/forgot-password", uc.ForgotPassword)
+	app.Post("/v1/auth/reset-password", uc.ResetPassword)
+
+	//route auth tetap bisa dipakai sebelum email terverifikasi, misalnya untuk kirim ulang email verifikasi
+	auth := app.Group("/v1/auth", middleware.JWTProtected())
+	auth.Post("/logout", uc.Logout)
+	auth.Post("/logout-all", uc.LogoutAll)
+	auth.Get("/sessions", uc.Sessions)
+	auth.Post("/verify-email/resend", uc.ResendVerification)
+	auth.Post("/change-password", uc.ChangePassword)
+
+	boards := app.Group("/v1/boards", middleware.JWTProtected(), middleware.RequireVerifiedEmail())
+	boards.Post("/", bc.Create)
+	boards.Get("/", bc.GetMyBoards)
+	boards.Get("/:id", bc.GetBoard)
+	boards.Put("/:id", bc.Update)
+	boards.Delete("/:id", bc.Delete)
+	boards.Get("/:id/members", bc.GetMembers)
+	boards.Post("/:id/members", bc.AddMember)
+	boards.Put("/:id/members/:userId", bc.UpdateMemberRole)
+	boards.Delete("/:id/members/:userId", bc.RemoveMember)
+
+	boards.Get("/:id/labels", lbc.GetLabels)
+	boards.Post("/:id/labels", lbc.Create)
+	boards.Put("/:id/labels/:labelId", lbc.Update)
+	boards.Delete("/:id/labels/:labelId", lbc.Delete)
+
+	boards.Get("/:boardId/lists", lc.GetLists)
+	boards.Post("/:boardId/lists", lc.Create)
+	boards.Put("/:boardId/lists/order", lc.Reorder)
+	boards.Put("/:boardId/lists/:listId", lc.Rename)
+	boards.Delete("/:boardId/lists/:listId", lc.Delete)
+
+	lists := app.Group("/v1/lists", middleware.JWTProtected(), middleware.RequireVerifiedEmail())
+	lists.Get("/:listId/cards", cc.GetCards)
+	lists.Post("/:listId/cards", cc.Create)
+
+	cards := app.Group("/v1/cards", middleware.JWTProtected(), middleware.RequireVerifiedEmail())
+	cards.Get("/:id", cc.GetCard)
+	cards.Put("/:id", cc.Update)
+	cards.Delete("/:id", cc.Delete)
+	cards.Post("/:id/move", cc.Move)
+	cards.Get("/:id/assignees", ac.GetAssignees)
+	cards.Post("/:id/assignees", ac.Assign)
+	cards.Delete("/:id/assignees/:userId", ac.Unassign)
+	cards.Get("/:id/labels", lbc.GetCardLabels)
+	cards.Post("/:id/labels", lbc.Attach)
+	cards.Delete("/:id/labels/:labelId", lbc.Detach)
+	cards.Get("/:id/comments", cmc.GetComments)
+	cards.Post("/:id/comments", cmc.Create)
+
+	cards.Get("/:id/attachments", atc.GetAttachments)
+	cards.Post("/:id/attachments", atc.Upload)
+	cards.Put("/:id/cover", atc.SetCover)
+	cards.Delete("/:id/cover", atc.RemoveCover)
+
+	attachments := app.Group("/v1/attachments", middleware.JWTProtected(), middleware.RequireVerifiedEmail())
+	attachments.Get("/:id", atc.Download)
+	attachments.Delete("/:id", atc.Delete)
+	attachments.Post("/:id/link", atc.CreateLink)
+
+	//link download yang di sign tidak memakai JWT
+	app.Get("/v1/files/:id", atc.SignedDownload)
+	app.Get("/v1/files/:id/thumbnails/:size", atc.Thumbnail)
+
+	comments := app.Group("/v1/comments", middleware.JWTProtected(), middleware.RequireVerifiedEmail())
+	comments.Put("/:id", cmc.Update)
+	comments.Delete("/:id", cmc.Delete)
+	comments.Get("/:id/history", cmc.History)
+
+	me := app.Group("/v1/me", middleware.JWTProtected(), middleware.RequireVerifiedEmail())
+	me.Get("/cards", ac.AssignedToMe)
+
+	admin := app.Group("/v1/admin", middleware.JWTProtected(), middleware.RequireVerifiedEmail(), middleware.RequireRole(models.RoleAdmin))
+	admin.Get("/storage/usage", atc.StorageUsage)
 
 }
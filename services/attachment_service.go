@@ -0,0 +1,459 @@
+package services
+
+import (
+	"bufio"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"log"
+	"mime"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/google/uuid"
+	"github.com/odink789/project-management/models"
+	"github.com/odink789/project-management/policies"
+	"github.com/odink789/project-management/repositories"
+	"github.com/odink789/project-management/storage"
+	"github.com/odink789/project-management/thumbnail"
+	"github.com/odink789/project-management/utils"
+	"gorm.io/gorm"
+)
+
+const (
+	maxFileNameLength = 255
+	defaultUsageLimit = 50
+	maxUsageLimit     = 500
+)
+
+type AttachmentService interface {
+	GetAttachments(ctx context.Context, principal *utils.Principal, cardPublicID string) ([]models.CardAttachment, error)
+	Upload(ctx context.Context, principal *utils.Principal, cardPublicID string, upload AttachmentUpload) (*models.CardAttachment, error)
+	GetAttachment(ctx context.Context, principal *utils.Principal, publicID string) (*models.CardAttachment, error)
+	Delete(ctx context.Context, principal *utils.Principal, publicID string) error
+
+	CreateDownloadLink(ctx context.Context, principal *utils.Principal, publicID string) (*DownloadLink, error)
+	ResolveDownloadLink(ctx context.Context, publicID string, expires string, signature string) (*models.CardAttachment, error)
+	OpenFile(ctx context.Context, attachment *models.CardAttachment, offset, length int64) (io.ReadCloser, error)
+	OpenThumbnail(ctx context.Context, publicID string, size string, expires string, signature string) (*models.AttachmentThumbnail, io.ReadCloser, error)
+
+	SetCover(ctx context.Context, principal *utils.Principal, cardPublicID string, attachmentPublicID string) (*models.Card, error)
+	RemoveCover(ctx context.Context, principal *utils.Principal, cardPublicID string) (*models.Card, error)
+
+	StorageUsage(ctx context.Context, limit int) (*StorageUsage, error)
+}
+
+// AttachmentQuota adalah batas upload dalam byte, 0 berarti tidak dibatasi
+type AttachmentQuota struct {
+	MaxFileSize int64
+	BoardBytes  int64
+	UserBytes   int64
+}
+
+// StorageUsage adalah laporan pemakaian storage untuk admin, diurutkan dari yang paling besar
+type StorageUsage struct {
+	MaxFileSize int64                            `json:"max_file_size"`
+	BoardQuota  int64                            `json:"board_quota"`
+	UserQuota   int64                            `json:"user_quota"`
+	Boards      []repositories.BoardStorageUsage `json:"boards"`
+	Users       []repositories.UserStorageUsage  `json:"users"`
+}
+
+// DownloadLink adalah link download attachment yang bisa dipakai tanpa token sampai ExpiresAt
+type DownloadLink struct {
+	AttachmentID string    `json:"attachment_id"`
+	Signature    string    `json:"signature"`
+	ExpiresAt    time.Time `json:"expires_at"`
+}
+
+// AttachmentUpload adalah file dari multipart form, ContentType dari client hanya dipakai kalau tidak bisa dideteksi
+type AttachmentUpload struct {
+	FileName    string
+	Size        int64
+	ContentType string
+	Reader      io.Reader
+}
+
+type attachmentService struct {
+	repo      repositories.AttachmentRepository
+	cardRepo  repositories.CardRepository
+	listRepo  repositories.ListRepository
+	boardRepo repositories.BoardRepository
+	storage   storage.Storage
+	queue     ThumbnailQueue
+	quota     AttachmentQuota
+	policy    policies.BoardPolicy
+}
+
+func NewAttachmentService(repo repositories.AttachmentRepository, cardRepo repositories.CardRepository, listRepo repositories.ListRepository, boardRepo repositories.BoardRepository, store storage.Storage, queue ThumbnailQueue, quota AttachmentQuota, policy policies.BoardPolicy) AttachmentService {
+	return &attachmentService{repo, cardRepo, listRepo, boardRepo, store, queue, quota, policy}
+}
+
+func (s *attachmentService) GetAttachments(ctx context.Context, principal *utils.Principal, cardPublicID string) ([]models.CardAttachment, error) {
+	card, _, board, err := findCard(ctx, s.cardRepo, s.listRepo, s.boardRepo, cardPublicID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.policy.CanViewBoard(ctx, principal, board); err != nil {
+		return nil, err
+	}
+	return s.repo.FindByCard(ctx, card.InternalID)
+}
+
+// file disimpan ke storage dulu sambil dihitung sha256 nya, baru row nya dibuat
+// quota dicek dua kali : sebelum upload memakai ukuran dari client supaya file besar tidak sempat di simpan,
+// lalu saat insert di dalam transaksi memakai ukuran sebenarnya. kalau gagal, file di storage dihapus lagi
+func (s *attachmentService) Upload(ctx context.Context, principal *utils.Principal, cardPublicID string, upload AttachmentUpload) (*models.CardAttachment, error) {
+	card, _, board, err := findCard(ctx, s.cardRepo, s.listRepo, s.boardRepo, cardPublicID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.policy.CanEditCard(ctx, principal, board); err != nil {
+		return nil, err
+	}
+
+	if upload.Reader == nil || upload.Size <= 0 {
+		return nil, invalidField("file", "file is required")
+	}
+	if s.quota.MaxFileSize > 0 && upload.Size > s.quota.MaxFileSize {
+		return nil, &QuotaExceededError{Scope: QuotaScopeFile, Limit: s.quota.MaxFileSize}
+	}
+	if err := s.checkQuota(ctx, board.InternalID, principal.UserID, upload.Size); err != nil {
+		return nil, err
+	}
+
+	attachment := &models.CardAttachment{
+		PublicID:  uuid.New(),
+		CardID:    card.InternalID,
+		UserID:    principal.UserID,
+		FileName:  sanitizeFileName(upload.FileName),
+		CreatedAt: time.Now(),
+	}
+	attachment.File = "attachments/" + card.PublicID.String() + "/" + attachment.PublicID.String()
+
+	reader := bufio.NewReader(upload.Reader)
+	head, _ := reader.Peek(512)
+	attachment.MimeType = detectMimeType(head, attachment.FileName, upload.ContentType)
+	if thumbnail.Supported(attachment.MimeType) {
+		attachment.Thumbnail = models.ThumbnailPending
+	}
+
+	hash := sha256.New()
+	counter := &countingWriter{}
+	body := io.TeeReader(reader, io.MultiWriter(hash, counter))
+	if err := s.storage.Put(attachment.File, body, upload.Size, attachment.MimeType); err != nil {
+		return nil, err
+	}
+	attachment.Size = counter.n
+	attachment.Checksum = hex.EncodeToString(hash.Sum(nil))
+
+	if s.quota.MaxFileSize > 0 && attachment.Size > s.quota.MaxFileSize {
+		removeFiles(s.storage, attachment.File)
+		return nil, &QuotaExceededError{Scope: QuotaScopeFile, Limit: s.quota.MaxFileSize}
+	}
+
+	if err := s.repo.CreateWithinQuota(ctx, attachment, board.InternalID, s.quota.BoardBytes, s.quota.UserBytes); err != nil {
+		removeFiles(s.storage, attachment.File)
+		//upload lain masuk duluan, hitung ulang pemakaian supaya error nya berisi angka terbaru
+		if errors.Is(err, repositories.ErrBoardQuotaExceeded) || errors.Is(err, repositories.ErrUserQuotaExceeded) {
+			if quotaErr := s.checkQuota(ctx, board.InternalID, principal.UserID, attachment.Size); quotaErr != nil {
+				return nil, quotaErr
+			}
+		}
+		return nil, err
+	}
+
+	if attachment.Thumbnail == models.ThumbnailPending {
+		s.queue.Enqueue(attachment.InternalID)
+	}
+	return attachment, nil
+}
+
+func (s *attachmentService) GetAttachment(ctx context.Context, principal *utils.Principal, publicID string) (*models.CardAttachment, error) {
+	attachment, board, err := s.findAttachment(ctx, publicID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.policy.CanViewBoard(ctx, principal, board); err != nil {
+		return nil, err
+	}
+	return attachment, nil
+}
+
+// membership dicek saat link dibuat, link yang sudah keluar tetap berlaku sampai expired
+func (s *attachmentService) CreateDownloadLink(ctx context.Context, principal *utils.Principal, publicID string) (*DownloadLink, error) {
+	attachment, err := s.GetAttachment(ctx, principal, publicID)
+	if err != nil {
+		return nil, err
+	}
+
+	signature, expiresAt, err := utils.SignDownload(attachment.PublicID.String())
+	if err != nil {
+		return nil, err
+	}
+	return &DownloadLink{AttachmentID: attachment.PublicID.String(), Signature: signature, ExpiresAt: expiresAt}, nil
+}
+
+func (s *attachmentService) ResolveDownloadLink(ctx context.Context, publicID string, expires string, signature string) (*models.CardAttachment, error) {
+	if err := utils.VerifyDownload(publicID, expires, signature); err != nil {
+		return nil, err
+	}
+
+	attachment, _, err := s.findAttachment(ctx, publicID)
+	return attachment, err
+}
+
+// reader harus ditutup oleh pemanggil, length sama dengan Size berarti file utuh
+func (s *attachmentService) OpenFile(ctx context.Context, attachment *models.CardAttachment, offset, length int64) (io.ReadCloser, error) {
+	var file io.ReadCloser
+	var err error
+	if offset == 0 && length == attachment.Size {
+		file, err = s.storage.Open(attachment.File)
+	} else {
+		file, err = s.storage.OpenRange(attachment.File, offset, length)
+	}
+
+	if errors.Is(err, storage.ErrNotFound) {
+		return nil, ErrAttachmentNotFound
+	}
+	return file, err
+}
+
+// row dihapus dulu, file yang gagal dihapus dari storage hanya di log
+func (s *attachmentService) Delete(ctx context.Context, principal *utils.Principal, publicID string) error {
+	attachment, board, err := s.findAttachment(ctx, publicID)
+	if err != nil {
+		return err
+	}
+
+	if err := s.policy.CanEditCard(ctx, principal, board); err != nil {
+		return err
+	}
+
+	files, err := s.repo.Delete(ctx, attachment)
+	if err != nil {
+		return err
+	}
+	removeFiles(s.storage, files...)
+	return nil
+}
+
+// thumbnail hanya bisa dibuka lewat link yang di sign (dikirim di cover card)
+func (s *attachmentService) OpenThumbnail(ctx context.Context, publicID string, size string, expires string, signature string) (*models.AttachmentThumbnail, io.ReadCloser, error) {
+	if err := utils.VerifyDownload(thumbnailSubject(publicID, size), expires, signature); err != nil {
+		return nil, nil, err
+	}
+
+	attachment, _, err := s.findAttachment(ctx, publicID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	thumbnails, err := s.repo.FindThumbnails(ctx, []int64{attachment.InternalID})
+	if err != nil {
+		return nil, nil, err
+	}
+	for i := range thumbnails {
+		if thumbnails[i].Size != size {
+			continue
+		}
+
+		file, err := s.storage.Open(thumbnails[i].File)
+		if errors.Is(err, storage.ErrNotFound) {
+			return nil, nil, ErrThumbnailNotFound
+		}
+		if err != nil {
+			return nil, nil, err
+		}
+		return &thumbnails[i], file, nil
+	}
+	return nil, nil, ErrThumbnailNotFound
+}
+
+// cover harus gambar yang di attach ke card yang sama
+func (s *attachmentService) SetCover(ctx context.Context, principal *utils.Principal, cardPublicID string, attachmentPublicID string) (*models.Card, error) {
+	card, _, board, err := findCard(ctx, s.cardRepo, s.listRepo, s.boardRepo, cardPublicID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.policy.CanEditCard(ctx, principal, board); err != nil {
+		return nil, err
+	}
+
+	attachment, _, err := s.findAttachment(ctx, attachmentPublicID)
+	if err != nil {
+		return nil, err
+	}
+	if attachment.CardID != card.InternalID {
+		return nil, ErrForeignAttachment
+	}
+	if !thumbnail.Supported(attachment.MimeType) {
+		return nil, ErrCoverNotImage
+	}
+
+	card.CoverID = &attachment.InternalID
+	return s.saveCover(ctx, card)
+}
+
+func (s *attachmentService) RemoveCover(ctx context.Context, principal *utils.Principal, cardPublicID string) (*models.Card, error) {
+	card, _, board, err := findCard(ctx, s.cardRepo, s.listRepo, s.boardRepo, cardPublicID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.policy.CanEditCard(ctx, principal, board); err != nil {
+		return nil, err
+	}
+
+	card.CoverID = nil
+	return s.saveCover(ctx, card)
+}
+
+func (s *attachmentService) saveCover(ctx context.Context, card *models.Card) (*models.Card, error) {
+	if err := s.cardRepo.UpdateCover(ctx, card); err != nil {
+		return nil, err
+	}
+
+	cards := []models.Card{*card}
+	if err := fillCovers(ctx, s.repo, cards); err != nil {
+		return nil, err
+	}
+	return &cards[0], nil
+}
+
+func (s *attachmentService) findAttachment(ctx context.Context, publicID string) (*models.CardAttachment, *models.Board, error) {
+	if _, err := uuid.Parse(publicID); err != nil {
+		return nil, nil, invalidField("id", "invalid attachment id")
+	}
+
+	attachment, err := s.repo.FindByPublicID(ctx, publicID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil, ErrAttachmentNotFound
+		}
+		return nil, nil, err
+	}
+
+	card, err := s.cardRepo.FindByID(ctx, attachment.CardID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	_, board, err := cardBoard(ctx, s.listRepo, s.boardRepo, card)
+	if err != nil {
+		return nil, nil, err
+	}
+	return attachment, board, nil
+}
+
+// admin only, dicek oleh middleware di route
+func (s *attachmentService) StorageUsage(ctx context.Context, limit int) (*StorageUsage, error) {
+	if limit <= 0 || limit > maxUsageLimit {
+		limit = defaultUsageLimit
+	}
+
+	boards, err := s.repo.UsageByBoard(ctx, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	users, err := s.repo.UsageByUser(ctx, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	return &StorageUsage{
+		MaxFileSize: s.quota.MaxFileSize,
+		BoardQuota:  s.quota.BoardBytes,
+		UserQuota:   s.quota.UserBytes,
+		Boards:      boards,
+		Users:       users,
+	}, nil
+}
+
+// kembalikan QuotaExceededError kalau file sebesar size sudah tidak muat di board atau di quota user
+func (s *attachmentService) checkQuota(ctx context.Context, boardID, userID int64, size int64) error {
+	if s.quota.BoardBytes > 0 {
+		used, err := s.repo.BoardUsage(ctx, boardID)
+		if err != nil {
+			return err
+		}
+		if used+size > s.quota.BoardBytes {
+			return &QuotaExceededError{Scope: QuotaScopeBoard, Limit: s.quota.BoardBytes, Used: used}
+		}
+	}
+	if s.quota.UserBytes > 0 {
+		used, err := s.repo.UserUsage(ctx, userID)
+		if err != nil {
+			return err
+		}
+		if used+size > s.quota.UserBytes {
+			return &QuotaExceededError{Scope: QuotaScopeUser, Limit: s.quota.UserBytes, Used: used}
+		}
+	}
+	return nil
+}
+
+// dipanggil setelah row nya terhapus, file yang gagal dihapus hanya di log
+func removeFiles(store storage.Storage, keys ...string) {
+	for _, key := range keys {
+		if err := store.Delete(key); err != nil {
+			log.Println("Failed to delete attachment file", key, err)
+		}
+	}
+}
+
+// content type dideteksi dari isi file, kalau hasil nya generik pakai extension lalu header dari client
+func detectMimeType(head []byte, fileName string, fallback string) string {
+	detected := http.DetectContentType(head)
+	if detected != "application/octet-stream" && !strings.HasPrefix(detected, "text/plain") {
+		return detected
+	}
+	if byExt := mime.TypeByExtension(strings.ToLower(filepath.Ext(fileName))); byExt != "" {
+		return byExt
+	}
+	if fallback != "" && detected == "application/octet-stream" {
+		if mediaType, _, err := mime.ParseMediaType(fallback); err == nil {
+			return mediaType
+		}
+	}
+	return detected
+}
+
+// nama file dari client hanya dipakai untuk ditampilkan, path dan karakter kontrol dibuang
+func sanitizeFileName(name string) string {
+	name = filepath.Base(strings.ReplaceAll(name, "\\", "/"))
+	name = strings.Map(func(r rune) rune {
+		if unicode.IsControl(r) {
+			return -1
+		}
+		return r
+	}, name)
+	name = strings.TrimSpace(name)
+
+	if name == "" || name == "." || name == ".." || name == "/" {
+		return "file"
+	}
+	if runes := []rune(name); len(runes) > maxFileNameLength {
+		name = string(runes[:maxFileNameLength])
+	}
+	return name
+}
+
+type countingWriter struct {
+	n int64
+}
+
+func (w *countingWriter) Write(p []byte) (int, error) {
+	w.n += int64(len(p))
+	return len(p), nil
+}
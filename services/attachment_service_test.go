@@ -0,0 +1,483 @@
+package services
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"net/url"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/odink789/project-management/config"
+	"github.com/odink789/project-management/database/migrations"
+	"github.com/odink789/project-management/models"
+	"github.com/odink789/project-management/policies"
+	"github.com/odink789/project-management/repositories"
+	"github.com/odink789/project-management/storage"
+	"github.com/odink789/project-management/utils"
+	"gorm.io/gorm"
+)
+
+type fakeAttachmentRepo struct {
+	repositories.AttachmentRepository
+	attachments []*models.CardAttachment
+	thumbnails  []models.AttachmentThumbnail
+}
+
+func (r *fakeAttachmentRepo) FindByPublicID(ctx context.Context, publicID string) (*models.CardAttachment, error) {
+	for _, a := range r.attachments {
+		if a.PublicID.String() == publicID {
+			return a, nil
+		}
+	}
+	return &models.CardAttachment{}, gorm.ErrRecordNotFound
+}
+
+func (r *fakeAttachmentRepo) Create(ctx context.Context, attachment *models.CardAttachment) error {
+	attachment.InternalID = int64(len(r.attachments) + 1)
+	r.attachments = append(r.attachments, attachment)
+	return nil
+}
+
+// fake hanya punya satu board, jadi pemakaian board adalah semua attachment
+func (r *fakeAttachmentRepo) CreateWithinQuota(ctx context.Context, attachment *models.CardAttachment, boardID int64, boardQuota, userQuota int64) error {
+	boardUsed, _ := r.BoardUsage(ctx, boardID)
+	if boardQuota > 0 && boardUsed+attachment.Size > boardQuota {
+		return repositories.ErrBoardQuotaExceeded
+	}
+	userUsed, _ := r.UserUsage(ctx, attachment.UserID)
+	if userQuota > 0 && userUsed+attachment.Size > userQuota {
+		return repositories.ErrUserQuotaExceeded
+	}
+	return r.Create(ctx, attachment)
+}
+
+func (r *fakeAttachmentRepo) BoardUsage(ctx context.Context, boardID int64) (int64, error) {
+	var used int64
+	for _, a := range r.attachments {
+		used += a.Size
+	}
+	return used, nil
+}
+
+func (r *fakeAttachmentRepo) UserUsage(ctx context.Context, userID int64) (int64, error) {
+	var used int64
+	for _, a := range r.attachments {
+		if a.UserID == userID {
+			used += a.Size
+		}
+	}
+	return used, nil
+}
+
+func (r *fakeAttachmentRepo) Delete(ctx context.Context, attachment *models.CardAttachment) ([]string, error) {
+	for i, a := range r.attachments {
+		if a == attachment {
+			r.attachments = append(r.attachments[:i], r.attachments[i+1:]...)
+		}
+	}
+	files := []string{attachment.File}
+	for _, t := range r.thumbnails {
+		if t.AttachmentID == attachment.InternalID {
+			files = append(files, t.File)
+		}
+	}
+	return files, nil
+}
+
+func (r *fakeAttachmentRepo) FindByIDs(ctx context.Context, ids []int64) ([]models.CardAttachment, error) {
+	var found []models.CardAttachment
+	for _, a := range r.attachments {
+		for _, id := range ids {
+			if a.InternalID == id {
+				found = append(found, *a)
+			}
+		}
+	}
+	return found, nil
+}
+
+func (r *fakeAttachmentRepo) FindThumbnails(ctx context.Context, attachmentIDs []int64) ([]models.AttachmentThumbnail, error) {
+	var found []models.AttachmentThumbnail
+	for _, t := range r.thumbnails {
+		for _, id := range attachmentIDs {
+			if t.AttachmentID == id {
+				found = append(found, t)
+			}
+		}
+	}
+	return found, nil
+}
+
+func (r *fakeCardRepo) FindByID(ctx context.Context, id int64) (*models.Card, error) {
+	return r.card, nil
+}
+
+func (r *fakeCardRepo) UpdateCover(ctx context.Context, card *models.Card) error {
+	r.card.CoverID = card.CoverID
+	return nil
+}
+
+type fakeQueue struct {
+	queued []int64
+}
+
+func (q *fakeQueue) Enqueue(attachmentID int64) {
+	q.queued = append(q.queued, attachmentID)
+}
+
+func TestAttachmentService(t *testing.T) {
+	board := &models.Board{InternalID: 1, PublicID: uuid.New()}
+	list := &models.List{InternalID: 1, PublicID: uuid.New(), BoardInternalID: 1}
+	card := &models.Card{InternalID: 1, PublicID: uuid.New(), ListID: 1}
+
+	member := &utils.Principal{UserID: 1, PublicID: uuid.New(), Role: models.RoleUser}
+	outsider := &utils.Principal{UserID: 2, PublicID: uuid.New(), Role: models.RoleUser}
+	memberRepo := &fakeMemberRepo{roles: map[int64]models.BoardRole{member.UserID: models.BoardRoleMember}}
+
+	store, err := storage.NewLocalStorage(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	repo := &fakeAttachmentRepo{}
+	queue := &fakeQueue{}
+	service := NewAttachmentService(repo, &fakeCardRepo{card: card}, &fakeListRepo{list: list}, &fakeBoardRepo{board: board}, store, queue, AttachmentQuota{}, policies.NewBoardPolicy(memberRepo))
+
+	content := "%PDF-1.4 fake pdf content"
+	upload := AttachmentUpload{FileName: "../../etc/report.pdf", Size: int64(len(content)), ContentType: "text/html", Reader: strings.NewReader(content)}
+	if _, err := service.Upload(context.Background(), outsider, card.PublicID.String(), upload); !errors.Is(err, policies.ErrForbidden) {
+		t.Errorf("outsider Upload() error = %v, want ErrForbidden", err)
+	}
+
+	attachment, err := service.Upload(context.Background(), member, card.PublicID.String(), upload)
+	if err != nil {
+		t.Fatalf("Upload() error = %v", err)
+	}
+
+	sum := sha256.Sum256([]byte(content))
+	if attachment.Checksum != hex.EncodeToString(sum[:]) {
+		t.Errorf("Checksum = %s", attachment.Checksum)
+	}
+	if attachment.Size != int64(len(content)) || attachment.FileName != "report.pdf" || attachment.MimeType != "application/pdf" {
+		t.Errorf("Upload() = %+v", attachment)
+	}
+	if !strings.HasPrefix(attachment.File, "attachments/"+card.PublicID.String()+"/") {
+		t.Errorf("File key = %s", attachment.File)
+	}
+
+	found, err := service.GetAttachment(context.Background(), member, attachment.PublicID.String())
+	if err != nil {
+		t.Fatalf("GetAttachment() error = %v", err)
+	}
+	file, err := service.OpenFile(context.Background(), found, 0, found.Size)
+	if err != nil {
+		t.Fatalf("OpenFile() error = %v", err)
+	}
+	got, _ := io.ReadAll(file)
+	file.Close()
+	if string(got) != content {
+		t.Errorf("OpenFile() content = %q", got)
+	}
+
+	file, err = service.OpenFile(context.Background(), found, 5, 3)
+	if err != nil {
+		t.Fatalf("OpenFile() range error = %v", err)
+	}
+	got, _ = io.ReadAll(file)
+	file.Close()
+	if string(got) != "1.4" {
+		t.Errorf("OpenFile() range content = %q", got)
+	}
+
+	config.AppConfig = &config.Config{JWTSecret: "test-secret", DownloadExpire: 5 * time.Minute}
+	if _, err := service.CreateDownloadLink(context.Background(), outsider, attachment.PublicID.String()); !errors.Is(err, policies.ErrForbidden) {
+		t.Errorf("outsider CreateDownloadLink() error = %v, want ErrForbidden", err)
+	}
+	link, err := service.CreateDownloadLink(context.Background(), member, attachment.PublicID.String())
+	if err != nil {
+		t.Fatalf("CreateDownloadLink() error = %v", err)
+	}
+	expires := strconv.FormatInt(link.ExpiresAt.Unix(), 10)
+	if _, err := service.ResolveDownloadLink(context.Background(), link.AttachmentID, expires, link.Signature); err != nil {
+		t.Errorf("ResolveDownloadLink() error = %v", err)
+	}
+	if _, err := service.ResolveDownloadLink(context.Background(), link.AttachmentID, expires, "bogus"); !errors.Is(err, utils.ErrInvalidSignature) {
+		t.Errorf("ResolveDownloadLink() with bad signature error = %v", err)
+	}
+
+	if attachment.Thumbnail != "" || len(queue.queued) != 0 {
+		t.Errorf("pdf should not be queued for thumbnails, status = %q", attachment.Thumbnail)
+	}
+	if _, err := service.SetCover(context.Background(), member, card.PublicID.String(), attachment.PublicID.String()); err == nil {
+		t.Error("SetCover() with a pdf should fail")
+	}
+
+	if err := service.Delete(context.Background(), member, attachment.PublicID.String()); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	if _, err := store.Open(attachment.File); !errors.Is(err, storage.ErrNotFound) {
+		t.Errorf("file should be removed from storage, got %v", err)
+	}
+}
+
+func TestAttachmentService_Cover(t *testing.T) {
+	config.AppConfig = &config.Config{JWTSecret: "test-secret", DownloadExpire: 5 * time.Minute}
+
+	board := &models.Board{InternalID: 1, PublicID: uuid.New()}
+	list := &models.List{InternalID: 1, PublicID: uuid.New(), BoardInternalID: 1}
+	card := &models.Card{InternalID: 1, PublicID: uuid.New(), ListID: 1}
+	member := &utils.Principal{UserID: 1, PublicID: uuid.New(), Role: models.RoleUser}
+	memberRepo := &fakeMemberRepo{roles: map[int64]models.BoardRole{member.UserID: models.BoardRoleMember}}
+
+	store, err := storage.NewLocalStorage(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	repo := &fakeAttachmentRepo{}
+	queue := &fakeQueue{}
+	service := NewAttachmentService(repo, &fakeCardRepo{card: card}, &fakeListRepo{list: list}, &fakeBoardRepo{board: board}, store, queue, AttachmentQuota{}, policies.NewBoardPolicy(memberRepo))
+
+	png := "\x89PNG\r\n\x1a\n rest of the image"
+	image, err := service.Upload(context.Background(), member, card.PublicID.String(), AttachmentUpload{FileName: "photo.png", Size: int64(len(png)), Reader: strings.NewReader(png)})
+	if err != nil {
+		t.Fatalf("Upload() error = %v", err)
+	}
+	if image.Thumbnail != models.ThumbnailPending || len(queue.queued) != 1 || queue.queued[0] != image.InternalID {
+		t.Fatalf("image should be queued for thumbnails, status = %q queued = %v", image.Thumbnail, queue.queued)
+	}
+
+	withCover, err := service.SetCover(context.Background(), member, card.PublicID.String(), image.PublicID.String())
+	if err != nil {
+		t.Fatalf("SetCover() error = %v", err)
+	}
+	if withCover.Cover == nil || withCover.Cover.AttachmentID != image.PublicID || withCover.Cover.Status != models.ThumbnailPending {
+		t.Fatalf("SetCover() cover = %+v", withCover.Cover)
+	}
+
+	//setelah worker selesai, cover berisi link thumbnail yang di sign
+	image.Thumbnail = models.ThumbnailReady
+	repo.thumbnails = []models.AttachmentThumbnail{{AttachmentID: image.InternalID, Size: "small", File: "thumbnails/x/small.jpg", Width: 128, Height: 64, ByteSize: 4}}
+	if err := store.Put("thumbnails/x/small.jpg", strings.NewReader("jpeg"), 4, "image/jpeg"); err != nil {
+		t.Fatal(err)
+	}
+
+	cards := []models.Card{*card}
+	if err := fillCovers(context.Background(), repo, cards); err != nil {
+		t.Fatalf("fillCovers(context.Background(), ) error = %v", err)
+	}
+	cover := cards[0].Cover
+	if cover == nil || len(cover.Images) != 1 {
+		t.Fatalf("cover = %+v", cover)
+	}
+
+	link, err := url.Parse(cover.Images[0].URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if link.Path != "/v1/files/"+image.PublicID.String()+"/thumbnails/small" {
+		t.Errorf("thumbnail url path = %s", link.Path)
+	}
+	thumb, file, err := service.OpenThumbnail(context.Background(), image.PublicID.String(), "small", link.Query().Get("expires"), link.Query().Get("signature"))
+	if err != nil {
+		t.Fatalf("OpenThumbnail() error = %v", err)
+	}
+	file.Close()
+	if thumb.Width != 128 {
+		t.Errorf("OpenThumbnail() = %+v", thumb)
+	}
+
+	//signature thumbnail tidak bisa dipakai untuk file asli atau ukuran lain
+	if _, err := service.ResolveDownloadLink(context.Background(), image.PublicID.String(), link.Query().Get("expires"), link.Query().Get("signature")); !errors.Is(err, utils.ErrInvalidSignature) {
+		t.Errorf("thumbnail signature used for original error = %v", err)
+	}
+	if _, _, err := service.OpenThumbnail(context.Background(), image.PublicID.String(), "large", link.Query().Get("expires"), link.Query().Get("signature")); !errors.Is(err, utils.ErrInvalidSignature) {
+		t.Errorf("thumbnail signature used for other size error = %v", err)
+	}
+
+	removed, err := service.RemoveCover(context.Background(), member, card.PublicID.String())
+	if err != nil {
+		t.Fatalf("RemoveCover() error = %v", err)
+	}
+	if removed.Cover != nil || card.CoverID != nil {
+		t.Errorf("RemoveCover() cover = %+v", removed.Cover)
+	}
+}
+
+func TestAttachmentService_Quota(t *testing.T) {
+	board := &models.Board{InternalID: 1, PublicID: uuid.New()}
+	list := &models.List{InternalID: 1, PublicID: uuid.New(), BoardInternalID: 1}
+	card := &models.Card{InternalID: 1, PublicID: uuid.New(), ListID: 1}
+	alice := &utils.Principal{UserID: 1, PublicID: uuid.New(), Role: models.RoleUser}
+	bob := &utils.Principal{UserID: 2, PublicID: uuid.New(), Role: models.RoleUser}
+	memberRepo := &fakeMemberRepo{roles: map[int64]models.BoardRole{
+		alice.UserID: models.BoardRoleMember,
+		bob.UserID:   models.BoardRoleMember,
+	}}
+
+	store, err := storage.NewLocalStorage(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	repo := &fakeAttachmentRepo{}
+	quota := AttachmentQuota{MaxFileSize: 10, BoardBytes: 20, UserBytes: 12}
+	service := NewAttachmentService(repo, &fakeCardRepo{card: card}, &fakeListRepo{list: list}, &fakeBoardRepo{board: board}, store, &fakeQueue{}, quota, policies.NewBoardPolicy(memberRepo))
+
+	upload := func(principal *utils.Principal, content string) (*models.CardAttachment, error) {
+		return service.Upload(context.Background(), principal, card.PublicID.String(), AttachmentUpload{FileName: "notes.txt", Size: int64(len(content)), Reader: strings.NewReader(content)})
+	}
+	scope := func(err error) string {
+		var quotaErr *QuotaExceededError
+		if !errors.As(err, &quotaErr) {
+			return ""
+		}
+		return quotaErr.Scope
+	}
+
+	if _, err := upload(alice, "01234567890"); scope(err) != QuotaScopeFile {
+		t.Errorf("oversized Upload() error = %v, want file quota", err)
+	}
+
+	first, err := upload(alice, "012345678")
+	if err != nil {
+		t.Fatalf("Upload() error = %v", err)
+	}
+	if _, err := upload(alice, "0123"); scope(err) != QuotaScopeUser {
+		t.Errorf("Upload() over user quota error = %v, want user quota", err)
+	}
+
+	if _, err := upload(bob, "012345678"); err != nil {
+		t.Fatalf("bob Upload() error = %v", err)
+	}
+	_, err = upload(bob, "0123")
+	var quotaErr *QuotaExceededError
+	if !errors.As(err, &quotaErr) || quotaErr.Scope != QuotaScopeBoard || quotaErr.Used != 18 || quotaErr.Limit != 20 {
+		t.Errorf("Upload() over board quota error = %v", err)
+	}
+	if len(repo.attachments) != 2 {
+		t.Errorf("rejected uploads should not be stored, got %d attachments", len(repo.attachments))
+	}
+
+	//setelah file dihapus, quota nya kembali
+	if err := service.Delete(context.Background(), alice, first.PublicID.String()); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	if _, err := upload(alice, "0123"); err != nil {
+		t.Errorf("Upload() after delete error = %v", err)
+	}
+}
+
+// card dihapus lewat cascade, pemakaian quota dan file di storage harus ikut bersih
+func TestCardService_DeleteFreesAttachments_SQLite(t *testing.T) {
+	db, err := config.OpenDB(&config.Config{DBDriver: config.DBDriverSQLite, DBPath: ":memory:"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := migrations.Up(db); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		if sqlDB, err := db.DB(); err == nil {
+			sqlDB.Close()
+		}
+	})
+	ctx := context.Background()
+
+	user := &models.User{PublicID: uuid.New(), Name: "owner", Email: "owner@example.com", Password: "hash", Role: models.RoleUser}
+	if err := repositories.NewUserRepository(db).Create(ctx, user); err != nil {
+		t.Fatal(err)
+	}
+	boardRepo := repositories.NewBoardRepository(db)
+	board := &models.Board{PublicID: uuid.New(), Title: "Board", OwnerID: user.InternalID, OwnerPublicID: user.PublicID, CreatedAt: time.Now()}
+	if err := boardRepo.Create(ctx, board); err != nil {
+		t.Fatal(err)
+	}
+	listRepo := repositories.NewListRepository(db)
+	list := &models.List{PublicID: uuid.New(), BoardPublicID: board.PublicID, BoardInternalID: board.InternalID, Tittle: "todo", CreatedAt: time.Now()}
+	if err := listRepo.Create(ctx, list, -1); err != nil {
+		t.Fatal(err)
+	}
+	cardRepo := repositories.NewCardRepository(db)
+	card := &models.Card{PublicID: uuid.New(), ListID: list.InternalID, Title: "card", CreatedAt: time.Now()}
+	if err := cardRepo.Create(ctx, card, -1); err != nil {
+		t.Fatal(err)
+	}
+
+	store, err := storage.NewLocalStorage(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	principal := &utils.Principal{UserID: user.InternalID, PublicID: user.PublicID, Role: models.RoleUser}
+	policy := policies.NewBoardPolicy(&fakeMemberRepo{roles: map[int64]models.BoardRole{user.InternalID: models.BoardRoleOwner}})
+	attachmentRepo := repositories.NewAttachmentRepository(db)
+	attachments := NewAttachmentService(attachmentRepo, cardRepo, listRepo, boardRepo, store, &fakeQueue{}, AttachmentQuota{BoardBytes: 100}, policy)
+	cards := NewCardService(cardRepo, listRepo, boardRepo, attachmentRepo, repositories.NewTransactor(db), store, policy)
+
+	uploaded, err := attachments.Upload(ctx, principal, card.PublicID.String(), AttachmentUpload{FileName: "notes.txt", Size: 5, Reader: strings.NewReader("hello")})
+	if err != nil {
+		t.Fatalf("Upload() error = %v", err)
+	}
+	thumbnail := models.AttachmentThumbnail{AttachmentID: uploaded.InternalID, Size: "small", File: uploaded.File + "_small"}
+	if err := store.Put(thumbnail.File, strings.NewReader("thumb"), 5, "image/jpeg"); err != nil {
+		t.Fatal(err)
+	}
+	if err := attachmentRepo.SaveThumbnails(ctx, uploaded, []models.AttachmentThumbnail{thumbnail}); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := cards.Delete(ctx, principal, card.PublicID.String()); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	if used, err := attachmentRepo.BoardUsage(ctx, board.InternalID); err != nil || used != 0 {
+		t.Errorf("BoardUsage() after card delete = %d, %v", used, err)
+	}
+	for _, key := range []string{uploaded.File, thumbnail.File} {
+		if _, err := store.Open(key); !errors.Is(err, storage.ErrNotFound) {
+			t.Errorf("Open(%s) after card delete error = %v, want ErrNotFound", key, err)
+		}
+	}
+}
+
+func TestSanitizeFileName(t *testing.T) {
+	tests := map[string]string{
+		"report.pdf":             "report.pdf",
+		"../../etc/passwd":       "passwd",
+		`C:\Users\me\photo.png`:  "photo.png",
+		"  spaced name.txt  ":    "spaced name.txt",
+		"bad\x00\nname.txt":      "badname.txt",
+		"":                       "file",
+		"..":                     "file",
+		strings.Repeat("a", 300): strings.Repeat("a", maxFileNameLength),
+	}
+
+	for input, want := range tests {
+		if got := sanitizeFileName(input); got != want {
+			t.Errorf("sanitizeFileName(%q) = %q, want %q", input, got, want)
+		}
+	}
+}
+
+func TestDetectMimeType(t *testing.T) {
+	png := []byte("\x89PNG\r\n\x1a\n")
+	tests := []struct {
+		head     []byte
+		fileName string
+		fallback string
+		want     string
+	}{
+		{png, "photo.txt", "text/plain", "image/png"},
+		{[]byte(`{"a": 1}`), "data.json", "", "application/json"},
+		{[]byte{0x00, 0x01, 0x02}, "blob", "application/x-custom", "application/x-custom"},
+		{[]byte{0x00, 0x01, 0x02}, "blob", "", "application/octet-stream"},
+	}
+
+	for _, tt := range tests {
+		if got := detectMimeType(tt.head, tt.fileName, tt.fallback); got != tt.want {
+			t.Errorf("detectMimeType(%q) = %q, want %q", tt.fileName, got, tt.want)
+		}
+	}
+}
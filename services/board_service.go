@@ -0,0 +1,253 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/odink789/project-management/models"
+	"github.com/odink789/project-management/policies"
+	"github.com/odink789/project-management/repositories"
+	"github.com/odink789/project-management/storage"
+	"github.com/odink789/project-management/utils"
+	"gorm.io/gorm"
+)
+
+type BoardService interface {
+	Create(ctx context.Context, principal *utils.Principal, board *models.Board) error
+	GetByPublicID(ctx context.Context, principal *utils.Principal, publicID string) (*models.Board, error)
+	ListMine(ctx context.Context, principal *utils.Principal) ([]models.Board, error)
+	Update(ctx context.Context, principal *utils.Principal, publicID string, input *models.Board) (*models.Board, error)
+	Delete(ctx context.Context, principal *utils.Principal, publicID string) error
+
+	ListMembers(ctx context.Context, principal *utils.Principal, publicID string) ([]models.BoardMember, error)
+	AddMember(ctx context.Context, principal *utils.Principal, publicID string, email string, role models.BoardRole) (*models.BoardMember, error)
+	UpdateMemberRole(ctx context.Context, principal *utils.Principal, publicID string, userPublicID string, role models.BoardRole) (*models.BoardMember, error)
+	RemoveMember(ctx context.Context, principal *utils.Principal, publicID string, userPublicID string) error
+}
+
+type boardService struct {
+	repo       repositories.BoardRepository
+	memberRepo repositories.BoardMemberRepository
+	userRepo   repositories.UserRepository
+	storage    storage.Storage
+	policy     policies.BoardPolicy
+}
+
+func NewBoardService(repo repositories.BoardRepository, memberRepo repositories.BoardMemberRepository, userRepo repositories.UserRepository, store storage.Storage, policy policies.BoardPolicy) BoardService {
+	return &boardService{repo, memberRepo, userRepo, store, policy}
+}
+
+func (s *boardService) Create(ctx context.Context, principal *utils.Principal, board *models.Board) error {
+	board.Title = strings.TrimSpace(board.Title)
+	if board.Title == "" {
+		return invalidField("title", "title is required")
+	}
+
+	board.PublicID = uuid.New()
+	board.OwnerID = principal.UserID
+	board.OwnerPublicID = principal.PublicID
+	board.CreatedAt = time.Now()
+	return s.repo.Create(ctx, board)
+}
+
+func (s *boardService) GetByPublicID(ctx context.Context, principal *utils.Principal, publicID string) (*models.Board, error) {
+	board, err := s.findBoard(ctx, publicID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.policy.CanViewBoard(ctx, principal, board); err != nil {
+		return nil, err
+	}
+	return board, nil
+}
+
+func (s *boardService) ListMine(ctx context.Context, principal *utils.Principal) ([]models.Board, error) {
+	return s.repo.FindByMember(ctx, principal.UserID)
+}
+
+func (s *boardService) Update(ctx context.Context, principal *utils.Principal, publicID string, input *models.Board) (*models.Board, error) {
+	board, err := s.findBoard(ctx, publicID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.policy.CanEditBoard(ctx, principal, board); err != nil {
+		return nil, err
+	}
+
+	title := strings.TrimSpace(input.Title)
+	if title == "" {
+		return nil, invalidField("title", "title is required")
+	}
+
+	board.Title = title
+	board.Description = input.Description
+	board.Duedate = input.Duedate
+	if err := s.repo.Update(ctx, board); err != nil {
+		return nil, err
+	}
+	return board, nil
+}
+
+func (s *boardService) Delete(ctx context.Context, principal *utils.Principal, publicID string) error {
+	board, err := s.findBoard(ctx, publicID)
+	if err != nil {
+		return err
+	}
+
+	if err := s.policy.CanDeleteBoard(ctx, principal, board); err != nil {
+		return err
+	}
+
+	//file attachment semua card di board baru dihapus dari storage setelah commit
+	files, err := s.repo.Delete(ctx, board)
+	if err != nil {
+		return err
+	}
+	removeFiles(s.storage, files...)
+	return nil
+}
+
+func (s *boardService) ListMembers(ctx context.Context, principal *utils.Principal, publicID string) ([]models.BoardMember, error) {
+	board, err := s.GetByPublicID(ctx, principal, publicID)
+	if err != nil {
+		return nil, err
+	}
+	return s.memberRepo.FindByBoard(ctx, board.InternalID)
+}
+
+func (s *boardService) AddMember(ctx context.Context, principal *utils.Principal, publicID string, email string, role models.BoardRole) (*models.BoardMember, error) {
+	board, err := s.findBoard(ctx, publicID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.policy.CanManageMembers(ctx, principal, board); err != nil {
+		return nil, err
+	}
+
+	if role == "" {
+		role = models.BoardRoleMember
+	}
+	//owner hanya satu yaitu pembuat board
+	if !role.IsValid() || role == models.BoardRoleOwner {
+		return nil, invalidField("role", "invalid board role")
+	}
+
+	user, err := s.userRepo.FindByEmail(ctx, email)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrUserNotFound
+		}
+		return nil, err
+	}
+
+	_, err = s.memberRepo.FindMember(ctx, board.InternalID, user.InternalID)
+	if err == nil {
+		return nil, ErrAlreadyMember
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	member := &models.BoardMember{
+		BoardID:  board.InternalID,
+		UserID:   user.InternalID,
+		Role:     role,
+		JoinedAt: time.Now(),
+		User:     user,
+	}
+	if err := s.memberRepo.Create(ctx, member); err != nil {
+		return nil, err
+	}
+	return member, nil
+}
+
+func (s *boardService) UpdateMemberRole(ctx context.Context, principal *utils.Principal, publicID string, userPublicID string, role models.BoardRole) (*models.BoardMember, error) {
+	board, member, err := s.findMember(ctx, principal, publicID, userPublicID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !role.IsValid() || role == models.BoardRoleOwner {
+		return nil, invalidField("role", "invalid board role")
+	}
+	if member.UserID == board.OwnerID {
+		return nil, ErrOwnerRoleLocked
+	}
+
+	member.Role = role
+	if err := s.memberRepo.UpdateRole(ctx, member); err != nil {
+		return nil, err
+	}
+	return member, nil
+}
+
+func (s *boardService) RemoveMember(ctx context.Context, principal *utils.Principal, publicID string, userPublicID string) error {
+	board, member, err := s.findMember(ctx, principal, publicID, userPublicID)
+	if err != nil {
+		return err
+	}
+
+	if member.UserID == board.OwnerID {
+		return ErrOwnerNotRemovable
+	}
+	return s.memberRepo.Delete(ctx, member)
+}
+
+func (s *boardService) findBoard(ctx context.Context, publicID string) (*models.Board, error) {
+	return findBoard(ctx, s.repo, publicID)
+}
+
+// dipakai juga oleh service lain yang route nya berada di bawah /v1/boards/:id
+func findBoard(ctx context.Context, repo repositories.BoardRepository, publicID string) (*models.Board, error) {
+	if _, err := uuid.Parse(publicID); err != nil {
+		return nil, invalidField("id", "invalid board id")
+	}
+
+	board, err := repo.FindByPublicID(ctx, publicID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrBoardNotFound
+		}
+		return nil, err
+	}
+	return board, nil
+}
+
+func (s *boardService) findMember(ctx context.Context, principal *utils.Principal, publicID string, userPublicID string) (*models.Board, *models.BoardMember, error) {
+	board, err := s.findBoard(ctx, publicID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if err := s.policy.CanManageMembers(ctx, principal, board); err != nil {
+		return nil, nil, err
+	}
+
+	if _, err := uuid.Parse(userPublicID); err != nil {
+		return nil, nil, invalidField("user_id", "invalid user id")
+	}
+
+	user, err := s.userRepo.FindByPublicID(ctx, userPublicID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil, ErrUserNotFound
+		}
+		return nil, nil, err
+	}
+
+	member, err := s.memberRepo.FindMember(ctx, board.InternalID, user.InternalID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil, ErrMemberNotFound
+		}
+		return nil, nil, err
+	}
+	member.User = user
+	return board, member, nil
+}
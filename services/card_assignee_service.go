@@ -0,0 +1,128 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/odink789/project-management/models"
+	"github.com/odink789/project-management/policies"
+	"github.com/odink789/project-management/repositories"
+	"github.com/odink789/project-management/utils"
+	"gorm.io/gorm"
+)
+
+type CardAssigneeService interface {
+	GetAssignees(ctx context.Context, principal *utils.Principal, cardPublicID string) ([]models.User, error)
+	Assign(ctx context.Context, principal *utils.Principal, cardPublicID string, userPublicID string) (*models.User, error)
+	Unassign(ctx context.Context, principal *utils.Principal, cardPublicID string, userPublicID string) error
+	AssignedToMe(ctx context.Context, principal *utils.Principal) ([]repositories.AssignedCard, error)
+}
+
+type cardAssigneeService struct {
+	repo       repositories.CardAssigneeRepository
+	cardRepo   repositories.CardRepository
+	listRepo   repositories.ListRepository
+	boardRepo  repositories.BoardRepository
+	memberRepo repositories.BoardMemberRepository
+	userRepo   repositories.UserRepository
+	policy     policies.BoardPolicy
+}
+
+func NewCardAssigneeService(repo repositories.CardAssigneeRepository, cardRepo repositories.CardRepository, listRepo repositories.ListRepository, boardRepo repositories.BoardRepository, memberRepo repositories.BoardMemberRepository, userRepo repositories.UserRepository, policy policies.BoardPolicy) CardAssigneeService {
+	return &cardAssigneeService{repo, cardRepo, listRepo, boardRepo, memberRepo, userRepo, policy}
+}
+
+func (s *cardAssigneeService) GetAssignees(ctx context.Context, principal *utils.Principal, cardPublicID string) ([]models.User, error) {
+	card, _, board, err := findCard(ctx, s.cardRepo, s.listRepo, s.boardRepo, cardPublicID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.policy.CanViewBoard(ctx, principal, board); err != nil {
+		return nil, err
+	}
+	return s.repo.FindUsersByCard(ctx, card.InternalID)
+}
+
+// hanya member board yang boleh di assign ke card
+func (s *cardAssigneeService) Assign(ctx context.Context, principal *utils.Principal, cardPublicID string, userPublicID string) (*models.User, error) {
+	card, _, board, err := findCard(ctx, s.cardRepo, s.listRepo, s.boardRepo, cardPublicID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.policy.CanEditCard(ctx, principal, board); err != nil {
+		return nil, err
+	}
+
+	user, err := s.findUser(ctx, userPublicID)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := s.memberRepo.FindMember(ctx, board.InternalID, user.InternalID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrAssigneeNotMember
+		}
+		return nil, err
+	}
+
+	assignee := &models.CardAssignee{
+		CardID:     card.InternalID,
+		UserID:     user.InternalID,
+		AssignedAt: time.Now(),
+	}
+	if err := s.repo.Create(ctx, assignee); err != nil {
+		if errors.Is(err, repositories.ErrAlreadyAssigned) {
+			return nil, ErrAlreadyAssigned
+		}
+		return nil, err
+	}
+	return user, nil
+}
+
+// user yang sudah keluar dari board tetap bisa dilepas dari card
+func (s *cardAssigneeService) Unassign(ctx context.Context, principal *utils.Principal, cardPublicID string, userPublicID string) error {
+	card, _, board, err := findCard(ctx, s.cardRepo, s.listRepo, s.boardRepo, cardPublicID)
+	if err != nil {
+		return err
+	}
+
+	if err := s.policy.CanEditCard(ctx, principal, board); err != nil {
+		return err
+	}
+
+	user, err := s.findUser(ctx, userPublicID)
+	if err != nil {
+		return err
+	}
+
+	if err := s.repo.Delete(ctx, card.InternalID, user.InternalID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrAssigneeNotFound
+		}
+		return err
+	}
+	return nil
+}
+
+func (s *cardAssigneeService) AssignedToMe(ctx context.Context, principal *utils.Principal) ([]repositories.AssignedCard, error) {
+	return s.repo.FindCardsByUser(ctx, principal.UserID)
+}
+
+func (s *cardAssigneeService) findUser(ctx context.Context, publicID string) (*models.User, error) {
+	if _, err := uuid.Parse(publicID); err != nil {
+		return nil, invalidField("user_id", "invalid user id")
+	}
+
+	user, err := s.userRepo.FindByPublicID(ctx, publicID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrUserNotFound
+		}
+		return nil, err
+	}
+	return user, nil
+}
@@ -0,0 +1,138 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/odink789/project-management/models"
+	"github.com/odink789/project-management/policies"
+	"github.com/odink789/project-management/repositories"
+	"github.com/odink789/project-management/utils"
+	"gorm.io/gorm"
+)
+
+type fakeCardRepo struct {
+	repositories.CardRepository
+	card *models.Card
+}
+
+func (r *fakeCardRepo) FindByPublicID(ctx context.Context, publicID string) (*models.Card, error) {
+	if r.card.PublicID.String() != publicID {
+		return &models.Card{}, gorm.ErrRecordNotFound
+	}
+	return r.card, nil
+}
+
+type fakeListRepo struct {
+	repositories.ListRepository
+	list *models.List
+}
+
+func (r *fakeListRepo) FindByID(ctx context.Context, id int64) (*models.List, error) {
+	return r.list, nil
+}
+
+type fakeBoardRepo struct {
+	repositories.BoardRepository
+	board *models.Board
+}
+
+func (r *fakeBoardRepo) FindByID(ctx context.Context, id int64) (*models.Board, error) {
+	return r.board, nil
+}
+
+type fakeMemberRepo struct {
+	repositories.BoardMemberRepository
+	roles map[int64]models.BoardRole
+}
+
+func (r *fakeMemberRepo) FindMember(ctx context.Context, boardID, userID int64) (*models.BoardMember, error) {
+	role, ok := r.roles[userID]
+	if !ok {
+		return &models.BoardMember{}, gorm.ErrRecordNotFound
+	}
+	return &models.BoardMember{BoardID: boardID, UserID: userID, Role: role}, nil
+}
+
+type fakeAssigneeRepo struct {
+	repositories.CardAssigneeRepository
+	assigned map[[2]int64]bool
+}
+
+func (r *fakeAssigneeRepo) Create(ctx context.Context, assignee *models.CardAssignee) error {
+	key := [2]int64{assignee.CardID, assignee.UserID}
+	if r.assigned[key] {
+		return repositories.ErrAlreadyAssigned
+	}
+	r.assigned[key] = true
+	return nil
+}
+
+func (r *fakeAssigneeRepo) Delete(ctx context.Context, cardID, userID int64) error {
+	key := [2]int64{cardID, userID}
+	if !r.assigned[key] {
+		return gorm.ErrRecordNotFound
+	}
+	delete(r.assigned, key)
+	return nil
+}
+
+func TestCardAssigneeService(t *testing.T) {
+	board := &models.Board{InternalID: 1, PublicID: uuid.New(), OwnerID: 1}
+	list := &models.List{InternalID: 1, PublicID: uuid.New(), BoardInternalID: 1}
+	card := &models.Card{InternalID: 1, PublicID: uuid.New(), ListID: 1}
+
+	owner := &models.User{InternalID: 1, PublicID: uuid.New(), Role: models.RoleUser}
+	member := &models.User{InternalID: 2, PublicID: uuid.New(), Role: models.RoleUser}
+	observer := &models.User{InternalID: 3, PublicID: uuid.New(), Role: models.RoleUser}
+	outsider := &models.User{InternalID: 4, PublicID: uuid.New(), Role: models.RoleUser}
+
+	memberRepo := &fakeMemberRepo{roles: map[int64]models.BoardRole{
+		owner.InternalID:    models.BoardRoleOwner,
+		member.InternalID:   models.BoardRoleMember,
+		observer.InternalID: models.BoardRoleObserver,
+	}}
+	assigneeRepo := &fakeAssigneeRepo{assigned: map[[2]int64]bool{}}
+	service := NewCardAssigneeService(
+		assigneeRepo,
+		&fakeCardRepo{card: card},
+		&fakeListRepo{list: list},
+		&fakeBoardRepo{board: board},
+		memberRepo,
+		&fakeUserRepo{users: []*models.User{owner, member, observer, outsider}},
+		policies.NewBoardPolicy(memberRepo),
+	)
+
+	principal := func(u *models.User) *utils.Principal {
+		return &utils.Principal{UserID: u.InternalID, PublicID: u.PublicID, Role: u.Role}
+	}
+	cardID := card.PublicID.String()
+
+	if _, err := service.Assign(context.Background(), principal(owner), cardID, member.PublicID.String()); err != nil {
+		t.Fatalf("assign member error = %v", err)
+	}
+	if _, err := service.Assign(context.Background(), principal(owner), cardID, member.PublicID.String()); !errors.Is(err, ErrConflict) {
+		t.Errorf("assign twice error = %v, want ErrConflict", err)
+	}
+	if _, err := service.Assign(context.Background(), principal(owner), cardID, outsider.PublicID.String()); !errors.Is(err, ErrAssigneeNotMember) {
+		t.Errorf("assign outsider error = %v, want ErrAssigneeNotMember", err)
+	}
+	if _, err := service.Assign(context.Background(), principal(observer), cardID, observer.PublicID.String()); !errors.Is(err, policies.ErrForbidden) {
+		t.Errorf("observer assign error = %v, want ErrForbidden", err)
+	}
+	if _, err := service.Assign(context.Background(), principal(owner), cardID, uuid.NewString()); !errors.Is(err, ErrUserNotFound) {
+		t.Errorf("assign unknown user error = %v, want ErrUserNotFound", err)
+	}
+	if _, err := service.Assign(context.Background(), principal(owner), uuid.NewString(), member.PublicID.String()); !errors.Is(err, ErrCardNotFound) {
+		t.Errorf("assign unknown card error = %v, want ErrCardNotFound", err)
+	}
+
+	if err := service.Unassign(context.Background(), principal(member), cardID, member.PublicID.String()); err != nil {
+		t.Fatalf("unassign error = %v", err)
+	}
+	if err := service.Unassign(context.Background(), principal(member), cardID, member.PublicID.String()); !errors.Is(err, ErrNotFound) {
+		t.Errorf("unassign twice error = %v, want ErrNotFound", err)
+	}
+}
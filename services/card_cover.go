@@ -0,0 +1,91 @@
+package services
+
+import (
+	"context"
+	"net/url"
+	"strconv"
+
+	"github.com/odink789/project-management/models"
+	"github.com/odink789/project-management/repositories"
+	"github.com/odink789/project-management/utils"
+)
+
+// ThumbnailQueue dipakai attachment service untuk meminta thumbnail dibuat di background
+type ThumbnailQueue interface {
+	Enqueue(attachmentID int64)
+}
+
+// fillCovers mengisi Cover di setiap card yang punya cover, attachment & thumbnail diambil sekaligus
+func fillCovers(ctx context.Context, attachmentRepo repositories.AttachmentRepository, cards []models.Card) error {
+	ids := []int64{}
+	for _, card := range cards {
+		if card.CoverID != nil {
+			ids = append(ids, *card.CoverID)
+		}
+	}
+	if len(ids) == 0 {
+		return nil
+	}
+
+	attachments, err := attachmentRepo.FindByIDs(ctx, ids)
+	if err != nil {
+		return err
+	}
+	thumbnails, err := attachmentRepo.FindThumbnails(ctx, ids)
+	if err != nil {
+		return err
+	}
+
+	byID := make(map[int64]models.CardAttachment, len(attachments))
+	for _, attachment := range attachments {
+		byID[attachment.InternalID] = attachment
+	}
+	thumbnailsByID := make(map[int64][]models.AttachmentThumbnail)
+	for _, thumbnail := range thumbnails {
+		thumbnailsByID[thumbnail.AttachmentID] = append(thumbnailsByID[thumbnail.AttachmentID], thumbnail)
+	}
+
+	for i := range cards {
+		if cards[i].CoverID == nil {
+			continue
+		}
+		attachment, ok := byID[*cards[i].CoverID]
+		if !ok {
+			continue
+		}
+
+		cover := &models.CardCover{AttachmentID: attachment.PublicID, Status: attachment.Thumbnail}
+		for _, thumbnail := range thumbnailsByID[attachment.InternalID] {
+			link, err := thumbnailURL(attachment.PublicID.String(), thumbnail.Size)
+			if err != nil {
+				return err
+			}
+			cover.Images = append(cover.Images, models.CoverImage{
+				Size:   thumbnail.Size,
+				Width:  thumbnail.Width,
+				Height: thumbnail.Height,
+				URL:    link,
+			})
+		}
+		cards[i].Cover = cover
+	}
+	return nil
+}
+
+// url relatif ke host api, di sign sama seperti link download attachment
+func thumbnailURL(attachmentID string, size string) (string, error) {
+	signature, expiresAt, err := utils.SignDownload(thumbnailSubject(attachmentID, size))
+	if err != nil {
+		return "", err
+	}
+
+	query := url.Values{}
+	query.Set("expires", strconv.FormatInt(expiresAt.Unix(), 10))
+	query.Set("signature", signature)
+	return "/v1/files/" + attachmentID + "/thumbnails/" + size + "?" + query.Encode(), nil
+}
+
+// signature thumbnail dibedakan dari file asli supaya link thumbnail tidak bisa dipakai download file asli
+func thumbnailSubject(attachmentID string, size string) string {
+	return attachmentID + "/thumbnails/" + size
+}
@@ -0,0 +1,276 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/odink789/project-management/models"
+	"github.com/odink789/project-management/policies"
+	"github.com/odink789/project-management/repositories"
+	"github.com/odink789/project-management/storage"
+	"github.com/odink789/project-management/utils"
+	"gorm.io/gorm"
+)
+
+type CardService interface {
+	GetCards(ctx context.Context, principal *utils.Principal, listPublicID string) (*OrderedCards, error)
+	Create(ctx context.Context, principal *utils.Principal, listPublicID string, card *models.Card, index int) error
+	GetByPublicID(ctx context.Context, principal *utils.Principal, publicID string) (*models.Card, error)
+	Update(ctx context.Context, principal *utils.Principal, publicID string, input *models.Card) (*models.Card, error)
+	Delete(ctx context.Context, principal *utils.Principal, publicID string) error
+	Move(ctx context.Context, principal *utils.Principal, publicID string, input CardMoveInput) (*CardMoveResult, error)
+}
+
+// CardMoveInput adalah tujuan drag and drop card
+// SourceVersion / TargetVersion opsional, diambil dari version urutan card yang terakhir dilihat client
+// tanpa version move tetap aman, card yang sudah dipindah request lain tetap ditolak dengan StaleOrderError
+type CardMoveInput struct {
+	ListID        string `json:"list_id"`
+	Position      *int   `json:"position"`
+	SourceVersion *int64 `json:"source_version"`
+	TargetVersion *int64 `json:"target_version"`
+}
+
+type cardService struct {
+	repo           repositories.CardRepository
+	listRepo       repositories.ListRepository
+	boardRepo      repositories.BoardRepository
+	attachmentRepo repositories.AttachmentRepository
+	transactor     repositories.Transactor
+	storage        storage.Storage
+	policy         policies.BoardPolicy
+}
+
+func NewCardService(repo repositories.CardRepository, listRepo repositories.ListRepository, boardRepo repositories.BoardRepository, attachmentRepo repositories.AttachmentRepository, transactor repositories.Transactor, store storage.Storage, policy policies.BoardPolicy) CardService {
+	return &cardService{repo, listRepo, boardRepo, attachmentRepo, transactor, store, policy}
+}
+
+// card dikembalikan sesuai urutan (card_order atau rank tergantung ORDERING_MODE), cover nya ikut diisi
+func (s *cardService) GetCards(ctx context.Context, principal *utils.Principal, listPublicID string) (*OrderedCards, error) {
+	list, err := findList(ctx, s.listRepo, listPublicID)
+	if err != nil {
+		return nil, err
+	}
+
+	board, err := s.boardRepo.FindByID(ctx, list.BoardInternalID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.policy.CanViewBoard(ctx, principal, board); err != nil {
+		return nil, err
+	}
+
+	cards, position, err := s.repo.FindOrdered(ctx, list.InternalID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := fillCovers(ctx, s.attachmentRepo, cards); err != nil {
+		return nil, err
+	}
+	return &OrderedCards{Version: position.Version, Cards: cards}, nil
+}
+
+func (s *cardService) Create(ctx context.Context, principal *utils.Principal, listPublicID string, card *models.Card, index int) error {
+	list, err := findList(ctx, s.listRepo, listPublicID)
+	if err != nil {
+		return err
+	}
+
+	board, err := s.boardRepo.FindByID(ctx, list.BoardInternalID)
+	if err != nil {
+		return err
+	}
+	if err := s.policy.CanEditCard(ctx, principal, board); err != nil {
+		return err
+	}
+
+	card.Title = strings.TrimSpace(card.Title)
+	if card.Title == "" {
+		return invalidField("title", "title is required")
+	}
+
+	card.InternalID = 0
+	card.PublicID = uuid.New()
+	card.ListID = list.InternalID
+	card.CreatedAt = time.Now()
+	return s.repo.Create(ctx, card, index)
+}
+
+func (s *cardService) GetByPublicID(ctx context.Context, principal *utils.Principal, publicID string) (*models.Card, error) {
+	card, _, board, err := s.findCard(ctx, publicID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.policy.CanViewBoard(ctx, principal, board); err != nil {
+		return nil, err
+	}
+
+	cards := []models.Card{*card}
+	if err := fillCovers(ctx, s.attachmentRepo, cards); err != nil {
+		return nil, err
+	}
+	return &cards[0], nil
+}
+
+func (s *cardService) Update(ctx context.Context, principal *utils.Principal, publicID string, input *models.Card) (*models.Card, error) {
+	card, _, board, err := s.findCard(ctx, publicID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.policy.CanEditCard(ctx, principal, board); err != nil {
+		return nil, err
+	}
+
+	title := strings.TrimSpace(input.Title)
+	if title == "" {
+		return nil, invalidField("title", "title is required")
+	}
+
+	card.Title = title
+	card.Description = input.Description
+	card.Duedate = input.Duedate
+	if err := s.repo.Update(ctx, card); err != nil {
+		return nil, err
+	}
+	return card, nil
+}
+
+func (s *cardService) Delete(ctx context.Context, principal *utils.Principal, publicID string) error {
+	card, _, board, err := s.findCard(ctx, publicID)
+	if err != nil {
+		return err
+	}
+
+	if err := s.policy.CanEditCard(ctx, principal, board); err != nil {
+		return err
+	}
+
+	files, err := s.repo.Delete(ctx, card)
+	if err != nil {
+		return err
+	}
+	removeFiles(s.storage, files...)
+	return nil
+}
+
+// Move dipakai untuk drag and drop, card hanya boleh dipindah ke list di board yang sama
+// pengecekan card & list tujuan dan update posisi dijalankan dalam satu transaksi
+func (s *cardService) Move(ctx context.Context, principal *utils.Principal, publicID string, input CardMoveInput) (*CardMoveResult, error) {
+	var result *CardMoveResult
+	var source, target *models.List
+	err := s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		card, list, board, err := s.findCard(ctx, publicID)
+		if err != nil {
+			return err
+		}
+		source = list
+
+		if err := s.policy.CanEditCard(ctx, principal, board); err != nil {
+			return err
+		}
+
+		target, err = findList(ctx, s.listRepo, input.ListID)
+		if err != nil {
+			return err
+		}
+		if target.BoardInternalID != board.InternalID {
+			return ErrCrossBoardMove
+		}
+
+		index := -1
+		if input.Position != nil {
+			index = *input.Position
+		}
+
+		sourcePosition, targetPosition, err := s.repo.Move(ctx, card, target.InternalID, index, input.SourceVersion, input.TargetVersion)
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			//card dihapus request lain sebelum sempat di lock
+			return ErrCardNotFound
+		}
+		if err != nil {
+			return err
+		}
+
+		orders := []OrderState{cardOrderState(source, sourcePosition)}
+		if source.InternalID != target.InternalID {
+			orders = append(orders, cardOrderState(target, targetPosition))
+		}
+		result = &CardMoveResult{Card: card, Orders: orders}
+		return nil
+	})
+	if err != nil {
+		//urutan terbaru dibaca setelah transaksi selesai supaya client dapat posisi yang sudah commit
+		//card bisa sudah pindah list, jadi list asal dibaca ulang dari card nya
+		if errors.Is(err, repositories.ErrStaleVersion) {
+			if _, current, _, findErr := s.findCard(ctx, publicID); findErr == nil {
+				source = current
+			}
+			return nil, s.staleOrderError(ctx, source, target)
+		}
+		return nil, err
+	}
+	return result, nil
+}
+
+func (s *cardService) staleOrderError(ctx context.Context, source, target *models.List) error {
+	lists := []*models.List{source}
+	if target.InternalID != source.InternalID {
+		lists = append(lists, target)
+	}
+
+	current := make([]OrderState, 0, len(lists))
+	for _, list := range lists {
+		_, position, err := s.repo.FindOrdered(ctx, list.InternalID)
+		if err != nil {
+			return err
+		}
+		current = append(current, cardOrderState(list, position))
+	}
+	return &StaleOrderError{Current: current}
+}
+
+func (s *cardService) findCard(ctx context.Context, publicID string) (*models.Card, *models.List, *models.Board, error) {
+	return findCard(ctx, s.repo, s.listRepo, s.boardRepo, publicID)
+}
+
+// findCard mengambil card beserta list dan board nya untuk pengecekan policy
+// dipakai juga oleh service lain yang route nya berada di bawah /v1/cards/:id
+func findCard(ctx context.Context, repo repositories.CardRepository, listRepo repositories.ListRepository, boardRepo repositories.BoardRepository, publicID string) (*models.Card, *models.List, *models.Board, error) {
+	if _, err := uuid.Parse(publicID); err != nil {
+		return nil, nil, nil, invalidField("id", "invalid card id")
+	}
+
+	card, err := repo.FindByPublicID(ctx, publicID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil, nil, ErrCardNotFound
+		}
+		return nil, nil, nil, err
+	}
+
+	list, board, err := cardBoard(ctx, listRepo, boardRepo, card)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	return card, list, board, nil
+}
+
+// cardBoard mengambil list dan board tempat card berada
+func cardBoard(ctx context.Context, listRepo repositories.ListRepository, boardRepo repositories.BoardRepository, card *models.Card) (*models.List, *models.Board, error) {
+	list, err := listRepo.FindByID(ctx, card.ListID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	board, err := boardRepo.FindByID(ctx, list.BoardInternalID)
+	if err != nil {
+		return nil, nil, err
+	}
+	return list, board, nil
+}
@@ -0,0 +1,199 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/odink789/project-management/models"
+	"github.com/odink789/project-management/policies"
+	"github.com/odink789/project-management/repositories"
+	"github.com/odink789/project-management/utils"
+	"gorm.io/gorm"
+)
+
+const (
+	defaultCommentLimit = 20
+	maxCommentLimit     = 100
+)
+
+type CommentService interface {
+	GetComments(ctx context.Context, principal *utils.Principal, cardPublicID string, page, limit int) (*CommentPage, error)
+	Create(ctx context.Context, principal *utils.Principal, cardPublicID string, message string) (*models.Comment, error)
+	Update(ctx context.Context, principal *utils.Principal, publicID string, message string) (*models.Comment, error)
+	Delete(ctx context.Context, principal *utils.Principal, publicID string) error
+	History(ctx context.Context, principal *utils.Principal, publicID string) ([]models.CommentEdit, error)
+}
+
+// CommentPage adalah satu halaman comment di sebuah card, urut dari yang paling lama
+type CommentPage struct {
+	Comments []models.Comment `json:"comments"`
+	Page     int              `json:"page"`
+	Limit    int              `json:"limit"`
+	Total    int64            `json:"total"`
+}
+
+type commentService struct {
+	repo      repositories.CommentRepository
+	cardRepo  repositories.CardRepository
+	listRepo  repositories.ListRepository
+	boardRepo repositories.BoardRepository
+	policy    policies.BoardPolicy
+}
+
+func NewCommentService(repo repositories.CommentRepository, cardRepo repositories.CardRepository, listRepo repositories.ListRepository, boardRepo repositories.BoardRepository, policy policies.BoardPolicy) CommentService {
+	return &commentService{repo, cardRepo, listRepo, boardRepo, policy}
+}
+
+func (s *commentService) GetComments(ctx context.Context, principal *utils.Principal, cardPublicID string, page, limit int) (*CommentPage, error) {
+	card, _, board, err := findCard(ctx, s.cardRepo, s.listRepo, s.boardRepo, cardPublicID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.policy.CanViewBoard(ctx, principal, board); err != nil {
+		return nil, err
+	}
+
+	page, limit = normalizePage(page, limit)
+	comments, total, err := s.repo.FindByCard(ctx, card.InternalID, (page-1)*limit, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range comments {
+		maskRemovedComment(&comments[i])
+	}
+	return &CommentPage{Comments: comments, Page: page, Limit: limit, Total: total}, nil
+}
+
+func (s *commentService) Create(ctx context.Context, principal *utils.Principal, cardPublicID string, message string) (*models.Comment, error) {
+	card, _, board, err := findCard(ctx, s.cardRepo, s.listRepo, s.boardRepo, cardPublicID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.policy.CanComment(ctx, principal, board); err != nil {
+		return nil, err
+	}
+
+	message = strings.TrimSpace(message)
+	if message == "" {
+		return nil, invalidField("message", "message is required")
+	}
+
+	comment := &models.Comment{
+		PublicID:  uuid.New(),
+		CardID:    card.InternalID,
+		CardPubID: card.PublicID,
+		UserID:    principal.UserID,
+		UserPubID: principal.PublicID,
+		Message:   message,
+		CreatedAt: time.Now(),
+	}
+	if err := s.repo.Create(ctx, comment); err != nil {
+		return nil, err
+	}
+	return comment, nil
+}
+
+func (s *commentService) Update(ctx context.Context, principal *utils.Principal, publicID string, message string) (*models.Comment, error) {
+	comment, err := s.findModeratedComment(ctx, principal, publicID)
+	if err != nil {
+		return nil, err
+	}
+
+	message = strings.TrimSpace(message)
+	if message == "" {
+		return nil, invalidField("message", "message is required")
+	}
+
+	if err := s.repo.Update(ctx, comment, principal.UserID, message); err != nil {
+		if errors.Is(err, repositories.ErrCommentRemoved) {
+			return nil, ErrCommentRemoved
+		}
+		return nil, err
+	}
+	return comment, nil
+}
+
+func (s *commentService) Delete(ctx context.Context, principal *utils.Principal, publicID string) error {
+	comment, err := s.findModeratedComment(ctx, principal, publicID)
+	if err != nil {
+		return err
+	}
+
+	if err := s.repo.SoftDelete(ctx, comment, principal.UserID); err != nil {
+		if errors.Is(err, repositories.ErrCommentRemoved) {
+			return ErrCommentRemoved
+		}
+		return err
+	}
+	return nil
+}
+
+// riwayat isi comment hanya untuk penulis dan admin board, termasuk isi comment yang sudah dihapus
+func (s *commentService) History(ctx context.Context, principal *utils.Principal, publicID string) ([]models.CommentEdit, error) {
+	comment, err := s.findComment(ctx, principal, publicID)
+	if err != nil {
+		return nil, err
+	}
+	return s.repo.FindEdits(ctx, comment.InternalID)
+}
+
+// comment yang sudah dihapus tidak bisa di edit / dihapus lagi
+func (s *commentService) findModeratedComment(ctx context.Context, principal *utils.Principal, publicID string) (*models.Comment, error) {
+	comment, err := s.findComment(ctx, principal, publicID)
+	if err != nil {
+		return nil, err
+	}
+	if comment.IsDeleted() {
+		return nil, ErrCommentRemoved
+	}
+	return comment, nil
+}
+
+func (s *commentService) findComment(ctx context.Context, principal *utils.Principal, publicID string) (*models.Comment, error) {
+	if _, err := uuid.Parse(publicID); err != nil {
+		return nil, invalidField("id", "invalid comment id")
+	}
+
+	comment, err := s.repo.FindByPublicID(ctx, publicID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrCommentNotFound
+		}
+		return nil, err
+	}
+
+	_, _, board, err := findCard(ctx, s.cardRepo, s.listRepo, s.boardRepo, comment.CardPubID.String())
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.policy.CanModerateComment(ctx, principal, board, comment); err != nil {
+		return nil, err
+	}
+	return comment, nil
+}
+
+func maskRemovedComment(comment *models.Comment) {
+	if comment.IsDeleted() {
+		comment.Message = models.CommentRemovedMessage
+	}
+}
+
+func normalizePage(page, limit int) (int, int) {
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 {
+		limit = defaultCommentLimit
+	}
+	if limit > maxCommentLimit {
+		limit = maxCommentLimit
+	}
+	return page, limit
+}
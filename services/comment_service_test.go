@@ -0,0 +1,138 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/odink789/project-management/models"
+	"github.com/odink789/project-management/policies"
+	"github.com/odink789/project-management/repositories"
+	"github.com/odink789/project-management/utils"
+	"gorm.io/gorm"
+)
+
+type fakeCommentRepo struct {
+	repositories.CommentRepository
+	comments []*models.Comment
+	edits    []models.CommentEdit
+}
+
+func (r *fakeCommentRepo) FindByCard(ctx context.Context, cardID int64, offset, limit int) ([]models.Comment, int64, error) {
+	var page []models.Comment
+	for i, c := range r.comments {
+		if i >= offset && len(page) < limit {
+			page = append(page, *c)
+		}
+	}
+	return page, int64(len(r.comments)), nil
+}
+
+func (r *fakeCommentRepo) FindByPublicID(ctx context.Context, publicID string) (*models.Comment, error) {
+	for _, c := range r.comments {
+		if c.PublicID.String() == publicID {
+			return c, nil
+		}
+	}
+	return &models.Comment{}, gorm.ErrRecordNotFound
+}
+
+func (r *fakeCommentRepo) Create(ctx context.Context, comment *models.Comment) error {
+	comment.InternalID = int64(len(r.comments) + 1)
+	r.comments = append(r.comments, comment)
+	return nil
+}
+
+func (r *fakeCommentRepo) Update(ctx context.Context, comment *models.Comment, editorID int64, message string) error {
+	r.edits = append(r.edits, models.CommentEdit{CommentID: comment.InternalID, Message: comment.Message, EditedBy: editorID})
+	now := time.Now()
+	comment.Message = message
+	comment.EditedAt = &now
+	return nil
+}
+
+func (r *fakeCommentRepo) SoftDelete(ctx context.Context, comment *models.Comment, editorID int64) error {
+	r.edits = append(r.edits, models.CommentEdit{CommentID: comment.InternalID, Message: comment.Message, EditedBy: editorID})
+	now := time.Now()
+	comment.Message = ""
+	comment.DeletedAt = &now
+	return nil
+}
+
+func TestCommentService(t *testing.T) {
+	board := &models.Board{InternalID: 1, PublicID: uuid.New()}
+	list := &models.List{InternalID: 1, PublicID: uuid.New(), BoardInternalID: 1}
+	card := &models.Card{InternalID: 1, PublicID: uuid.New(), ListID: 1}
+
+	author := &utils.Principal{UserID: 1, PublicID: uuid.New(), Role: models.RoleUser}
+	other := &utils.Principal{UserID: 2, PublicID: uuid.New(), Role: models.RoleUser}
+	admin := &utils.Principal{UserID: 3, PublicID: uuid.New(), Role: models.RoleUser}
+
+	memberRepo := &fakeMemberRepo{roles: map[int64]models.BoardRole{
+		author.UserID: models.BoardRoleMember,
+		other.UserID:  models.BoardRoleMember,
+		admin.UserID:  models.BoardRoleAdmin,
+	}}
+	repo := &fakeCommentRepo{}
+	service := NewCommentService(repo, &fakeCardRepo{card: card}, &fakeListRepo{list: list}, &fakeBoardRepo{board: board}, policies.NewBoardPolicy(memberRepo))
+
+	comment, err := service.Create(context.Background(), author, card.PublicID.String(), "  first  ")
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	if comment.Message != "first" || comment.UserPubID != author.PublicID || comment.CardPubID != card.PublicID {
+		t.Fatalf("Create() = %+v", comment)
+	}
+	if _, err := service.Create(context.Background(), author, card.PublicID.String(), "   "); err == nil {
+		t.Error("empty message should be rejected")
+	}
+
+	id := comment.PublicID.String()
+	if _, err := service.Update(context.Background(), other, id, "hijack"); !errors.Is(err, policies.ErrForbidden) {
+		t.Errorf("other member Update() error = %v, want ErrForbidden", err)
+	}
+	if _, err := service.Update(context.Background(), author, id, "second"); err != nil {
+		t.Fatalf("author Update() error = %v", err)
+	}
+	if err := service.Delete(context.Background(), admin, id); err != nil {
+		t.Fatalf("admin Delete() error = %v", err)
+	}
+	if _, err := service.Update(context.Background(), author, id, "third"); !errors.Is(err, ErrCommentRemoved) {
+		t.Errorf("Update() on removed comment error = %v, want ErrCommentRemoved", err)
+	}
+
+	if len(repo.edits) != 2 || repo.edits[0].Message != "first" || repo.edits[1].Message != "second" {
+		t.Errorf("edits = %+v, want previous versions first and second", repo.edits)
+	}
+
+	page, err := service.GetComments(context.Background(), other, card.PublicID.String(), 0, 0)
+	if err != nil {
+		t.Fatalf("GetComments() error = %v", err)
+	}
+	if page.Page != 1 || page.Limit != defaultCommentLimit || page.Total != 1 {
+		t.Errorf("GetComments() page = %d limit = %d total = %d", page.Page, page.Limit, page.Total)
+	}
+	if page.Comments[0].Message != models.CommentRemovedMessage {
+		t.Errorf("removed comment message = %q, want placeholder", page.Comments[0].Message)
+	}
+}
+
+func TestNormalizePage(t *testing.T) {
+	tests := []struct {
+		page, limit         int
+		wantPage, wantLimit int
+	}{
+		{0, 0, 1, defaultCommentLimit},
+		{-3, 10, 1, 10},
+		{2, 1000, 2, maxCommentLimit},
+	}
+
+	for _, tt := range tests {
+		page, limit := normalizePage(tt.page, tt.limit)
+		if page != tt.wantPage || limit != tt.wantLimit {
+			t.Errorf("normalizePage(%d, %d) = %d, %d", tt.page, tt.limit, page, limit)
+		}
+	}
+}
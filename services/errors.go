@@ -0,0 +1,131 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/odink789/project-management/policies"
+)
+
+//error dari service selalu bisa dikenali jenis nya lewat errors.Is(err, ErrNotFound) dan sejenisnya
+//error yang tidak punya jenis dianggap error internal, pesan asli nya tidak dikirim ke client
+
+var (
+	ErrValidation   = errors.New("validation failed")
+	ErrUnauthorized = errors.New("unauthorized")
+	ErrForbidden    = policies.ErrForbidden
+	ErrNotFound     = errors.New("not found")
+	ErrConflict     = errors.New("conflict")
+)
+
+// Error adalah error domain dengan Code yang stabil untuk client, Kind salah satu error di atas
+// Fields hanya diisi untuk error validasi
+type Error struct {
+	Kind    error
+	Code    string
+	Message string
+	Fields  []FieldError
+}
+
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+func (e *Error) Unwrap() error {
+	return e.Kind
+}
+
+func newError(kind error, code string, message string) *Error {
+	return &Error{Kind: kind, Code: code, Message: message}
+}
+
+const CodeValidationFailed = "validation_failed"
+
+// ValidationError menggabungkan error per field, pesan utama nya diambil dari field pertama
+func ValidationError(fields ...FieldError) *Error {
+	message := ErrValidation.Error()
+	if len(fields) == 1 {
+		message = fields[0].Message
+	}
+	return &Error{Kind: ErrValidation, Code: CodeValidationFailed, Message: message, Fields: fields}
+}
+
+// invalidField dipakai untuk input yang salah, field nya ikut dikirim ke client di details
+func invalidField(field string, message string) *Error {
+	return ValidationError(FieldError{Field: field, Message: message})
+}
+
+var (
+	ErrBoardNotFound      = newError(ErrNotFound, "board_not_found", "board not found")
+	ErrUserNotFound       = newError(ErrNotFound, "user_not_found", "user not found")
+	ErrMemberNotFound     = newError(ErrNotFound, "member_not_found", "board member not found")
+	ErrListNotFound       = newError(ErrNotFound, "list_not_found", "list not found")
+	ErrCardNotFound       = newError(ErrNotFound, "card_not_found", "card not found")
+	ErrLabelNotFound      = newError(ErrNotFound, "label_not_found", "label not found")
+	ErrCommentNotFound    = newError(ErrNotFound, "comment_not_found", "comment not found")
+	ErrAttachmentNotFound = newError(ErrNotFound, "attachment_not_found", "attachment not found")
+	ErrThumbnailNotFound  = newError(ErrNotFound, "thumbnail_not_found", "thumbnail not found")
+
+	ErrAssigneeNotFound  = newError(ErrNotFound, "assignee_not_found", "card assignee not found")
+	ErrCardLabelNotFound = newError(ErrNotFound, "card_label_not_found", "card label not found")
+	ErrAlreadyAssigned   = newError(ErrConflict, "already_assigned", "user is already assigned to this card")
+	ErrAssigneeNotMember = newError(ErrValidation, "assignee_not_member", "user is not a member of this board")
+	ErrCommentRemoved    = newError(ErrConflict, "comment_removed", "comment has been removed")
+
+	ErrEmailTaken          = newError(ErrConflict, "email_taken", "email already registered")
+	ErrInvalidCredentials  = newError(ErrUnauthorized, "invalid_credentials", "invalid email or password")
+	ErrInvalidRefreshToken = newError(ErrUnauthorized, "invalid_refresh_token", "invalid refresh token")
+	ErrRefreshTokenReused  = newError(ErrUnauthorized, "refresh_token_reused", "refresh token reuse detected")
+	ErrInvalidVerifyToken  = newError(ErrValidation, "invalid_verification_token", "verification link is invalid or has expired")
+	ErrEmailVerified       = newError(ErrConflict, "email_already_verified", "email is already verified")
+	ErrInvalidResetToken   = newError(ErrValidation, "invalid_reset_token", "password reset link is invalid or has expired")
+	ErrWrongPassword       = newError(ErrValidation, "wrong_current_password", "current password is incorrect")
+	ErrAlreadyMember       = newError(ErrConflict, "already_member", "user is already a board member")
+	ErrOwnerRoleLocked     = newError(ErrConflict, "owner_role_locked", "cannot change the role of the board owner")
+	ErrOwnerNotRemovable   = newError(ErrConflict, "owner_not_removable", "cannot remove the board owner")
+	ErrCrossBoardMove      = newError(ErrValidation, "cross_board_move", "cannot move card to a list on another board")
+	ErrForeignAttachment   = newError(ErrValidation, "foreign_attachment", "attachment does not belong to this card")
+	ErrCoverNotImage       = newError(ErrValidation, "cover_not_image", "cover must be an image")
+	ErrForeignLabel        = newError(ErrValidation, "foreign_label", "label does not belong to this board")
+	ErrInvalidListOrder    = newError(ErrValidation, "invalid_list_order", "list order must contain every list of the board exactly once")
+)
+
+// StaleOrderError dikembalikan saat version urutan yang dikirim client sudah basi
+// Current berisi urutan terbaru supaya client bisa rebase lalu kirim ulang
+type StaleOrderError struct {
+	Current []OrderState
+}
+
+func (e *StaleOrderError) Error() string {
+	return "order has been changed by another user"
+}
+
+func (e *StaleOrderError) Unwrap() error {
+	return ErrConflict
+}
+
+const (
+	QuotaScopeFile  = "file"
+	QuotaScopeBoard = "board"
+	QuotaScopeUser  = "user"
+)
+
+// QuotaExceededError dikembalikan saat upload melebihi batas ukuran file atau quota storage
+// Scope berisi file, board atau user, Used adalah pemakaian sebelum file baru
+type QuotaExceededError struct {
+	Scope string `json:"scope"`
+	Limit int64  `json:"limit"`
+	Used  int64  `json:"used"`
+}
+
+func (e *QuotaExceededError) Error() string {
+	if e.Scope == QuotaScopeFile {
+		return fmt.Sprintf("file exceeds the maximum upload size of %d bytes", e.Limit)
+	}
+	return fmt.Sprintf("%s storage quota exceeded (%d of %d bytes used)", e.Scope, e.Used, e.Limit)
+}
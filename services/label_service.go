@@ -0,0 +1,224 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/odink789/project-management/models"
+	"github.com/odink789/project-management/policies"
+	"github.com/odink789/project-management/repositories"
+	"github.com/odink789/project-management/utils"
+	"gorm.io/gorm"
+)
+
+var hexColorPattern = regexp.MustCompile(`^#([0-9a-f]{3}|[0-9a-f]{6})$`)
+
+type LabelService interface {
+	GetLabels(ctx context.Context, principal *utils.Principal, boardPublicID string) ([]models.Label, error)
+	Create(ctx context.Context, principal *utils.Principal, boardPublicID string, label *models.Label) error
+	Update(ctx context.Context, principal *utils.Principal, boardPublicID string, labelPublicID string, input *models.Label) (*models.Label, error)
+	Delete(ctx context.Context, principal *utils.Principal, boardPublicID string, labelPublicID string) error
+
+	GetCardLabels(ctx context.Context, principal *utils.Principal, cardPublicID string) ([]models.Label, error)
+	Attach(ctx context.Context, principal *utils.Principal, cardPublicID string, labelPublicID string) (*models.Label, error)
+	Detach(ctx context.Context, principal *utils.Principal, cardPublicID string, labelPublicID string) error
+}
+
+type labelService struct {
+	repo      repositories.LabelRepository
+	cardRepo  repositories.CardRepository
+	listRepo  repositories.ListRepository
+	boardRepo repositories.BoardRepository
+	policy    policies.BoardPolicy
+}
+
+func NewLabelService(repo repositories.LabelRepository, cardRepo repositories.CardRepository, listRepo repositories.ListRepository, boardRepo repositories.BoardRepository, policy policies.BoardPolicy) LabelService {
+	return &labelService{repo, cardRepo, listRepo, boardRepo, policy}
+}
+
+func (s *labelService) GetLabels(ctx context.Context, principal *utils.Principal, boardPublicID string) ([]models.Label, error) {
+	board, err := findBoard(ctx, s.boardRepo, boardPublicID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.policy.CanViewBoard(ctx, principal, board); err != nil {
+		return nil, err
+	}
+	return s.repo.FindByBoard(ctx, board.InternalID)
+}
+
+// label adalah pengaturan board, jadi hanya admin board yang boleh mengubah nya
+func (s *labelService) Create(ctx context.Context, principal *utils.Principal, boardPublicID string, label *models.Label) error {
+	board, err := findBoard(ctx, s.boardRepo, boardPublicID)
+	if err != nil {
+		return err
+	}
+
+	if err := s.policy.CanEditBoard(ctx, principal, board); err != nil {
+		return err
+	}
+
+	if err := validateLabel(label); err != nil {
+		return err
+	}
+
+	label.InternalID = 0
+	label.PublicID = uuid.New()
+	label.BoardID = board.InternalID
+	label.CreatedAt = time.Now()
+	return s.repo.Create(ctx, label)
+}
+
+func (s *labelService) Update(ctx context.Context, principal *utils.Principal, boardPublicID string, labelPublicID string, input *models.Label) (*models.Label, error) {
+	board, label, err := s.findBoardLabel(ctx, boardPublicID, labelPublicID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.policy.CanEditBoard(ctx, principal, board); err != nil {
+		return nil, err
+	}
+
+	if err := validateLabel(input); err != nil {
+		return nil, err
+	}
+
+	label.Name = input.Name
+	label.Color = input.Color
+	if err := s.repo.Update(ctx, label); err != nil {
+		return nil, err
+	}
+	return label, nil
+}
+
+func (s *labelService) Delete(ctx context.Context, principal *utils.Principal, boardPublicID string, labelPublicID string) error {
+	board, label, err := s.findBoardLabel(ctx, boardPublicID, labelPublicID)
+	if err != nil {
+		return err
+	}
+
+	if err := s.policy.CanEditBoard(ctx, principal, board); err != nil {
+		return err
+	}
+	return s.repo.Delete(ctx, label)
+}
+
+func (s *labelService) GetCardLabels(ctx context.Context, principal *utils.Principal, cardPublicID string) ([]models.Label, error) {
+	card, _, board, err := findCard(ctx, s.cardRepo, s.listRepo, s.boardRepo, cardPublicID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.policy.CanViewBoard(ctx, principal, board); err != nil {
+		return nil, err
+	}
+	return s.repo.FindByCard(ctx, card.InternalID)
+}
+
+// label yang dipasang harus milik board yang sama dengan card
+func (s *labelService) Attach(ctx context.Context, principal *utils.Principal, cardPublicID string, labelPublicID string) (*models.Label, error) {
+	card, label, err := s.findCardLabel(ctx, principal, cardPublicID, labelPublicID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.Attach(ctx, card.InternalID, label.InternalID); err != nil {
+		return nil, err
+	}
+	return label, nil
+}
+
+func (s *labelService) Detach(ctx context.Context, principal *utils.Principal, cardPublicID string, labelPublicID string) error {
+	card, label, err := s.findCardLabel(ctx, principal, cardPublicID, labelPublicID)
+	if err != nil {
+		return err
+	}
+
+	if err := s.repo.Detach(ctx, card.InternalID, label.InternalID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrCardLabelNotFound
+		}
+		return err
+	}
+	return nil
+}
+
+func (s *labelService) findBoardLabel(ctx context.Context, boardPublicID string, labelPublicID string) (*models.Board, *models.Label, error) {
+	board, err := findBoard(ctx, s.boardRepo, boardPublicID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	label, err := s.findLabel(ctx, labelPublicID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if label.BoardID != board.InternalID {
+		return nil, nil, ErrLabelNotFound
+	}
+	return board, label, nil
+}
+
+func (s *labelService) findCardLabel(ctx context.Context, principal *utils.Principal, cardPublicID string, labelPublicID string) (*models.Card, *models.Label, error) {
+	card, _, board, err := findCard(ctx, s.cardRepo, s.listRepo, s.boardRepo, cardPublicID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if err := s.policy.CanEditCard(ctx, principal, board); err != nil {
+		return nil, nil, err
+	}
+
+	label, err := s.findLabel(ctx, labelPublicID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if label.BoardID != board.InternalID {
+		return nil, nil, ErrForeignLabel
+	}
+	return card, label, nil
+}
+
+func (s *labelService) findLabel(ctx context.Context, publicID string) (*models.Label, error) {
+	if _, err := uuid.Parse(publicID); err != nil {
+		return nil, invalidField("id", "invalid label id")
+	}
+
+	label, err := s.repo.FindByPublicID(ctx, publicID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrLabelNotFound
+		}
+		return nil, err
+	}
+	return label, nil
+}
+
+// name boleh kosong (label hanya warna), color wajib dari palette atau hex
+func validateLabel(label *models.Label) error {
+	label.Name = strings.TrimSpace(label.Name)
+	color, err := normalizeLabelColor(label.Color)
+	if err != nil {
+		return err
+	}
+	label.Color = color
+	return nil
+}
+
+func normalizeLabelColor(color string) (string, error) {
+	color = strings.ToLower(strings.TrimSpace(color))
+	if hexColorPattern.MatchString(color) {
+		return color, nil
+	}
+	for _, c := range models.LabelPalette {
+		if c == color {
+			return color, nil
+		}
+	}
+	return "", invalidField("color", "color must be a palette color or hex (#RGB / #RRGGBB)")
+}
@@ -0,0 +1,32 @@
+package services
+
+import "testing"
+
+func TestNormalizeLabelColor(t *testing.T) {
+	tests := []struct {
+		color   string
+		want    string
+		wantErr bool
+	}{
+		{color: "green", want: "green"},
+		{color: " Blue ", want: "blue"},
+		{color: "#FFF", want: "#fff"},
+		{color: "#1a2B3c", want: "#1a2b3c"},
+		{color: "", wantErr: true},
+		{color: "brown", wantErr: true},
+		{color: "#ffff", wantErr: true},
+		{color: "fff", wantErr: true},
+		{color: "#ggg", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		got, err := normalizeLabelColor(tt.color)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("normalizeLabelColor(%q) error = %v, wantErr %v", tt.color, err, tt.wantErr)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("normalizeLabelColor(%q) = %q, want %q", tt.color, got, tt.want)
+		}
+	}
+}
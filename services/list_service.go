@@ -0,0 +1,174 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/odink789/project-management/models"
+	"github.com/odink789/project-management/models/types"
+	"github.com/odink789/project-management/policies"
+	"github.com/odink789/project-management/repositories"
+	"github.com/odink789/project-management/storage"
+	"github.com/odink789/project-management/utils"
+	"gorm.io/gorm"
+)
+
+type ListService interface {
+	GetLists(ctx context.Context, principal *utils.Principal, boardPublicID string) (*OrderedLists, error)
+	Create(ctx context.Context, principal *utils.Principal, boardPublicID string, list *models.List, index int) error
+	Rename(ctx context.Context, principal *utils.Principal, boardPublicID string, listPublicID string, title string) (*models.List, error)
+	Delete(ctx context.Context, principal *utils.Principal, boardPublicID string, listPublicID string) error
+	Reorder(ctx context.Context, principal *utils.Principal, boardPublicID string, order []uuid.UUID, version *int64) (*OrderedLists, error)
+}
+
+type listService struct {
+	repo      repositories.ListRepository
+	boardRepo repositories.BoardRepository
+	storage   storage.Storage
+	policy    policies.BoardPolicy
+}
+
+func NewListService(repo repositories.ListRepository, boardRepo repositories.BoardRepository, store storage.Storage, policy policies.BoardPolicy) ListService {
+	return &listService{repo, boardRepo, store, policy}
+}
+
+// list dikembalikan sesuai urutan di list_position.list_order
+func (s *listService) GetLists(ctx context.Context, principal *utils.Principal, boardPublicID string) (*OrderedLists, error) {
+	board, err := findBoard(ctx, s.boardRepo, boardPublicID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.policy.CanViewBoard(ctx, principal, board); err != nil {
+		return nil, err
+	}
+	return s.orderedLists(ctx, board.InternalID)
+}
+
+func (s *listService) Create(ctx context.Context, principal *utils.Principal, boardPublicID string, list *models.List, index int) error {
+	board, err := findBoard(ctx, s.boardRepo, boardPublicID)
+	if err != nil {
+		return err
+	}
+
+	if err := s.policy.CanEditList(ctx, principal, board); err != nil {
+		return err
+	}
+
+	list.Tittle = strings.TrimSpace(list.Tittle)
+	if list.Tittle == "" {
+		return invalidField("title", "title is required")
+	}
+
+	list.PublicID = uuid.New()
+	list.BoardInternalID = board.InternalID
+	list.BoardPublicID = board.PublicID
+	list.CreatedAt = time.Now()
+	return s.repo.Create(ctx, list, index)
+}
+
+func (s *listService) Rename(ctx context.Context, principal *utils.Principal, boardPublicID string, listPublicID string, title string) (*models.List, error) {
+	_, list, err := s.findList(ctx, principal, boardPublicID, listPublicID)
+	if err != nil {
+		return nil, err
+	}
+
+	title = strings.TrimSpace(title)
+	if title == "" {
+		return nil, invalidField("title", "title is required")
+	}
+
+	list.Tittle = title
+	if err := s.repo.Update(ctx, list); err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+func (s *listService) Delete(ctx context.Context, principal *utils.Principal, boardPublicID string, listPublicID string) error {
+	_, list, err := s.findList(ctx, principal, boardPublicID, listPublicID)
+	if err != nil {
+		return err
+	}
+
+	files, err := s.repo.Delete(ctx, list)
+	if err != nil {
+		return err
+	}
+	removeFiles(s.storage, files...)
+	return nil
+}
+
+// version opsional, kalau dikirim dan sudah basi service mengembalikan *StaleOrderError
+func (s *listService) Reorder(ctx context.Context, principal *utils.Principal, boardPublicID string, order []uuid.UUID, version *int64) (*OrderedLists, error) {
+	board, err := findBoard(ctx, s.boardRepo, boardPublicID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.policy.CanEditList(ctx, principal, board); err != nil {
+		return nil, err
+	}
+
+	if _, err := s.repo.Reorder(ctx, board.InternalID, types.UUIDArray(order), version); err != nil {
+		if errors.Is(err, repositories.ErrStaleVersion) {
+			_, position, findErr := s.repo.FindOrdered(ctx, board.InternalID)
+			if findErr != nil {
+				return nil, findErr
+			}
+			return nil, &StaleOrderError{Current: []OrderState{listOrderState(board, position)}}
+		}
+		if errors.Is(err, repositories.ErrInvalidListOrder) {
+			return nil, ErrInvalidListOrder
+		}
+		return nil, err
+	}
+	return s.orderedLists(ctx, board.InternalID)
+}
+
+func (s *listService) orderedLists(ctx context.Context, boardID int64) (*OrderedLists, error) {
+	lists, position, err := s.repo.FindOrdered(ctx, boardID)
+	if err != nil {
+		return nil, err
+	}
+	return &OrderedLists{Version: position.Version, Lists: lists}, nil
+}
+
+// findList memastikan list memang milik board yang ada di url dan user boleh mengubah nya
+func (s *listService) findList(ctx context.Context, principal *utils.Principal, boardPublicID string, listPublicID string) (*models.Board, *models.List, error) {
+	board, err := findBoard(ctx, s.boardRepo, boardPublicID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if err := s.policy.CanEditList(ctx, principal, board); err != nil {
+		return nil, nil, err
+	}
+
+	list, err := findList(ctx, s.repo, listPublicID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if list.BoardInternalID != board.InternalID {
+		return nil, nil, ErrListNotFound
+	}
+	return board, list, nil
+}
+
+func findList(ctx context.Context, repo repositories.ListRepository, publicID string) (*models.List, error) {
+	if _, err := uuid.Parse(publicID); err != nil {
+		return nil, invalidField("id", "invalid list id")
+	}
+
+	list, err := repo.FindByPublicID(ctx, publicID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrListNotFound
+		}
+		return nil, err
+	}
+	return list, nil
+}
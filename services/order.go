@@ -0,0 +1,48 @@
+package services
+
+import (
+	"github.com/google/uuid"
+	"github.com/odink789/project-management/models"
+)
+
+// OrderState adalah urutan list di sebuah board (BoardID) atau urutan card di sebuah list (ListID)
+// beserta version nya. version opsional dikirim balik saat reorder / move, kalau dikirim dan sudah basi
+// request ditolak dengan *StaleOrderError
+// hasil move di mode rank tidak membawa order, cukup version dan rank card yang dipindah
+type OrderState struct {
+	BoardID *uuid.UUID  `json:"board_id,omitempty"`
+	ListID  *uuid.UUID  `json:"list_id,omitempty"`
+	Version int64       `json:"version"`
+	Order   []uuid.UUID `json:"order,omitzero"`
+}
+
+type OrderedLists struct {
+	Version int64         `json:"version"`
+	Lists   []models.List `json:"lists"`
+}
+
+type OrderedCards struct {
+	Version int64         `json:"version"`
+	Cards   []models.Card `json:"cards"`
+}
+
+type CardMoveResult struct {
+	Card   *models.Card `json:"card"`
+	Orders []OrderState `json:"orders"`
+}
+
+func listOrderState(board *models.Board, position *models.ListPosition) OrderState {
+	return OrderState{
+		BoardID: &board.PublicID,
+		Version: position.Version,
+		Order:   position.ListOrder,
+	}
+}
+
+func cardOrderState(list *models.List, position *models.CardPosition) OrderState {
+	return OrderState{
+		ListID:  &list.PublicID,
+		Version: position.Version,
+		Order:   position.CardOrder,
+	}
+}
@@ -1,46 +1,469 @@
 package services
 
 import (
+	"context"
 	"errors"
+	"log"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
 
 	"github.com/google/uuid"
+	"github.com/odink789/project-management/mailer"
 	"github.com/odink789/project-management/models"
 	"github.com/odink789/project-management/repositories"
+	"github.com/odink789/project-management/utils"
+	"gorm.io/gorm"
 )
 
 //service ini adalah logika bisnis nya
 
 type UserService interface {
-	Register(user *models.User) error
+	Register(ctx context.Context, user *models.User) error
+	Login(ctx context.Context, email, password, deviceLabel string) (*models.User, *AuthToken, error)
+	RefreshToken(ctx context.Context, refreshToken string) (*AuthToken, error)
+	Logout(ctx context.Context, userID int64, refreshToken string) error
+	LogoutAll(ctx context.Context, userID int64) error
+	Sessions(ctx context.Context, userID int64) ([]models.RefreshToken, error)
+	VerifyEmail(ctx context.Context, token string) error
+	ResendVerification(ctx context.Context, userID int64) error
+	ForgotPassword(ctx context.Context, email string) error
+	ResetPassword(ctx context.Context, token, password string) error
+	ChangePassword(ctx context.Context, userID int64, currentPassword, newPassword string) error
+	Close()
+	Wait()
+}
+
+type AuthToken struct {
+	AccessToken  string `json:"access_token"`
+	RefreshToken string `json:"refresh_token"`
+}
+
+// AccountEmail adalah pengaturan email akun, link di email mengarah ke AppURL (frontend)
+type AccountEmail struct {
+	AppURL          string
+	VerificationTTL time.Duration
+	ResetTTL        time.Duration
 }
 
 type userService struct {
-	repo repositories.UserRepository
+	repo          repositories.UserRepository
+	tokenRepo     repositories.RefreshTokenRepository
+	userTokenRepo repositories.UserTokenRepository
+	transactor    repositories.Transactor
+	mailer        mailer.Mailer
+	email         AccountEmail
+	background    *backgroundMail
+}
+
+func NewUserService(repo repositories.UserRepository, tokenRepo repositories.RefreshTokenRepository, userTokenRepo repositories.UserTokenRepository, transactor repositories.Transactor, mail mailer.Mailer, email AccountEmail) UserService {
+	return &userService{repo, tokenRepo, userTokenRepo, transactor, mail, email, newBackgroundMail(maxBackgroundMails)}
+}
+
+const (
+	// batas waktu kirim email yang berjalan di background, supaya goroutine nya tidak menggantung
+	backgroundMailTimeout = time.Minute
+	// jumlah email yang boleh dikirim bersamaan di background, sisanya dilewati
+	maxBackgroundMails = 16
+)
+
+// backgroundMail menjalankan pengiriman email di background dengan jumlah goroutine yang dibatasi
+// seperti antrian thumbnail, kalau semua slot terpakai email dilewati dan hanya di log
+type backgroundMail struct {
+	slots  chan struct{}
+	mu     sync.Mutex
+	closed bool
+	wg     sync.WaitGroup
+}
+
+func newBackgroundMail(size int) *backgroundMail {
+	return &backgroundMail{slots: make(chan struct{}, size)}
+}
+
+// ctx request sudah selesai saat goroutine berjalan, jadi value nya saja yang dipakai
+func (b *backgroundMail) run(ctx context.Context, name string, send func(ctx context.Context) error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.closed {
+		log.Println("Mail sender is closed, skipping", name)
+		return
+	}
+	select {
+	case b.slots <- struct{}{}:
+	default:
+		log.Println("Mail queue is full, skipping", name)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), backgroundMailTimeout)
+	b.wg.Add(1)
+	go func() {
+		defer b.wg.Done()
+		defer func() { <-b.slots }()
+		defer cancel()
+		if err := send(ctx); err != nil {
+			log.Println("Failed to send", name, err)
+		}
+	}()
+}
+
+// Close berhenti menerima email baru, email yang sedang dikirim tetap ditunggu lewat Wait
+func (s *userService) Close() {
+	s.background.mu.Lock()
+	s.background.closed = true
+	s.background.mu.Unlock()
 }
 
-func NewUserService(repo repositories.UserRepository) UserService {
-	return &userService{repo}
+// Wait menunggu email yang sedang dikirim di background, paling lama backgroundMailTimeout
+func (s *userService) Wait() {
+	s.background.wg.Wait()
 }
 
-func (s *userService) Register(user *models.User) error {
+func (s *userService) Register(ctx context.Context, user *models.User) error {
 	//kita harus mengecek email yang terdaftar apakah sudah di pakai atau blm
 	//hasing password
 	//set role
 	//simpan user
 
-	existingUser, _ := s.repo.FindByEmail(user.Email)
-	if existingUser.InternalID != 0 {
-		return errors.New("email already registered")
+	existingUser, err := s.repo.FindByEmail(ctx, user.Email)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return err
+	}
+	if err == nil && existingUser.InternalID != 0 {
+		return ErrEmailTaken
 	}
 
-	hased, err := utils.HashPassword(user.password)
+	hased, err := utils.HashPassword(user.Password)
 	if err != nil {
 		return err
 	}
 
 	user.Password = hased
-	user.Role = "user"
-	user.PublicId = uuid.New()
-	return s.repo.Create(user)
+	user.Role = models.RoleUser
+	user.PublicID = uuid.New()
+	user.EmailVerifiedAt = nil
+	if err := s.repo.Create(ctx, user); err != nil {
+		return err
+	}
+
+	//email dikirim di background seperti ForgotPassword, akun tetap terbuat walaupun email gagal dikirim
+	//user bisa login lalu minta kirim ulang, route selain /v1/auth butuh email terverifikasi
+	//user disalin karena pointer nya masih dipakai controller untuk response
+	created := *user
+	s.background.run(ctx, "verification email to "+user.Email, func(ctx context.Context) error {
+		return s.sendVerification(ctx, &created)
+	})
+	return nil
+}
+
+func (s *userService) Login(ctx context.Context, email, password, deviceLabel string) (*models.User, *AuthToken, error) {
+	//pesan error sengaja disamakan supaya tidak bocor email mana yang terdaftar
+	user, err := s.repo.FindByEmail(ctx, email)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil, ErrInvalidCredentials
+		}
+		return nil, nil, err
+	}
+
+	if !utils.CheckPasswordHash(password, user.Password) {
+		return nil, nil, ErrInvalidCredentials
+	}
+
+	accessToken, refreshToken, err := s.newTokenPair(ctx, user, uuid.New(), deviceLabel)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if err := s.tokenRepo.Create(ctx, refreshToken); err != nil {
+		return nil, nil, err
+	}
+	return user, accessToken, nil
+}
+
+func (s *userService) RefreshToken(ctx context.Context, refreshToken string) (*AuthToken, error) {
+	if _, err := utils.ParseRefreshToken(refreshToken); err != nil {
+		return nil, ErrInvalidRefreshToken
+	}
+
+	stored, err := s.tokenRepo.FindByHash(ctx, utils.HashToken(refreshToken))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrInvalidRefreshToken
+		}
+		return nil, err
+	}
+
+	//token yang sudah dirotasi dipakai lagi, kemungkinan dicuri
+	//revoke seluruh family supaya pencuri dan pemilik asli sama-sama harus login ulang
+	if stored.RevokedAt != nil {
+		if err := s.tokenRepo.RevokeFamily(ctx, stored.FamilyID); err != nil {
+			return nil, err
+		}
+		return nil, ErrRefreshTokenReused
+	}
+
+	if time.Now().After(stored.ExpiresAt) {
+		return nil, ErrInvalidRefreshToken
+	}
+
+	user, err := s.repo.FindByID(ctx, stored.UserID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrInvalidRefreshToken
+		}
+		return nil, err
+	}
+
+	token, next, err := s.newTokenPair(ctx, user, stored.FamilyID, stored.DeviceLabel)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.tokenRepo.Rotate(ctx, stored, next); err != nil {
+		if errors.Is(err, repositories.ErrRefreshTokenRevoked) {
+			if err := s.tokenRepo.RevokeFamily(ctx, stored.FamilyID); err != nil {
+				return nil, err
+			}
+			return nil, ErrRefreshTokenReused
+		}
+		return nil, err
+	}
+	return token, nil
+}
+
+//access token tetap valid sampai expired, logout hanya mematikan refresh token nya
+
+func (s *userService) Logout(ctx context.Context, userID int64, refreshToken string) error {
+	stored, err := s.tokenRepo.FindByHash(ctx, utils.HashToken(refreshToken))
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return err
+	}
+	if err != nil || stored.UserID != userID {
+		return ErrInvalidRefreshToken
+	}
+	return s.tokenRepo.RevokeFamily(ctx, stored.FamilyID)
+}
+
+func (s *userService) LogoutAll(ctx context.Context, userID int64) error {
+	return s.tokenRepo.RevokeAllByUser(ctx, userID)
+}
+
+func (s *userService) Sessions(ctx context.Context, userID int64) ([]models.RefreshToken, error) {
+	return s.tokenRepo.FindActiveByUser(ctx, userID)
+}
+
+func (s *userService) newTokenPair(ctx context.Context, user *models.User, familyID uuid.UUID, deviceLabel string) (*AuthToken, *models.RefreshToken, error) {
+	accessToken, err := utils.GenerateToken(user.InternalID, user.Role, user.Email, user.PublicID, user.EmailVerifiedAt != nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	refreshToken, expiresAt, err := utils.GenerateRefreshToken(user.InternalID, user.PublicID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	stored := &models.RefreshToken{
+		PublicID:    uuid.New(),
+		UserID:      user.InternalID,
+		FamilyID:    familyID,
+		TokenHash:   utils.HashToken(refreshToken),
+		DeviceLabel: deviceLabel,
+		ExpiresAt:   expiresAt,
+	}
+	return &AuthToken{AccessToken: accessToken, RefreshToken: refreshToken}, stored, nil
+}
+
+func (s *userService) VerifyEmail(ctx context.Context, token string) error {
+	return s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		user, err := s.consumeToken(ctx, models.TokenPurposeEmailVerification, token, ErrInvalidVerifyToken)
+		if err != nil {
+			return err
+		}
+		if user.EmailVerifiedAt != nil {
+			return nil
+		}
+		return s.repo.MarkEmailVerified(ctx, user)
+	})
+}
+
+func (s *userService) ResendVerification(ctx context.Context, userID int64) error {
+	user, err := s.repo.FindByID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrUserNotFound
+		}
+		return err
+	}
+	if user.EmailVerifiedAt != nil {
+		return ErrEmailVerified
+	}
+	return s.sendVerification(ctx, user)
+}
+
+// ForgotPassword selalu berhasil untuk email yang tidak terdaftar supaya tidak bocor email mana yang ada
+// token dan email dikerjakan di background, jadi email terdaftar maupun tidak dijawab sama cepat
+// dan gagal buat token atau kirim email hanya di log dengan alasan yang sama
+func (s *userService) ForgotPassword(ctx context.Context, email string) error {
+	user, err := s.repo.FindByEmail(ctx, email)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil
+		}
+		return err
+	}
+
+	s.background.run(ctx, "password reset email to "+user.Email, func(ctx context.Context) error {
+		return s.sendPasswordReset(ctx, user)
+	})
+	return nil
+}
+
+func (s *userService) sendPasswordReset(ctx context.Context, user *models.User) error {
+	token, err := s.issueToken(ctx, user, models.TokenPurposePasswordReset, s.email.ResetTTL)
+	if err != nil {
+		return err
+	}
+	msg, err := mailer.NewMessage(user.Email, "Reset your password", "reset_password", mailer.TemplateData{
+		Name:      user.Name,
+		URL:       s.emailLink("/reset-password", token),
+		ExpiresIn: humanDuration(s.email.ResetTTL),
+	})
+	if err != nil {
+		return err
+	}
+	return s.mailer.Send(ctx, msg)
+}
+
+// ResetPassword mengganti password lalu mematikan semua sesi, pemegang refresh token lama harus login ulang
+func (s *userService) ResetPassword(ctx context.Context, token, password string) error {
+	hashed, err := utils.HashPassword(password)
+	if err != nil {
+		return err
+	}
+
+	return s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		user, err := s.consumeToken(ctx, models.TokenPurposePasswordReset, token, ErrInvalidResetToken)
+		if err != nil {
+			return err
+		}
+		if err := s.repo.UpdatePassword(ctx, user, hashed); err != nil {
+			return err
+		}
+		//link reset diterima lewat email, berarti email nya juga terbukti milik user
+		if user.EmailVerifiedAt == nil {
+			if err := s.repo.MarkEmailVerified(ctx, user); err != nil {
+				return err
+			}
+		}
+		return s.tokenRepo.RevokeAllByUser(ctx, user.InternalID)
+	})
+}
 
+func (s *userService) ChangePassword(ctx context.Context, userID int64, currentPassword, newPassword string) error {
+	user, err := s.repo.FindByID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrUserNotFound
+		}
+		return err
+	}
+	if !utils.CheckPasswordHash(currentPassword, user.Password) {
+		return ErrWrongPassword
+	}
+
+	hashed, err := utils.HashPassword(newPassword)
+	if err != nil {
+		return err
+	}
+	return s.repo.UpdatePassword(ctx, user, hashed)
+}
+
+// consumeToken menandai token email sudah dipakai dan mengembalikan pemilik nya
+// token yang tidak ada, kadaluarsa atau sudah dipakai dikembalikan sebagai invalid
+func (s *userService) consumeToken(ctx context.Context, purpose, token string, invalid error) (*models.User, error) {
+	stored, err := s.userTokenRepo.FindByHash(ctx, purpose, utils.HashToken(token))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, invalid
+		}
+		return nil, err
+	}
+	if stored.UsedAt != nil || time.Now().After(stored.ExpiresAt) {
+		return nil, invalid
+	}
+
+	if err := s.userTokenRepo.Consume(ctx, stored); err != nil {
+		if errors.Is(err, repositories.ErrUserTokenUsed) {
+			return nil, invalid
+		}
+		return nil, err
+	}
+
+	user, err := s.repo.FindByID(ctx, stored.UserID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, invalid
+		}
+		return nil, err
+	}
+	return user, nil
+}
+
+// sendVerification membuat token baru dan mengirim link nya, token lama yang belum dipakai tidak berlaku lagi
+func (s *userService) sendVerification(ctx context.Context, user *models.User) error {
+	token, err := s.issueToken(ctx, user, models.TokenPurposeEmailVerification, s.email.VerificationTTL)
+	if err != nil {
+		return err
+	}
+
+	msg, err := mailer.NewMessage(user.Email, "Verify your email", "verify_email", mailer.TemplateData{
+		Name:      user.Name,
+		URL:       s.emailLink("/verify-email", token),
+		ExpiresIn: humanDuration(s.email.VerificationTTL),
+	})
+	if err != nil {
+		return err
+	}
+	return s.mailer.Send(ctx, msg)
+}
+
+func (s *userService) issueToken(ctx context.Context, user *models.User, purpose string, ttl time.Duration) (string, error) {
+	if err := s.userTokenRepo.InvalidateByUser(ctx, user.InternalID, purpose); err != nil {
+		return "", err
+	}
+
+	token, err := utils.GenerateRandomToken()
+	if err != nil {
+		return "", err
+	}
+	stored := &models.UserToken{
+		UserID:    user.InternalID,
+		Purpose:   purpose,
+		TokenHash: utils.HashToken(token),
+		ExpiresAt: time.Now().Add(ttl),
+	}
+	if err := s.userTokenRepo.Create(ctx, stored); err != nil {
+		return "", err
+	}
+	return token, nil
+}
+
+func (s *userService) emailLink(path, token string) string {
+	return strings.TrimRight(s.email.AppURL, "/") + path + "?token=" + url.QueryEscape(token)
+}
+
+// humanDuration dipakai di isi email, contoh : 24 hours, 30 minutes
+func humanDuration(d time.Duration) string {
+	value, unit := int(d.Minutes()), "minute"
+	if d >= time.Hour && d%time.Hour == 0 {
+		value, unit = int(d.Hours()), "hour"
+	}
+	if value != 1 {
+		unit += "s"
+	}
+	return strconv.Itoa(value) + " " + unit
 }
@@ -0,0 +1,621 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/odink789/project-management/config"
+	"github.com/odink789/project-management/mailer"
+	"github.com/odink789/project-management/models"
+	"github.com/odink789/project-management/repositories"
+	"github.com/odink789/project-management/utils"
+	"gorm.io/gorm"
+)
+
+type fakeUserRepo struct {
+	users []*models.User
+}
+
+func (r *fakeUserRepo) Create(ctx context.Context, user *models.User) error {
+	user.InternalID = int64(len(r.users) + 1)
+	r.users = append(r.users, user)
+	return nil
+}
+
+func (r *fakeUserRepo) FindByEmail(ctx context.Context, email string) (*models.User, error) {
+	for _, u := range r.users {
+		if u.Email == email {
+			return u, nil
+		}
+	}
+	return &models.User{}, gorm.ErrRecordNotFound
+}
+
+func (r *fakeUserRepo) FindByPublicID(ctx context.Context, publicID string) (*models.User, error) {
+	for _, u := range r.users {
+		if u.PublicID.String() == publicID {
+			return u, nil
+		}
+	}
+	return &models.User{}, gorm.ErrRecordNotFound
+}
+
+func (r *fakeUserRepo) FindByID(ctx context.Context, id int64) (*models.User, error) {
+	for _, u := range r.users {
+		if u.InternalID == id {
+			return u, nil
+		}
+	}
+	return &models.User{}, gorm.ErrRecordNotFound
+}
+
+func (r *fakeUserRepo) UpdatePassword(ctx context.Context, user *models.User, hash string) error {
+	user.Password = hash
+	return nil
+}
+
+func (r *fakeUserRepo) MarkEmailVerified(ctx context.Context, user *models.User) error {
+	now := time.Now()
+	user.EmailVerifiedAt = &now
+	return nil
+}
+
+type fakeRefreshTokenRepo struct {
+	tokens []*models.RefreshToken
+}
+
+func (r *fakeRefreshTokenRepo) Create(ctx context.Context, token *models.RefreshToken) error {
+	token.InternalID = int64(len(r.tokens) + 1)
+	r.tokens = append(r.tokens, token)
+	return nil
+}
+
+func (r *fakeRefreshTokenRepo) FindByHash(ctx context.Context, hash string) (*models.RefreshToken, error) {
+	for _, t := range r.tokens {
+		if t.TokenHash == hash {
+			copied := *t
+			return &copied, nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (r *fakeRefreshTokenRepo) FindActiveByUser(ctx context.Context, userID int64) ([]models.RefreshToken, error) {
+	var result []models.RefreshToken
+	for _, t := range r.tokens {
+		if t.UserID == userID && t.RevokedAt == nil {
+			result = append(result, *t)
+		}
+	}
+	return result, nil
+}
+
+func (r *fakeRefreshTokenRepo) Rotate(ctx context.Context, old *models.RefreshToken, next *models.RefreshToken) error {
+	for _, t := range r.tokens {
+		if t.InternalID == old.InternalID {
+			if t.RevokedAt != nil {
+				return repositories.ErrRefreshTokenRevoked
+			}
+			now := time.Now()
+			t.RevokedAt = &now
+		}
+	}
+	return r.Create(ctx, next)
+}
+
+func (r *fakeRefreshTokenRepo) RevokeFamily(ctx context.Context, familyID uuid.UUID) error {
+	now := time.Now()
+	for _, t := range r.tokens {
+		if t.FamilyID == familyID && t.RevokedAt == nil {
+			t.RevokedAt = &now
+		}
+	}
+	return nil
+}
+
+func (r *fakeRefreshTokenRepo) RevokeAllByUser(ctx context.Context, userID int64) error {
+	now := time.Now()
+	for _, t := range r.tokens {
+		if t.UserID == userID && t.RevokedAt == nil {
+			t.RevokedAt = &now
+		}
+	}
+	return nil
+}
+
+type fakeUserTokenRepo struct {
+	tokens []*models.UserToken
+	err    error
+}
+
+func (r *fakeUserTokenRepo) Create(ctx context.Context, token *models.UserToken) error {
+	if r.err != nil {
+		return r.err
+	}
+	token.InternalID = int64(len(r.tokens) + 1)
+	r.tokens = append(r.tokens, token)
+	return nil
+}
+
+func (r *fakeUserTokenRepo) FindByHash(ctx context.Context, purpose, hash string) (*models.UserToken, error) {
+	for _, t := range r.tokens {
+		if t.Purpose == purpose && t.TokenHash == hash {
+			copied := *t
+			return &copied, nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (r *fakeUserTokenRepo) Consume(ctx context.Context, token *models.UserToken) error {
+	for _, t := range r.tokens {
+		if t.InternalID == token.InternalID {
+			if t.UsedAt != nil {
+				return repositories.ErrUserTokenUsed
+			}
+			now := time.Now()
+			t.UsedAt = &now
+		}
+	}
+	return nil
+}
+
+func (r *fakeUserTokenRepo) InvalidateByUser(ctx context.Context, userID int64, purpose string) error {
+	now := time.Now()
+	for _, t := range r.tokens {
+		if t.UserID == userID && t.Purpose == purpose && t.UsedAt == nil {
+			t.UsedAt = &now
+		}
+	}
+	return nil
+}
+
+type fakeTransactor struct{}
+
+func (fakeTransactor) WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	return fn(ctx)
+}
+
+type fakeMailer struct {
+	sent []mailer.Message
+	err  error
+}
+
+func (m *fakeMailer) Send(ctx context.Context, msg mailer.Message) error {
+	if m.err != nil {
+		return m.err
+	}
+	m.sent = append(m.sent, msg)
+	return nil
+}
+
+var mailTokenPattern = regexp.MustCompile(`token=([A-Za-z0-9_-]+)`)
+
+// lastToken mengambil token dari link di email terakhir yang dikirim
+func (m *fakeMailer) lastToken(t *testing.T) string {
+	t.Helper()
+	if len(m.sent) == 0 {
+		t.Fatal("no email sent")
+	}
+	match := mailTokenPattern.FindStringSubmatch(m.sent[len(m.sent)-1].Text)
+	if match == nil {
+		t.Fatalf("no token in email %q", m.sent[len(m.sent)-1].Text)
+	}
+	return match[1]
+}
+
+type userServiceFixture struct {
+	svc        UserService
+	users      *fakeUserRepo
+	tokenRepo  *fakeRefreshTokenRepo
+	userTokens *fakeUserTokenRepo
+	mail       *fakeMailer
+}
+
+// forgotPassword menunggu email reset yang dikirim di background
+func (f *userServiceFixture) forgotPassword(ctx context.Context, email string) error {
+	err := f.svc.ForgotPassword(ctx, email)
+	f.svc.Wait()
+	return err
+}
+
+func setupUserService(t *testing.T) *userServiceFixture {
+	t.Helper()
+	config.AppConfig = &config.Config{
+		JWTSecret:        "test-secret",
+		JWTExpire:        15 * time.Minute,
+		JWTRefreshExpire: 24 * time.Hour,
+	}
+
+	f := &userServiceFixture{users: &fakeUserRepo{}, tokenRepo: &fakeRefreshTokenRepo{}, userTokens: &fakeUserTokenRepo{}, mail: &fakeMailer{}}
+	email := AccountEmail{AppURL: "http://localhost:5173/", VerificationTTL: 24 * time.Hour, ResetTTL: time.Hour}
+	f.svc = NewUserService(f.users, f.tokenRepo, f.userTokens, fakeTransactor{}, f.mail, email)
+
+	if err := f.svc.Register(context.Background(), &models.User{Name: "Budi", Email: "budi@example.com", Password: "rahasia123"}); err != nil {
+		t.Fatalf("Register() error = %v", err)
+	}
+	//email verifikasi dikirim di background
+	f.svc.Wait()
+	return f
+}
+
+func newTestUserService(t *testing.T) (UserService, *fakeRefreshTokenRepo) {
+	t.Helper()
+	f := setupUserService(t)
+	return f.svc, f.tokenRepo
+}
+
+func TestUserService_LoginWrongPassword(t *testing.T) {
+	svc, _ := newTestUserService(t)
+
+	if _, _, err := svc.Login(context.Background(), "budi@example.com", "salah", "test"); err == nil {
+		t.Fatal("expected error for wrong password")
+	}
+	if _, _, err := svc.Login(context.Background(), "tidakada@example.com", "rahasia123", "test"); err == nil {
+		t.Fatal("expected error for unknown email")
+	}
+}
+
+func TestUserService_RefreshRotatesToken(t *testing.T) {
+	svc, tokenRepo := newTestUserService(t)
+
+	_, first, err := svc.Login(context.Background(), "budi@example.com", "rahasia123", "laptop")
+	if err != nil {
+		t.Fatalf("Login() error = %v", err)
+	}
+
+	second, err := svc.RefreshToken(context.Background(), first.RefreshToken)
+	if err != nil {
+		t.Fatalf("RefreshToken() error = %v", err)
+	}
+	if second.RefreshToken == first.RefreshToken {
+		t.Error("refresh token should be rotated")
+	}
+
+	sessions, _ := tokenRepo.FindActiveByUser(context.Background(), 1)
+	if len(sessions) != 1 {
+		t.Fatalf("active sessions = %d, want 1", len(sessions))
+	}
+	if sessions[0].DeviceLabel != "laptop" {
+		t.Errorf("device label = %q, want laptop", sessions[0].DeviceLabel)
+	}
+}
+
+func TestUserService_RefreshReuseRevokesFamily(t *testing.T) {
+	svc, tokenRepo := newTestUserService(t)
+
+	_, first, err := svc.Login(context.Background(), "budi@example.com", "rahasia123", "laptop")
+	if err != nil {
+		t.Fatalf("Login() error = %v", err)
+	}
+	_, other, err := svc.Login(context.Background(), "budi@example.com", "rahasia123", "hp")
+	if err != nil {
+		t.Fatalf("Login() error = %v", err)
+	}
+
+	second, err := svc.RefreshToken(context.Background(), first.RefreshToken)
+	if err != nil {
+		t.Fatalf("RefreshToken() error = %v", err)
+	}
+
+	//replay token lama
+	if _, err := svc.RefreshToken(context.Background(), first.RefreshToken); err == nil {
+		t.Fatal("expected error when reusing rotated refresh token")
+	}
+
+	if _, err := svc.RefreshToken(context.Background(), second.RefreshToken); err == nil {
+		t.Error("token from the same family should be revoked after reuse")
+	}
+
+	if _, err := svc.RefreshToken(context.Background(), other.RefreshToken); err != nil {
+		t.Errorf("other session should not be affected, got %v", err)
+	}
+
+	sessions, _ := tokenRepo.FindActiveByUser(context.Background(), 1)
+	if len(sessions) != 1 || sessions[0].DeviceLabel != "hp" {
+		t.Errorf("unexpected active sessions %+v", sessions)
+	}
+}
+
+func TestUserService_LogoutAll(t *testing.T) {
+	svc, _ := newTestUserService(t)
+
+	_, first, _ := svc.Login(context.Background(), "budi@example.com", "rahasia123", "laptop")
+	_, second, _ := svc.Login(context.Background(), "budi@example.com", "rahasia123", "hp")
+
+	if err := svc.LogoutAll(context.Background(), 1); err != nil {
+		t.Fatalf("LogoutAll() error = %v", err)
+	}
+
+	for _, token := range []*AuthToken{first, second} {
+		if _, err := svc.RefreshToken(context.Background(), token.RefreshToken); err == nil {
+			t.Error("expected refresh to fail after logout-all")
+		}
+	}
+}
+
+func TestUserService_LogoutOtherUsersToken(t *testing.T) {
+	svc, _ := newTestUserService(t)
+
+	_, token, _ := svc.Login(context.Background(), "budi@example.com", "rahasia123", "laptop")
+
+	err := svc.Logout(context.Background(), 99, token.RefreshToken)
+	if err == nil {
+		t.Fatal("expected error when logging out a token owned by another user")
+	}
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		t.Error("error should not leak repository errors")
+	}
+
+	if _, err := utils.ParseRefreshToken(token.RefreshToken); err != nil {
+		t.Fatalf("token should still be valid: %v", err)
+	}
+	if _, err := svc.RefreshToken(context.Background(), token.RefreshToken); err != nil {
+		t.Errorf("session should remain active, got %v", err)
+	}
+}
+
+func TestUserService_VerifyEmail(t *testing.T) {
+	f := setupUserService(t)
+	ctx := context.Background()
+
+	if len(f.mail.sent) != 1 || f.mail.sent[0].To != "budi@example.com" {
+		t.Fatalf("sent = %+v, want one verification email", f.mail.sent)
+	}
+	if !strings.Contains(f.mail.sent[0].Text, "http://localhost:5173/verify-email?token=") || !strings.Contains(f.mail.sent[0].Text, "24 hours") {
+		t.Errorf("email text = %q", f.mail.sent[0].Text)
+	}
+	if f.users.users[0].EmailVerifiedAt != nil {
+		t.Fatal("new account should not be verified")
+	}
+
+	token := f.mail.lastToken(t)
+	if err := f.svc.VerifyEmail(ctx, "bukan-token"); !errors.Is(err, ErrInvalidVerifyToken) {
+		t.Errorf("VerifyEmail() with unknown token error = %v", err)
+	}
+	if err := f.svc.VerifyEmail(ctx, token); err != nil {
+		t.Fatalf("VerifyEmail() error = %v", err)
+	}
+	if f.users.users[0].EmailVerifiedAt == nil {
+		t.Error("email should be verified")
+	}
+	if err := f.svc.VerifyEmail(ctx, token); !errors.Is(err, ErrInvalidVerifyToken) {
+		t.Errorf("VerifyEmail() twice error = %v, token must be single use", err)
+	}
+	if err := f.svc.ResendVerification(ctx, 1); !errors.Is(err, ErrEmailVerified) {
+		t.Errorf("ResendVerification() after verify error = %v", err)
+	}
+}
+
+func TestUserService_ResendVerificationInvalidatesOldToken(t *testing.T) {
+	f := setupUserService(t)
+	ctx := context.Background()
+
+	old := f.mail.lastToken(t)
+	if err := f.svc.ResendVerification(ctx, 1); err != nil {
+		t.Fatalf("ResendVerification() error = %v", err)
+	}
+	fresh := f.mail.lastToken(t)
+	if fresh == old {
+		t.Fatal("resend should issue a new token")
+	}
+
+	if err := f.svc.VerifyEmail(ctx, old); !errors.Is(err, ErrInvalidVerifyToken) {
+		t.Errorf("VerifyEmail() with old token error = %v", err)
+	}
+	if err := f.svc.VerifyEmail(ctx, fresh); err != nil {
+		t.Errorf("VerifyEmail() with new token error = %v", err)
+	}
+}
+
+func TestUserService_AccessTokenCarriesEmailVerified(t *testing.T) {
+	f := setupUserService(t)
+	ctx := context.Background()
+
+	emailVerified := func(token string) interface{} {
+		t.Helper()
+		claims, err := utils.ParseToken(token)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return claims["email_verified"]
+	}
+
+	_, tokens, err := f.svc.Login(ctx, "budi@example.com", "rahasia123", "laptop")
+	if err != nil {
+		t.Fatalf("Login() error = %v", err)
+	}
+	if got := emailVerified(tokens.AccessToken); got != false {
+		t.Errorf("email_verified before verification = %v, want false", got)
+	}
+
+	//setelah verifikasi, token hasil refresh sudah membawa status terbaru
+	if err := f.svc.VerifyEmail(ctx, f.mail.lastToken(t)); err != nil {
+		t.Fatalf("VerifyEmail() error = %v", err)
+	}
+	refreshed, err := f.svc.RefreshToken(ctx, tokens.RefreshToken)
+	if err != nil {
+		t.Fatalf("RefreshToken() error = %v", err)
+	}
+	if got := emailVerified(refreshed.AccessToken); got != true {
+		t.Errorf("email_verified after verification = %v, want true", got)
+	}
+}
+
+func TestUserService_RegisterSucceedsWhenMailFails(t *testing.T) {
+	f := setupUserService(t)
+	f.mail.err = errors.New("smtp down")
+
+	user := &models.User{Name: "Sari", Email: "sari@example.com", Password: "rahasia123"}
+	if err := f.svc.Register(context.Background(), user); err != nil {
+		t.Fatalf("Register() error = %v", err)
+	}
+	f.svc.Wait()
+	if user.InternalID == 0 {
+		t.Error("user should be created even if the email could not be sent")
+	}
+	if err := f.svc.ResendVerification(context.Background(), user.InternalID); err == nil {
+		t.Error("ResendVerification() should report the mail error")
+	}
+}
+
+func TestUserService_ForgotPasswordDoesNotRevealEmail(t *testing.T) {
+	f := setupUserService(t)
+	sent := len(f.mail.sent)
+
+	if err := f.forgotPassword(context.Background(), "tidakada@example.com"); err != nil {
+		t.Errorf("ForgotPassword() for unknown email error = %v", err)
+	}
+	if len(f.mail.sent) != sent {
+		t.Error("no email should be sent for an unknown address")
+	}
+
+	//gagal kirim email atau buat token juga tidak boleh terlihat berbeda dari email yang tidak terdaftar
+	f.mail.err = errors.New("smtp down")
+	if err := f.forgotPassword(context.Background(), "budi@example.com"); err != nil {
+		t.Errorf("ForgotPassword() with mail failure error = %v", err)
+	}
+	f.userTokens.err = errors.New("db down")
+	if err := f.forgotPassword(context.Background(), "budi@example.com"); err != nil {
+		t.Errorf("ForgotPassword() with token failure error = %v", err)
+	}
+}
+
+// mailer yang lambat tidak boleh membuat response email terdaftar lebih lama
+func TestUserService_ForgotPasswordDoesNotWaitForMail(t *testing.T) {
+	f := setupUserService(t)
+	release := make(chan struct{})
+	f.svc.(*userService).mailer = mailerFunc(func(ctx context.Context, msg mailer.Message) error {
+		<-release
+		return nil
+	})
+
+	done := make(chan error, 1)
+	go func() { done <- f.svc.ForgotPassword(context.Background(), "budi@example.com") }()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Errorf("ForgotPassword() error = %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Error("ForgotPassword() should not wait for the email to be sent")
+	}
+	close(release)
+	f.svc.Wait()
+}
+
+func TestUserService_BackgroundMailIsBounded(t *testing.T) {
+	f := setupUserService(t)
+	svc := f.svc.(*userService)
+	svc.background = newBackgroundMail(1)
+
+	release := make(chan struct{})
+	var sent atomic.Int32
+	svc.mailer = mailerFunc(func(ctx context.Context, msg mailer.Message) error {
+		sent.Add(1)
+		<-release
+		return nil
+	})
+
+	//slot pertama masih mengirim, request berikut nya dilewati
+	for i := 0; i < 3; i++ {
+		if err := f.svc.ForgotPassword(context.Background(), "budi@example.com"); err != nil {
+			t.Fatalf("ForgotPassword() error = %v", err)
+		}
+	}
+	close(release)
+	f.svc.Close()
+	if err := f.svc.ForgotPassword(context.Background(), "budi@example.com"); err != nil {
+		t.Fatalf("ForgotPassword() after Close error = %v", err)
+	}
+	f.svc.Wait()
+
+	if got := sent.Load(); got != 1 {
+		t.Errorf("sent %d emails, want 1", got)
+	}
+}
+
+type mailerFunc func(ctx context.Context, msg mailer.Message) error
+
+func (fn mailerFunc) Send(ctx context.Context, msg mailer.Message) error {
+	return fn(ctx, msg)
+}
+
+func TestUserService_ResetPassword(t *testing.T) {
+	f := setupUserService(t)
+	ctx := context.Background()
+	verifyToken := f.mail.lastToken(t)
+
+	_, session, err := f.svc.Login(ctx, "budi@example.com", "rahasia123", "laptop")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := f.forgotPassword(ctx, "budi@example.com"); err != nil {
+		t.Fatalf("ForgotPassword() error = %v", err)
+	}
+	last := f.mail.sent[len(f.mail.sent)-1]
+	if last.Subject != "Reset your password" || !strings.Contains(last.Text, "http://localhost:5173/reset-password?token=") || !strings.Contains(last.Text, "1 hour") {
+		t.Errorf("reset email = %+v", last)
+	}
+	token := f.mail.lastToken(t)
+
+	//token verifikasi email tidak bisa dipakai untuk reset password
+	if err := f.svc.ResetPassword(ctx, verifyToken, "Baru12345"); !errors.Is(err, ErrInvalidResetToken) {
+		t.Errorf("ResetPassword() with verification token error = %v", err)
+	}
+
+	if err := f.svc.ResetPassword(ctx, token, "Baru12345"); err != nil {
+		t.Fatalf("ResetPassword() error = %v", err)
+	}
+	if err := f.svc.ResetPassword(ctx, token, "Lagi12345"); !errors.Is(err, ErrInvalidResetToken) {
+		t.Errorf("ResetPassword() twice error = %v, token must be single use", err)
+	}
+
+	if _, _, err := f.svc.Login(ctx, "budi@example.com", "rahasia123", "laptop"); err == nil {
+		t.Error("old password should no longer work")
+	}
+	if _, _, err := f.svc.Login(ctx, "budi@example.com", "Baru12345", "laptop"); err != nil {
+		t.Errorf("Login() with new password error = %v", err)
+	}
+	if _, err := f.svc.RefreshToken(ctx, session.RefreshToken); err == nil {
+		t.Error("sessions from before the reset should be revoked")
+	}
+	if f.users.users[0].EmailVerifiedAt == nil {
+		t.Error("reset via email link should verify the email")
+	}
+}
+
+func TestUserService_ResetPasswordExpiredToken(t *testing.T) {
+	f := setupUserService(t)
+	ctx := context.Background()
+
+	f.svc.(*userService).email.ResetTTL = -time.Minute
+	if err := f.forgotPassword(ctx, "budi@example.com"); err != nil {
+		t.Fatal(err)
+	}
+	if err := f.svc.ResetPassword(ctx, f.mail.lastToken(t), "Baru12345"); !errors.Is(err, ErrInvalidResetToken) {
+		t.Errorf("ResetPassword() with expired token error = %v", err)
+	}
+}
+
+func TestUserService_ChangePassword(t *testing.T) {
+	f := setupUserService(t)
+	ctx := context.Background()
+
+	if err := f.svc.ChangePassword(ctx, 1, "salah", "Baru12345"); !errors.Is(err, ErrWrongPassword) {
+		t.Errorf("ChangePassword() with wrong current password error = %v", err)
+	}
+	if err := f.svc.ChangePassword(ctx, 1, "rahasia123", "Baru12345"); err != nil {
+		t.Fatalf("ChangePassword() error = %v", err)
+	}
+	if _, _, err := f.svc.Login(ctx, "budi@example.com", "Baru12345", "laptop"); err != nil {
+		t.Errorf("Login() with new password error = %v", err)
+	}
+}
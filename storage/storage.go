@@ -0,0 +1,43 @@
+package storage
+
+import (
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/odink789/project-management/config"
+)
+
+//storage dipakai untuk menyimpan file attachment, backend nya dipilih lewat STORAGE_DRIVER
+
+var ErrNotFound = errors.New("object not found")
+
+type Storage interface {
+	Put(key string, r io.Reader, size int64, contentType string) error
+	Open(key string) (io.ReadCloser, error)
+	OpenRange(key string, offset, length int64) (io.ReadCloser, error) // dipakai untuk request dengan header Range
+	Delete(key string) error
+}
+
+// New membuat storage sesuai config, local atau s3
+func New(cfg *config.Config) (Storage, error) {
+	switch cfg.StorageDriver {
+	case config.StorageDriverLocal:
+		return NewLocalStorage(cfg.StorageLocalDir)
+	case config.StorageDriverS3:
+		return NewS3Storage(S3Options{
+			Endpoint:  cfg.S3Endpoint,
+			Region:    cfg.S3Region,
+			Bucket:    cfg.S3Bucket,
+			AccessKey: cfg.S3AccessKey,
+			SecretKey: cfg.S3SecretKey,
+		})
+	default:
+		return nil, fmt.Errorf("unknown storage driver %q", cfg.StorageDriver)
+	}
+}
+
+type limitedReadCloser struct {
+	io.Reader
+	io.Closer
+}
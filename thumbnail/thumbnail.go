@@ -0,0 +1,151 @@
+package thumbnail
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	"image/color"
+	_ "image/gif"
+	"image/jpeg"
+	_ "image/png"
+
+	"golang.org/x/image/draw"
+	_ "golang.org/x/image/webp"
+)
+
+//thumbnail selalu di encode ulang ke JPEG, jadi semua metadata dari file asli (EXIF, GPS, dsb) hilang
+
+const (
+	maxSourcePixels = 50_000_000 // gambar yang lebih besar ditolak supaya tidak kehabisan memory
+	jpegQuality     = 85
+)
+
+var ErrImageTooLarge = errors.New("image is too large to generate thumbnails")
+
+// Size adalah ukuran thumbnail, gambar di scale supaya sisi terpanjang nya tidak lebih dari MaxDimension
+type Size struct {
+	Name         string
+	MaxDimension int
+}
+
+// urut dari yang paling besar, thumbnail yang lebih kecil dibuat dari thumbnail sebelum nya
+var Sizes = []Size{
+	{Name: "large", MaxDimension: 1024},
+	{Name: "medium", MaxDimension: 320},
+	{Name: "small", MaxDimension: 128},
+}
+
+const MimeType = "image/jpeg"
+
+var supportedTypes = map[string]bool{
+	"image/jpeg": true,
+	"image/png":  true,
+	"image/gif":  true,
+	"image/webp": true,
+}
+
+// Supported mengecek apakah thumbnail bisa dibuat dari file dengan mime type ini
+func Supported(mimeType string) bool {
+	return supportedTypes[mimeType]
+}
+
+type Result struct {
+	Size   Size
+	Width  int
+	Height int
+	Data   []byte
+}
+
+// Generate membuat thumbnail untuk semua Sizes dari isi file gambar
+func Generate(data []byte) ([]Result, error) {
+	cfg, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	if cfg.Width <= 0 || cfg.Height <= 0 || cfg.Width*cfg.Height > maxSourcePixels {
+		return nil, ErrImageTooLarge
+	}
+
+	src, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+
+	//scale dulu baru diputar, memutar gambar kecil jauh lebih murah
+	orientation := readOrientation(data)
+	results := make([]Result, 0, len(Sizes))
+	current := src
+	for i, size := range Sizes {
+		scaled := fit(current, size.MaxDimension)
+		if i == 0 {
+			scaled = orient(scaled, orientation)
+		}
+		current = scaled
+
+		var buf bytes.Buffer
+		if err := jpeg.Encode(&buf, scaled, &jpeg.Options{Quality: jpegQuality}); err != nil {
+			return nil, err
+		}
+		bounds := scaled.Bounds()
+		results = append(results, Result{Size: size, Width: bounds.Dx(), Height: bounds.Dy(), Data: buf.Bytes()})
+	}
+	return results, nil
+}
+
+// fit mengecilkan gambar (tidak pernah memperbesar) di atas background putih supaya transparansi tidak jadi hitam
+func fit(src image.Image, maxDimension int) *image.RGBA {
+	bounds := src.Bounds()
+	width, height := bounds.Dx(), bounds.Dy()
+	if width > maxDimension || height > maxDimension {
+		if width >= height {
+			height = max(1, height*maxDimension/width)
+			width = maxDimension
+		} else {
+			width = max(1, width*maxDimension/height)
+			height = maxDimension
+		}
+	}
+
+	dst := image.NewRGBA(image.Rect(0, 0, width, height))
+	draw.Draw(dst, dst.Bounds(), image.NewUniform(color.White), image.Point{}, draw.Src)
+	draw.CatmullRom.Scale(dst, dst.Bounds(), src, bounds, draw.Over, nil)
+	return dst
+}
+
+// orient memutar / membalik gambar sesuai EXIF orientation 1-8
+func orient(src *image.RGBA, orientation int) *image.RGBA {
+	if orientation <= 1 || orientation > 8 {
+		return src
+	}
+
+	width, height := src.Bounds().Dx(), src.Bounds().Dy()
+	dstWidth, dstHeight := width, height
+	if orientation >= 5 {
+		dstWidth, dstHeight = height, width
+	}
+
+	dst := image.NewRGBA(image.Rect(0, 0, dstWidth, dstHeight))
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			var dx, dy int
+			switch orientation {
+			case 2:
+				dx, dy = width-1-x, y
+			case 3:
+				dx, dy = width-1-x, height-1-y
+			case 4:
+				dx, dy = x, height-1-y
+			case 5:
+				dx, dy = y, x
+			case 6:
+				dx, dy = height-1-y, x
+			case 7:
+				dx, dy = height-1-y, width-1-x
+			case 8:
+				dx, dy = y, width-1-x
+			}
+			dst.SetRGBA(dx, dy, src.RGBAAt(x, y))
+		}
+	}
+	return dst
+}
@@ -0,0 +1,158 @@
+package thumbnail
+
+import (
+	"bytes"
+	"encoding/binary"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"testing"
+)
+
+// exifSegment membuat APP1 EXIF berisi orientation dan pointer ke GPS IFD
+func exifSegment(order binary.ByteOrder, orientation uint16) []byte {
+	tiff := &bytes.Buffer{}
+	if order == binary.LittleEndian {
+		tiff.WriteString("II")
+	} else {
+		tiff.WriteString("MM")
+	}
+	binary.Write(tiff, order, uint16(42))
+	binary.Write(tiff, order, uint32(8))
+
+	binary.Write(tiff, order, uint16(2))
+	//orientation, SHORT, 1 value
+	binary.Write(tiff, order, uint16(exifOrientationTag))
+	binary.Write(tiff, order, uint16(3))
+	binary.Write(tiff, order, uint32(1))
+	binary.Write(tiff, order, orientation)
+	binary.Write(tiff, order, uint16(0))
+	//GPS IFD pointer
+	binary.Write(tiff, order, uint16(0x8825))
+	binary.Write(tiff, order, uint16(4))
+	binary.Write(tiff, order, uint32(1))
+	binary.Write(tiff, order, uint32(38))
+	binary.Write(tiff, order, uint32(0))
+	tiff.WriteString("GPS-LATITUDE-SECRET")
+
+	payload := append([]byte("Exif\x00\x00"), tiff.Bytes()...)
+	segment := []byte{0xFF, 0xE1, 0, 0}
+	binary.BigEndian.PutUint16(segment[2:], uint16(len(payload)+2))
+	return append(segment, payload...)
+}
+
+// gambar 400x200, setengah kiri merah dan setengah kanan biru
+func testImage() *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, 400, 200))
+	for y := 0; y < 200; y++ {
+		for x := 0; x < 400; x++ {
+			c := color.RGBA{R: 255, A: 255}
+			if x >= 200 {
+				c = color.RGBA{B: 255, A: 255}
+			}
+			img.SetRGBA(x, y, c)
+		}
+	}
+	return img
+}
+
+func jpegWithExif(t *testing.T, order binary.ByteOrder, orientation uint16) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, testImage(), &jpeg.Options{Quality: 95}); err != nil {
+		t.Fatal(err)
+	}
+	data := buf.Bytes()
+	out := append([]byte{}, data[:2]...)
+	out = append(out, exifSegment(order, orientation)...)
+	return append(out, data[2:]...)
+}
+
+func TestReadOrientation(t *testing.T) {
+	if got := readOrientation(jpegWithExif(t, binary.LittleEndian, 6)); got != 6 {
+		t.Errorf("little endian orientation = %d, want 6", got)
+	}
+	if got := readOrientation(jpegWithExif(t, binary.BigEndian, 8)); got != 8 {
+		t.Errorf("big endian orientation = %d, want 8", got)
+	}
+	if got := readOrientation([]byte("not an image")); got != 1 {
+		t.Errorf("non jpeg orientation = %d, want 1", got)
+	}
+	if got := readOrientation([]byte{0xFF, 0xD8, 0xFF, 0xE1, 0xFF, 0xFF}); got != 1 {
+		t.Errorf("truncated segment orientation = %d, want 1", got)
+	}
+}
+
+func TestGenerate_Sizes(t *testing.T) {
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, testImage()); err != nil {
+		t.Fatal(err)
+	}
+
+	results, err := Generate(buf.Bytes())
+	if err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+
+	want := map[string][2]int{"large": {400, 200}, "medium": {320, 160}, "small": {128, 64}}
+	if len(results) != len(want) {
+		t.Fatalf("len(results) = %d", len(results))
+	}
+	for _, r := range results {
+		size := want[r.Size.Name]
+		if r.Width != size[0] || r.Height != size[1] {
+			t.Errorf("%s = %dx%d, want %dx%d", r.Size.Name, r.Width, r.Height, size[0], size[1])
+		}
+		img, err := jpeg.Decode(bytes.NewReader(r.Data))
+		if err != nil {
+			t.Fatalf("%s is not a valid jpeg: %v", r.Size.Name, err)
+		}
+		if img.Bounds().Dx() != r.Width || img.Bounds().Dy() != r.Height {
+			t.Errorf("%s encoded bounds = %v", r.Size.Name, img.Bounds())
+		}
+	}
+}
+
+func TestGenerate_OrientationAndMetadata(t *testing.T) {
+	results, err := Generate(jpegWithExif(t, binary.LittleEndian, 6))
+	if err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+
+	large := results[0]
+	if large.Width != 200 || large.Height != 400 {
+		t.Fatalf("rotated size = %dx%d, want 200x400", large.Width, large.Height)
+	}
+
+	//diputar 90 derajat searah jarum jam, sisi kiri (merah) pindah ke atas
+	img, _ := jpeg.Decode(bytes.NewReader(large.Data))
+	top := color.RGBAModel.Convert(img.At(100, 50)).(color.RGBA)
+	bottom := color.RGBAModel.Convert(img.At(100, 350)).(color.RGBA)
+	if top.R < 200 || top.B > 60 {
+		t.Errorf("top should be red, got %v", top)
+	}
+	if bottom.B < 200 || bottom.R > 60 {
+		t.Errorf("bottom should be blue, got %v", bottom)
+	}
+
+	for _, r := range results {
+		if bytes.Contains(r.Data, []byte("Exif")) || bytes.Contains(r.Data, []byte("GPS-LATITUDE-SECRET")) {
+			t.Errorf("%s still contains EXIF metadata", r.Size.Name)
+		}
+	}
+}
+
+func TestGenerate_Invalid(t *testing.T) {
+	if _, err := Generate([]byte("definitely not an image")); err == nil {
+		t.Error("Generate() should fail for non image data")
+	}
+}
+
+func TestSupported(t *testing.T) {
+	for mimeType, want := range map[string]bool{"image/jpeg": true, "image/webp": true, "image/svg+xml": false, "application/pdf": false} {
+		if got := Supported(mimeType); got != want {
+			t.Errorf("Supported(%q) = %v, want %v", mimeType, got, want)
+		}
+	}
+}
@@ -0,0 +1,159 @@
+package thumbnail
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"strings"
+	"sync"
+
+	"github.com/odink789/project-management/models"
+	"github.com/odink789/project-management/repositories"
+	"github.com/odink789/project-management/storage"
+)
+
+const maxSourceBytes = 50 << 20 // file yang lebih besar tidak dibuatkan thumbnail
+
+// Worker membuat thumbnail di background, upload tidak perlu menunggu proses resize
+type Worker struct {
+	repo    repositories.AttachmentRepository
+	storage storage.Storage
+	jobs    chan int64
+	cancel  context.CancelFunc
+	wg      sync.WaitGroup
+}
+
+func NewWorker(repo repositories.AttachmentRepository, store storage.Storage, queueSize int) *Worker {
+	return &Worker{repo: repo, storage: store, jobs: make(chan int64, queueSize)}
+}
+
+// Start menjalankan n goroutine worker lalu memasukkan attachment yang masih pending ke antrian
+// worker berhenti saat ctx selesai atau Stop dipanggil
+func (w *Worker) Start(ctx context.Context, n int) error {
+	ctx, w.cancel = context.WithCancel(ctx)
+	for i := 0; i < n; i++ {
+		w.wg.Add(1)
+		go w.run(ctx)
+	}
+
+	pending, err := w.repo.FindPendingThumbnails(ctx)
+	if err != nil {
+		return err
+	}
+	for _, attachment := range pending {
+		w.Enqueue(attachment.InternalID)
+	}
+	return nil
+}
+
+// kalau antrian penuh job dilewati, status nya tetap pending dan diproses lagi saat startup berikut nya
+func (w *Worker) Enqueue(attachmentID int64) {
+	select {
+	case w.jobs <- attachmentID:
+	default:
+		log.Println("Thumbnail queue is full, skipping attachment", attachmentID)
+	}
+}
+
+// Stop menghentikan worker, job yang tersisa di antrian tetap pending dan diproses lagi saat startup berikut nya
+func (w *Worker) Stop() {
+	if w.cancel != nil {
+		w.cancel()
+	}
+}
+
+// Wait menunggu semua goroutine worker selesai setelah Stop
+func (w *Worker) Wait() {
+	w.wg.Wait()
+}
+
+func (w *Worker) run(ctx context.Context) {
+	defer w.wg.Done()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case id := <-w.jobs:
+			if err := w.Process(ctx, id); err != nil {
+				log.Println("Failed to generate thumbnails for attachment", id, err)
+			}
+		}
+	}
+}
+
+// Process membuat semua ukuran thumbnail untuk satu attachment
+func (w *Worker) Process(ctx context.Context, attachmentID int64) error {
+	attachment, err := w.repo.FindByID(ctx, attachmentID)
+	if err != nil {
+		return err
+	}
+	if attachment.Thumbnail != models.ThumbnailPending {
+		return nil
+	}
+
+	thumbnails, err := w.generate(attachment)
+	if err != nil {
+		if statusErr := w.repo.UpdateThumbnailStatus(ctx, attachment, models.ThumbnailFailed); statusErr != nil {
+			log.Println("Failed to update thumbnail status", attachmentID, statusErr)
+		}
+		return err
+	}
+
+	//attachment dihapus selagi thumbnail dibuat, file yang sudah ditulis tidak punya pemilik lagi
+	err = w.repo.SaveThumbnails(ctx, attachment, thumbnails)
+	if errors.Is(err, repositories.ErrAttachmentDeleted) {
+		for _, thumbnail := range thumbnails {
+			if err := w.storage.Delete(thumbnail.File); err != nil {
+				log.Println("Failed to delete thumbnail file", thumbnail.File, err)
+			}
+		}
+		return nil
+	}
+	return err
+}
+
+func (w *Worker) generate(attachment *models.CardAttachment) ([]models.AttachmentThumbnail, error) {
+	if attachment.Size > maxSourceBytes {
+		return nil, ErrImageTooLarge
+	}
+
+	file, err := w.storage.Open(attachment.File)
+	if err != nil {
+		return nil, err
+	}
+	data, err := io.ReadAll(io.LimitReader(file, maxSourceBytes+1))
+	file.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	results, err := Generate(data)
+	if err != nil {
+		return nil, err
+	}
+
+	thumbnails := make([]models.AttachmentThumbnail, 0, len(results))
+	for _, result := range results {
+		key := Key(attachment, result.Size.Name)
+		if err := w.storage.Put(key, bytes.NewReader(result.Data), int64(len(result.Data)), MimeType); err != nil {
+			return nil, err
+		}
+		thumbnails = append(thumbnails, models.AttachmentThumbnail{
+			AttachmentID: attachment.InternalID,
+			Size:         result.Size.Name,
+			File:         key,
+			Width:        result.Width,
+			Height:       result.Height,
+			ByteSize:     int64(len(result.Data)),
+		})
+	}
+	return thumbnails, nil
+}
+
+// Key adalah lokasi thumbnail di storage, terpisah dari folder file asli
+func Key(attachment *models.CardAttachment, size string) string {
+	return fmt.Sprintf("thumbnails/%s/%s.jpg", strings.TrimPrefix(attachment.File, "attachments/"), size)
+}
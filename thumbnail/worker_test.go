@@ -0,0 +1,146 @@
+package thumbnail
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"image/png"
+	"testing"
+	"time"
+
+	"github.com/odink789/project-management/models"
+	"github.com/odink789/project-management/repositories"
+	"github.com/odink789/project-management/storage"
+)
+
+type fakeAttachmentRepo struct {
+	repositories.AttachmentRepository
+	attachment *models.CardAttachment
+	thumbnails []models.AttachmentThumbnail
+	deleted    bool
+}
+
+func (r *fakeAttachmentRepo) FindPendingThumbnails(ctx context.Context) ([]models.CardAttachment, error) {
+	return nil, nil
+}
+
+func (r *fakeAttachmentRepo) FindByID(ctx context.Context, id int64) (*models.CardAttachment, error) {
+	return r.attachment, nil
+}
+
+func (r *fakeAttachmentRepo) UpdateThumbnailStatus(ctx context.Context, attachment *models.CardAttachment, status string) error {
+	attachment.Thumbnail = status
+	return nil
+}
+
+func (r *fakeAttachmentRepo) SaveThumbnails(ctx context.Context, attachment *models.CardAttachment, thumbnails []models.AttachmentThumbnail) error {
+	if r.deleted {
+		return repositories.ErrAttachmentDeleted
+	}
+	r.thumbnails = thumbnails
+	attachment.Thumbnail = models.ThumbnailReady
+	return nil
+}
+
+func TestWorker_Process(t *testing.T) {
+	store, err := storage.NewLocalStorage(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, testImage()); err != nil {
+		t.Fatal(err)
+	}
+	attachment := &models.CardAttachment{InternalID: 1, File: "attachments/card/image", Size: int64(buf.Len()), Thumbnail: models.ThumbnailPending}
+	if err := store.Put(attachment.File, bytes.NewReader(buf.Bytes()), int64(buf.Len()), "image/png"); err != nil {
+		t.Fatal(err)
+	}
+
+	repo := &fakeAttachmentRepo{attachment: attachment}
+	worker := NewWorker(repo, store, 1)
+	if err := worker.Process(context.Background(), 1); err != nil {
+		t.Fatalf("Process() error = %v", err)
+	}
+
+	if attachment.Thumbnail != models.ThumbnailReady || len(repo.thumbnails) != len(Sizes) {
+		t.Fatalf("status = %s, thumbnails = %d", attachment.Thumbnail, len(repo.thumbnails))
+	}
+	for _, thumb := range repo.thumbnails {
+		if thumb.File != "thumbnails/card/image/"+thumb.Size+".jpg" {
+			t.Errorf("thumbnail key = %s", thumb.File)
+		}
+		file, err := store.Open(thumb.File)
+		if err != nil {
+			t.Errorf("thumbnail %s not stored: %v", thumb.Size, err)
+			continue
+		}
+		file.Close()
+	}
+}
+
+func TestWorker_ProcessInvalidImage(t *testing.T) {
+	store, err := storage.NewLocalStorage(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	attachment := &models.CardAttachment{InternalID: 1, File: "attachments/card/broken", Size: 5, Thumbnail: models.ThumbnailPending}
+	if err := store.Put(attachment.File, bytes.NewReader([]byte("hello")), 5, "image/png"); err != nil {
+		t.Fatal(err)
+	}
+
+	worker := NewWorker(&fakeAttachmentRepo{attachment: attachment}, store, 1)
+	if err := worker.Process(context.Background(), 1); err == nil {
+		t.Error("Process() should fail for broken image")
+	}
+	if attachment.Thumbnail != models.ThumbnailFailed {
+		t.Errorf("status = %s, want failed", attachment.Thumbnail)
+	}
+}
+
+func TestWorker_ProcessDeletedAttachment(t *testing.T) {
+	store, err := storage.NewLocalStorage(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, testImage()); err != nil {
+		t.Fatal(err)
+	}
+	attachment := &models.CardAttachment{InternalID: 1, File: "attachments/card/image", Size: int64(buf.Len()), Thumbnail: models.ThumbnailPending}
+	if err := store.Put(attachment.File, bytes.NewReader(buf.Bytes()), int64(buf.Len()), "image/png"); err != nil {
+		t.Fatal(err)
+	}
+
+	//attachment dihapus saat thumbnail sedang dibuat, file thumbnail tidak boleh tertinggal di storage
+	worker := NewWorker(&fakeAttachmentRepo{attachment: attachment, deleted: true}, store, 1)
+	if err := worker.Process(context.Background(), 1); err != nil {
+		t.Fatalf("Process() error = %v", err)
+	}
+	for _, size := range Sizes {
+		if _, err := store.Open(Key(attachment, size.Name)); !errors.Is(err, storage.ErrNotFound) {
+			t.Errorf("thumbnail %s should be deleted, got %v", size.Name, err)
+		}
+	}
+}
+
+func TestWorker_Stop(t *testing.T) {
+	worker := NewWorker(&fakeAttachmentRepo{}, nil, 1)
+	if err := worker.Start(context.Background(), 2); err != nil {
+		t.Fatal(err)
+	}
+
+	done := make(chan struct{})
+	go func() {
+		worker.Stop()
+		worker.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("Wait() should return after Stop()")
+	}
+}
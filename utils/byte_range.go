@@ -0,0 +1,60 @@
+package utils
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+)
+
+var ErrRangeNotSatisfiable = errors.New("requested range not satisfiable")
+
+// ParseRange membaca header Range untuk file berukuran size
+// hanya single range yang didukung, multi range / format yang salah diabaikan (kirim file utuh)
+// partial false berarti kirim seluruh file
+func ParseRange(header string, size int64) (start, length int64, partial bool, err error) {
+	if header == "" || !strings.HasPrefix(header, "bytes=") || strings.Contains(header, ",") {
+		return 0, size, false, nil
+	}
+
+	spec := strings.TrimSpace(strings.TrimPrefix(header, "bytes="))
+	first, last, found := strings.Cut(spec, "-")
+	if !found {
+		return 0, size, false, nil
+	}
+	first, last = strings.TrimSpace(first), strings.TrimSpace(last)
+
+	//bytes=-500 berarti 500 byte terakhir
+	if first == "" {
+		n, err := strconv.ParseInt(last, 10, 64)
+		if err != nil || n < 0 {
+			return 0, size, false, nil
+		}
+		if n == 0 || size == 0 {
+			return 0, 0, false, ErrRangeNotSatisfiable
+		}
+		if n > size {
+			n = size
+		}
+		return size - n, n, true, nil
+	}
+
+	start, err = strconv.ParseInt(first, 10, 64)
+	if err != nil || start < 0 {
+		return 0, size, false, nil
+	}
+	if start >= size {
+		return 0, 0, false, ErrRangeNotSatisfiable
+	}
+
+	end := size - 1
+	if last != "" {
+		end, err = strconv.ParseInt(last, 10, 64)
+		if err != nil || end < start {
+			return 0, size, false, nil
+		}
+		if end > size-1 {
+			end = size - 1
+		}
+	}
+	return start, end - start + 1, true, nil
+}
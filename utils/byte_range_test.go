@@ -0,0 +1,42 @@
+package utils
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestParseRange(t *testing.T) {
+	tests := []struct {
+		header      string
+		start       int64
+		length      int64
+		partial     bool
+		unsatisfied bool
+	}{
+		{header: "", start: 0, length: 1000},
+		{header: "bytes=0-99", start: 0, length: 100, partial: true},
+		{header: "bytes=500-", start: 500, length: 500, partial: true},
+		{header: "bytes=-200", start: 800, length: 200, partial: true},
+		{header: "bytes=-5000", start: 0, length: 1000, partial: true},
+		{header: "bytes=900-5000", start: 900, length: 100, partial: true},
+		{header: "bytes=1000-", unsatisfied: true},
+		{header: "bytes=-0", unsatisfied: true},
+		{header: "bytes=0-1,5-9", start: 0, length: 1000},
+		{header: "bytes=abc", start: 0, length: 1000},
+		{header: "bytes=9-1", start: 0, length: 1000},
+		{header: "items=0-1", start: 0, length: 1000},
+	}
+
+	for _, tt := range tests {
+		start, length, partial, err := ParseRange(tt.header, 1000)
+		if tt.unsatisfied {
+			if !errors.Is(err, ErrRangeNotSatisfiable) {
+				t.Errorf("ParseRange(%q) error = %v, want ErrRangeNotSatisfiable", tt.header, err)
+			}
+			continue
+		}
+		if err != nil || start != tt.start || length != tt.length || partial != tt.partial {
+			t.Errorf("ParseRange(%q) = %d, %d, %v, %v want %d, %d, %v", tt.header, start, length, partial, err, tt.start, tt.length, tt.partial)
+		}
+	}
+}
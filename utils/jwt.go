@@ -1,6 +1,8 @@
 package utils
 
 import (
+	"errors"
+	"fmt"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
@@ -11,18 +13,74 @@ import (
 //generatetoken jwt
 //generate refresh token
 
-func GenerateToken(userID int64, role, email string, publicID uuid.UUID) (string, error) {
-	secret := config.AppConfig.JWTSecret
-	duration, _ := time.ParseDuration(config.Appconfig.JWTExpire)
+const (
+	TokenTypeAccess  = "access"
+	TokenTypeRefresh = "refresh"
+)
+
+// emailVerified dibawa di access token supaya route yang butuh email terverifikasi tidak perlu baca db
+func GenerateToken(userID int64, role, email string, publicID uuid.UUID, emailVerified bool) (string, error) {
+	duration := config.AppConfig.JWTExpire
+	if duration <= 0 {
+		return "", fmt.Errorf("invalid JWT expiry : %v", duration)
+	}
+
+	claims := jwt.MapClaims{
+		"user_id":        userID,
+		"role":           role,
+		"pub_id":         publicID,
+		"email":          email,
+		"email_verified": emailVerified,
+		"type":           TokenTypeAccess,
+		"exp":            time.Now().Add(duration).Unix(),
+	}
+
+	return signToken(claims)
+}
+
+// refresh token hanya membawa identitas user, role & email diambil ulang dari db saat refresh
+func GenerateRefreshToken(userID int64, publicID uuid.UUID) (string, time.Time, error) {
+	duration := config.AppConfig.JWTRefreshExpire
+	if duration <= 0 {
+		return "", time.Time{}, fmt.Errorf("invalid refresh token expiry : %v", duration)
+	}
 
+	expiresAt := time.Now().Add(duration)
 	claims := jwt.MapClaims{
 		"user_id": userID,
-		"role":    role,
 		"pub_id":  publicID,
-		"email":   email,
-		"exp":     time.Now().Add(duration).Unix(),
+		"type":    TokenTypeRefresh,
+		"jti":     uuid.NewString(),
+		"exp":     expiresAt.Unix(),
 	}
 
+	token, err := signToken(claims)
+	return token, expiresAt, err
+}
+
+func ParseToken(tokenString string) (jwt.MapClaims, error) {
+	claims := jwt.MapClaims{}
+	_, err := jwt.ParseWithClaims(tokenString, claims, func(t *jwt.Token) (interface{}, error) {
+		return []byte(config.AppConfig.JWTSecret), nil
+	}, jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}), jwt.WithExpirationRequired())
+	if err != nil {
+		return nil, err
+	}
+	return claims, nil
+}
+
+func ParseRefreshToken(tokenString string) (jwt.MapClaims, error) {
+	claims, err := ParseToken(tokenString)
+	if err != nil {
+		return nil, err
+	}
+	if claims["type"] != TokenTypeRefresh {
+		return nil, errors.New("token is not a refresh token")
+	}
+	return claims, nil
+}
+
+func signToken(claims jwt.MapClaims) (string, error) {
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	return token.SignedString([]byte(secret))
+	return token.SignedString([]byte(config.AppConfig.JWTSecret))
 }
@@ -0,0 +1,76 @@
+package utils
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/odink789/project-management/config"
+)
+
+func setupJWTConfig(t *testing.T) {
+	t.Helper()
+	config.AppConfig = &config.Config{
+		JWTSecret:        "test-secret",
+		JWTExpire:        15 * time.Minute,
+		JWTRefreshExpire: 24 * time.Hour,
+	}
+}
+
+func TestGenerateRefreshToken_RoundTrip(t *testing.T) {
+	setupJWTConfig(t)
+	pubID := uuid.New()
+
+	token, _, err := GenerateRefreshToken(7, pubID)
+	if err != nil {
+		t.Fatalf("GenerateRefreshToken() error = %v", err)
+	}
+
+	claims, err := ParseRefreshToken(token)
+	if err != nil {
+		t.Fatalf("ParseRefreshToken() error = %v", err)
+	}
+
+	if claims["pub_id"] != pubID.String() {
+		t.Errorf("pub_id = %v, want %v", claims["pub_id"], pubID)
+	}
+	if claims["user_id"] != float64(7) {
+		t.Errorf("user_id = %v, want 7", claims["user_id"])
+	}
+}
+
+func TestParseRefreshToken_RejectsAccessToken(t *testing.T) {
+	setupJWTConfig(t)
+
+	token, err := GenerateToken(1, "user", "user@example.com", uuid.New(), true)
+	if err != nil {
+		t.Fatalf("GenerateToken() error = %v", err)
+	}
+
+	if _, err := ParseRefreshToken(token); err == nil {
+		t.Error("expected error when parsing access token as refresh token")
+	}
+}
+
+func TestParseToken_InvalidSignature(t *testing.T) {
+	setupJWTConfig(t)
+
+	token, err := GenerateToken(1, "user", "user@example.com", uuid.New(), true)
+	if err != nil {
+		t.Fatalf("GenerateToken() error = %v", err)
+	}
+
+	config.AppConfig.JWTSecret = "other-secret"
+	if _, err := ParseToken(token); err == nil {
+		t.Error("expected error for token signed with different secret")
+	}
+}
+
+func TestGenerateToken_InvalidExpiry(t *testing.T) {
+	setupJWTConfig(t)
+	config.AppConfig.JWTExpire = 0
+
+	if _, err := GenerateToken(1, "user", "user@example.com", uuid.New(), true); err == nil {
+		t.Error("expected error for zero expiry")
+	}
+}
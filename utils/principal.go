@@ -0,0 +1,53 @@
+package utils
+
+import (
+	"errors"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+)
+
+//principal adalah identitas user yang sedang login, diambil dari claims access token
+
+const PrincipalKey = "principal"
+
+type Principal struct {
+	UserID   int64
+	PublicID uuid.UUID
+	Role     string
+	Email    string
+	//token lama yang belum membawa claim email_verified dianggap belum terverifikasi
+	EmailVerified bool
+}
+
+func NewPrincipal(claims jwt.MapClaims) (*Principal, error) {
+	//angka di MapClaims selalu di decode sebagai float64
+	userID, ok := claims["user_id"].(float64)
+	if !ok {
+		return nil, errors.New("invalid user_id claim")
+	}
+
+	pubIDStr, _ := claims["pub_id"].(string)
+	pubID, err := uuid.Parse(pubIDStr)
+	if err != nil {
+		return nil, errors.New("invalid pub_id claim")
+	}
+
+	role, _ := claims["role"].(string)
+	email, _ := claims["email"].(string)
+	emailVerified, _ := claims["email_verified"].(bool)
+
+	return &Principal{
+		UserID:        int64(userID),
+		PublicID:      pubID,
+		Role:          role,
+		Email:         email,
+		EmailVerified: emailVerified,
+	}, nil
+}
+
+func GetPrincipal(c *fiber.Ctx) (*Principal, bool) {
+	principal, ok := c.Locals(PrincipalKey).(*Principal)
+	return principal, ok
+}
@@ -0,0 +1,121 @@
+package utils
+
+import (
+	"errors"
+	"strings"
+)
+
+//fractional index / rank untuk mengurutkan card dan list tanpa menulis ulang seluruh array
+//rank adalah string base62 yang dibandingkan secara leksikografis (byte per byte)
+//di antara dua rank selalu bisa dibuat rank baru, contoh : RankBetween("V", "W") = "VV"
+
+const rankDigits = "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz"
+
+// MaxRankLength adalah panjang rank maksimal, rank yang lebih panjang membuat seluruh sibling di rebalance
+const MaxRankLength = 48
+
+var ErrInvalidRankRange = errors.New("rank range is invalid")
+
+// RankBetween membuat rank di antara before dan after
+// string kosong berarti tidak ada batas (awal / akhir list)
+func RankBetween(before, after string) (string, error) {
+	if !validRank(before) || !validRank(after) {
+		return "", ErrInvalidRankRange
+	}
+	if before != "" && after != "" && before >= after {
+		return "", ErrInvalidRankRange
+	}
+	return rankMidpoint(before, after), nil
+}
+
+// RankSequence membuat n rank dengan jarak yang sama, dipakai untuk rebalance / backfill
+func RankSequence(n int) []string {
+	if n <= 0 {
+		return []string{}
+	}
+
+	//panjang rank dipilih supaya jarak antar rank minimal 62 (masih ada ruang untuk satu digit)
+	length := 1
+	space := uint64(len(rankDigits))
+	for space < uint64(n+1)*uint64(len(rankDigits)) {
+		length++
+		space *= uint64(len(rankDigits))
+	}
+	step := space / uint64(n+1)
+
+	ranks := make([]string, n)
+	for i := range ranks {
+		ranks[i] = encodeRank((uint64(i)+1)*step, length)
+	}
+	return ranks
+}
+
+// rankMidpoint mengikuti algoritma fractional-indexing, before < after dan keduanya tidak diakhiri '0'
+func rankMidpoint(before, after string) string {
+	if after != "" {
+		//ambil prefix yang sama, '0' dianggap sebagai digit untuk before yang lebih pendek
+		n := 0
+		for n < len(after) && rankDigitAt(before, n) == after[n] {
+			n++
+		}
+		if n > 0 {
+			rest := ""
+			if n < len(before) {
+				rest = before[n:]
+			}
+			return after[:n] + rankMidpoint(rest, after[n:])
+		}
+	}
+
+	digitBefore := 0
+	if before != "" {
+		digitBefore = strings.IndexByte(rankDigits, before[0])
+	}
+	digitAfter := len(rankDigits)
+	if after != "" {
+		digitAfter = strings.IndexByte(rankDigits, after[0])
+	}
+
+	if digitAfter-digitBefore > 1 {
+		return string(rankDigits[(digitBefore+digitAfter)/2])
+	}
+
+	//digit nya berurutan, tambah satu digit di belakang
+	if after != "" && len(after) > 1 {
+		return after[:1]
+	}
+	rest := ""
+	if before != "" {
+		rest = before[1:]
+	}
+	return string(rankDigits[digitBefore]) + rankMidpoint(rest, "")
+}
+
+func rankDigitAt(rank string, i int) byte {
+	if i < len(rank) {
+		return rank[i]
+	}
+	return rankDigits[0]
+}
+
+// rank tidak boleh diakhiri '0' supaya selalu ada ruang di sebelum nya
+func validRank(rank string) bool {
+	if strings.HasSuffix(rank, string(rankDigits[0])) {
+		return false
+	}
+	for i := 0; i < len(rank); i++ {
+		if strings.IndexByte(rankDigits, rank[i]) < 0 {
+			return false
+		}
+	}
+	return true
+}
+
+func encodeRank(value uint64, length int) string {
+	buf := make([]byte, length)
+	for i := length - 1; i >= 0; i-- {
+		buf[i] = rankDigits[value%uint64(len(rankDigits))]
+		value /= uint64(len(rankDigits))
+	}
+	return strings.TrimRight(string(buf), string(rankDigits[0]))
+}
@@ -0,0 +1,116 @@
+package utils
+
+import (
+	"math/rand"
+	"sort"
+	"testing"
+)
+
+func TestRankBetween(t *testing.T) {
+	tests := []struct {
+		name   string
+		before string
+		after  string
+	}{
+		{name: "empty list", before: "", after: ""},
+		{name: "before first", before: "", after: "V"},
+		{name: "after last", before: "V", after: ""},
+		{name: "between far apart", before: "A", after: "z"},
+		{name: "between adjacent digits", before: "V", after: "W"},
+		{name: "between prefix", before: "V", after: "V1"},
+		{name: "after last digit", before: "z", after: ""},
+		{name: "before smallest", before: "", after: "1"},
+		{name: "different lengths", before: "Vz", after: "W"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := RankBetween(tt.before, tt.after)
+			if err != nil {
+				t.Fatalf("RankBetween() error = %v", err)
+			}
+			if tt.before != "" && got <= tt.before {
+				t.Errorf("RankBetween(%q, %q) = %q, want > before", tt.before, tt.after, got)
+			}
+			if tt.after != "" && got >= tt.after {
+				t.Errorf("RankBetween(%q, %q) = %q, want < after", tt.before, tt.after, got)
+			}
+		})
+	}
+}
+
+func TestRankBetween_Invalid(t *testing.T) {
+	invalid := [][2]string{
+		{"W", "V"},
+		{"V", "V"},
+		{"V0", ""},
+		{"V-", ""},
+	}
+
+	for _, tc := range invalid {
+		if _, err := RankBetween(tc[0], tc[1]); err == nil {
+			t.Errorf("RankBetween(%q, %q) expected error", tc[0], tc[1])
+		}
+	}
+}
+
+func TestRankBetween_RandomInserts(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	ranks := []string{}
+
+	for i := 0; i < 500; i++ {
+		index := rng.Intn(len(ranks) + 1)
+		before, after := "", ""
+		if index > 0 {
+			before = ranks[index-1]
+		}
+		if index < len(ranks) {
+			after = ranks[index]
+		}
+
+		rank, err := RankBetween(before, after)
+		if err != nil {
+			t.Fatalf("insert %d: RankBetween(%q, %q) error = %v", i, before, after, err)
+		}
+
+		ranks = append(ranks, "")
+		copy(ranks[index+1:], ranks[index:])
+		ranks[index] = rank
+	}
+
+	if !sort.StringsAreSorted(ranks) {
+		t.Fatal("ranks should stay sorted after random inserts")
+	}
+	for i := 1; i < len(ranks); i++ {
+		if ranks[i] == ranks[i-1] {
+			t.Fatalf("duplicate rank %q", ranks[i])
+		}
+	}
+}
+
+func TestRankSequence(t *testing.T) {
+	for _, n := range []int{0, 1, 2, 61, 62, 1000, 5000} {
+		ranks := RankSequence(n)
+		if len(ranks) != n {
+			t.Fatalf("RankSequence(%d) len = %d", n, len(ranks))
+		}
+		if !sort.StringsAreSorted(ranks) {
+			t.Errorf("RankSequence(%d) is not sorted", n)
+		}
+		for i, rank := range ranks {
+			if !validRank(rank) || rank == "" {
+				t.Fatalf("RankSequence(%d)[%d] = %q is not a valid rank", n, i, rank)
+			}
+			if i > 0 && ranks[i-1] == rank {
+				t.Fatalf("RankSequence(%d) has duplicate %q", n, rank)
+			}
+		}
+		//harus selalu ada ruang di antara dua rank hasil rebalance
+		for i := 1; i < len(ranks); i++ {
+			mid, err := RankBetween(ranks[i-1], ranks[i])
+			if err != nil || len(mid) > len(ranks[i])+1 {
+				t.Fatalf("no room between %q and %q: %q %v", ranks[i-1], ranks[i], mid, err)
+			}
+		}
+	}
+}
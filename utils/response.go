@@ -1,6 +1,11 @@
 package utils
 
-import "github.com/gofiber/fiber"
+import (
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/utils"
+)
 
 //Bentuk response yang kita harapkan
 //{
@@ -9,6 +14,8 @@ import "github.com/gofiber/fiber"
 //message : login successfull
 // data : {}
 //}
+//response error juga membawa error_code yang stabil supaya client tidak perlu parsing pesan error
+//dan details untuk error validasi per field
 
 type Response struct {
 	Status       string      `json:"status"`
@@ -16,6 +23,57 @@ type Response struct {
 	Message      string      `json:"message,omitempty"`
 	Data         interface{} `json:"data,omitempty"`
 	Error        string      `json:"error,omitempty"`
+	ErrorCode    string      `json:"error_code,omitempty"`
+	Details      interface{} `json:"details,omitempty"`
+}
+
+// error_code umum per status, service boleh memakai code yang lebih spesifik
+const (
+	CodeBadRequest          = "bad_request"
+	CodeUnauthorized        = "unauthorized"
+	CodeForbidden           = "forbidden"
+	CodeNotFound            = "not_found"
+	CodeConflict            = "conflict"
+	CodePayloadTooLarge     = "payload_too_large"
+	CodeRangeNotSatisfiable = "range_not_satisfiable"
+	CodeInternal            = "internal_error"
+	CodeEmailNotVerified    = "email_not_verified"
+)
+
+var errorStatus = map[int]struct {
+	status string
+	code   string
+}{
+	fiber.StatusBadRequest:                   {"Error Bad Request", CodeBadRequest},
+	fiber.StatusUnauthorized:                 {"Error Unauthorized", CodeUnauthorized},
+	fiber.StatusForbidden:                    {"Error Forbidden", CodeForbidden},
+	fiber.StatusNotFound:                     {"Error Not Found", CodeNotFound},
+	fiber.StatusConflict:                     {"Error Conflict", CodeConflict},
+	fiber.StatusRequestEntityTooLarge:        {"Error Payload Too Large", CodePayloadTooLarge},
+	fiber.StatusRequestedRangeNotSatisfiable: {"Error Range Not Satisfiable", CodeRangeNotSatisfiable},
+	fiber.StatusInternalServerError:          {"Error Internal Server", CodeInternal},
+}
+
+// Fail menulis response error dengan status apa saja, code kosong berarti pakai code umum dari status nya
+func Fail(c *fiber.Ctx, status int, code string, message string, err string, data interface{}, details interface{}) error {
+	text, ok := errorStatus[status]
+	if !ok {
+		text.status = "Error " + utils.StatusMessage(status)
+		text.code = strings.ReplaceAll(strings.ToLower(utils.StatusMessage(status)), " ", "_")
+	}
+	if code == "" {
+		code = text.code
+	}
+
+	return c.Status(status).JSON(Response{
+		Status:       text.status,
+		ResponseCode: status,
+		Message:      message,
+		Data:         data,
+		Error:        err,
+		ErrorCode:    code,
+		Details:      details,
+	})
 }
 
 func Success(c *fiber.Ctx, message string, data interface{}) error {
@@ -37,20 +95,14 @@ func Created(c *fiber.Ctx, message string, data interface{}) error {
 	})
 }
 
-func BadRequest(c *fiber.Ctx, message string, err string) error {
-	return c.Status(fiber.StatusBadRequest).JSON(Response{
-		Status:       "Error Bad Request",
-		ResponseCode: fiber.StatusBadRequest,
-		Message:      message,
-		Error:        err,
-	})
+func Unauthorized(c *fiber.Ctx, message string, err string) error {
+	return Fail(c, fiber.StatusUnauthorized, "", message, err, nil, nil)
 }
 
-func NotFound(c *fiber.Ctx, message string, err string) error {
-	return c.Status(fiber.StatusNotFound).JSON(Response{
-		Status:       "Error Not Found",
-		ResponseCode: fiber.StatusNotFound,
-		Message:      message,
-		Error:        err,
-	})
+func Forbidden(c *fiber.Ctx, message string, err string) error {
+	return Fail(c, fiber.StatusForbidden, "", message, err, nil, nil)
+}
+
+func RangeNotSatisfiable(c *fiber.Ctx, message string, err string) error {
+	return Fail(c, fiber.StatusRequestedRangeNotSatisfiable, "", message, err, nil, nil)
 }
@@ -0,0 +1,56 @@
+package utils
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/odink789/project-management/config"
+)
+
+//link download attachment di sign dengan HMAC dari JWT_SECRET
+//isi yang di sign : public id attachment + waktu expired (unix), jadi link tidak bisa dipakai untuk file lain
+
+var (
+	ErrInvalidSignature = errors.New("invalid download signature")
+	ErrSignatureExpired = errors.New("download link has expired")
+)
+
+// SignDownload membuat signature untuk link download yang berlaku selama DOWNLOAD_URL_EXPIRED
+func SignDownload(publicID string) (string, time.Time, error) {
+	duration := config.AppConfig.DownloadExpire
+	if duration == 0 {
+		return "", time.Time{}, fmt.Errorf("invalid download url expiry : %v", duration)
+	}
+
+	expiresAt := time.Now().Add(duration).Truncate(time.Second)
+	return downloadSignature(publicID, expiresAt.Unix()), expiresAt, nil
+}
+
+// VerifyDownload mengecek signature dan waktu expired dari query string link download
+func VerifyDownload(publicID string, expires string, signature string) error {
+	unix, err := strconv.ParseInt(expires, 10, 64)
+	if err != nil {
+		return ErrInvalidSignature
+	}
+
+	expected := downloadSignature(publicID, unix)
+	if !hmac.Equal([]byte(expected), []byte(signature)) {
+		return ErrInvalidSignature
+	}
+	if time.Now().Unix() > unix {
+		return ErrSignatureExpired
+	}
+	return nil
+}
+
+// prefix "download:" supaya signature ini tidak bisa dipakai di tempat lain yang memakai secret yang sama
+func downloadSignature(publicID string, expires int64) string {
+	mac := hmac.New(sha256.New, []byte(config.AppConfig.JWTSecret))
+	mac.Write([]byte("download:" + publicID + ":" + strconv.FormatInt(expires, 10)))
+	return hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,62 @@
+package utils
+
+import (
+	"errors"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/odink789/project-management/config"
+)
+
+func TestSignDownload_RoundTrip(t *testing.T) {
+	config.AppConfig = &config.Config{JWTSecret: "test-secret", DownloadExpire: 15 * time.Minute}
+
+	signature, expiresAt, err := SignDownload("attachment-1")
+	if err != nil {
+		t.Fatalf("SignDownload() error = %v", err)
+	}
+	if until := time.Until(expiresAt); until < 14*time.Minute || until > 15*time.Minute {
+		t.Errorf("expiresAt should be about 15m from now, got %v", until)
+	}
+
+	expires := strconv.FormatInt(expiresAt.Unix(), 10)
+	if err := VerifyDownload("attachment-1", expires, signature); err != nil {
+		t.Fatalf("VerifyDownload() error = %v", err)
+	}
+
+	later := strconv.FormatInt(expiresAt.Add(time.Hour).Unix(), 10)
+	cases := []struct {
+		name      string
+		id        string
+		expires   string
+		signature string
+	}{
+		{"other attachment", "attachment-2", expires, signature},
+		{"extended expiry", "attachment-1", later, signature},
+		{"tampered signature", "attachment-1", expires, signature[:len(signature)-1] + "0"},
+		{"invalid expires", "attachment-1", "soon", signature},
+	}
+	for _, tc := range cases {
+		if err := VerifyDownload(tc.id, tc.expires, tc.signature); !errors.Is(err, ErrInvalidSignature) {
+			t.Errorf("%s: error = %v, want ErrInvalidSignature", tc.name, err)
+		}
+	}
+
+	config.AppConfig.JWTSecret = "rotated-secret"
+	if err := VerifyDownload("attachment-1", expires, signature); !errors.Is(err, ErrInvalidSignature) {
+		t.Errorf("rotated secret error = %v, want ErrInvalidSignature", err)
+	}
+}
+
+func TestVerifyDownload_Expired(t *testing.T) {
+	config.AppConfig = &config.Config{JWTSecret: "test-secret", DownloadExpire: -time.Minute}
+
+	signature, expiresAt, err := SignDownload("attachment-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := VerifyDownload("attachment-1", strconv.FormatInt(expiresAt.Unix(), 10), signature); !errors.Is(err, ErrSignatureExpired) {
+		t.Errorf("error = %v, want ErrSignatureExpired", err)
+	}
+}